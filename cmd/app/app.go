@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	"wharehouse-control/internal/blob"
 	"wharehouse-control/internal/config"
 	"wharehouse-control/internal/handler"
 	"wharehouse-control/internal/middleware"
@@ -37,8 +39,13 @@ func Run() error {
 		log.Fatal("could not init db: " + err.Error())
 	}
 
+	blobs, err := blob.NewLocal(config.Cfg.Attachments.Dir)
+	if err != nil {
+		log.Fatal("could not init attachment storage: " + err.Error())
+	}
+
 	repository := repository.New(db)
-	service := service.New(repository)
+	service := service.New(repository, blobs)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -52,36 +59,177 @@ func Run() error {
 		cancel()
 	}()
 
+	go runPeriodically(ctx, time.Hour, "purged %d expired idempotency keys", repository.PurgeExpiredIdempotencyKeys)
+	go runPeriodically(ctx, config.Cfg.Reservations.SweepInterval, "released %d expired reservations", service.ExpireReservations)
+
 	router := gin.New()
 	handler := handler.New(ctx, service)
-	registerRoutes(router, handler)
+	registerRoutes(router, handler, repository)
 
 	zlog.Logger.Info().Msg("succesfully started server on " + config.Cfg.HttpServer.Address)
 	return router.Run(config.Cfg.HttpServer.Address)
 }
 
-func registerRoutes(engine *gin.Engine, handler *handler.Handler) {
+func registerRoutes(engine *gin.Engine, handler *handler.Handler, idempotencyStore middleware.IdempotencyStore) {
 	engine.LoadHTMLFiles("/app/static/login.html", "/app/static/main.html")
 	engine.Static("/static", "/app/static")
 
-	group := engine.Group("/", middleware.AuthMiddleware([]byte(config.Cfg.HttpServer.Secret))) // Register static files
+	group := engine.Group(
+		"/",
+		middleware.AuthMiddleware([]byte(config.Cfg.HttpServer.Secret)),
+		middleware.IdempotencyMiddleware(idempotencyStore, config.Cfg.Idempotency.TTL, config.Cfg.Idempotency.Lease),
+	)
 
 	// POST requests
 	group.POST("/items", handler.CreateItem)
+	group.POST("/categories", handler.CreateCategory)
+	group.POST("/categories/:id/attributes", handler.CreateAttributeDefinition)
+	group.POST("/units", handler.CreateUnit)
+	group.POST("/warehouses", handler.CreateWarehouse)
+	group.POST("/warehouses/:id/locations", handler.CreateLocation)
+	group.POST("/items/:id/lots", handler.CreateLot)
+	group.POST("/suppliers", handler.CreateSupplier)
+	group.POST("/purchase-orders", handler.CreatePurchaseOrder)
+	group.POST("/purchase-orders/from-low-stock", handler.CreateLowStockOrders)
+	group.POST("/purchase-orders/:id/lines", handler.AddPurchaseOrderLine)
+	group.POST("/purchase-orders/:id/send", handler.SendPurchaseOrder)
+	group.POST("/purchase-orders/:id/close", handler.ClosePurchaseOrder)
+	group.POST("/receipts", handler.CreateReceipt)
+	group.POST("/outbound-orders", handler.CreateOutboundOrder)
+	group.POST("/outbound-orders/:id/pick-list", handler.GeneratePickList)
+	group.POST("/outbound-orders/:id/pack", handler.PackOutboundOrder)
+	group.POST("/outbound-orders/:id/ship", handler.ShipOutboundOrder)
+	group.POST("/outbound-orders/:id/cancel", handler.CancelOutboundOrder)
+	group.POST("/transfer-orders", handler.CreateTransferOrder)
+	group.POST("/transfer-orders/:id/dispatch", handler.DispatchTransferOrder)
+	group.POST("/transfer-orders/:id/receive", handler.ReceiveTransferOrder)
+	group.POST("/transfer-orders/:id/cancel", handler.CancelTransferOrder)
+	group.POST("/stocktakes", handler.CreateStocktake)
+	group.POST("/stocktakes/:id/counts", handler.RecordStocktakeCounts)
+	group.POST("/stocktakes/:id/approve", handler.ApproveStocktake)
+	group.POST("/stocktakes/:id/cancel", handler.CancelStocktake)
+	group.POST("/change-requests/:id/approve", handler.ApproveChangeRequest)
+	group.POST("/change-requests/:id/reject", handler.RejectChangeRequest)
+	group.POST("/views", handler.CreateSavedView)
 	engine.POST("/users", handler.CreateUser)
 
 	// GET requests
 	engine.GET("/login", handler.GetLoginPage)
 	engine.GET("/main", handler.GetMainPage)
 	group.GET("/items", handler.GetAllItems)
+	group.GET("/items/:id", handler.GetItemByID)
 	group.GET("/users/history", handler.GetUsersWithChanges)
+	group.GET("/categories", handler.GetCategoryTree)
+	group.GET("/categories/:id", handler.GetCategoryByID)
+	group.GET("/categories/:id/attributes", handler.GetAttributeDefinitions)
+	group.GET("/units", handler.GetUnits)
+	group.GET("/items/:id/units", handler.GetItemUnits)
+	group.GET("/warehouses", handler.GetWarehouses)
+	group.GET("/warehouses/:id", handler.GetWarehouseByID)
+	group.GET("/warehouses/:id/locations", handler.GetLocations)
+	group.GET("/items/:id/locations", handler.GetItemLocations)
+	group.GET("/items/:id/movements", handler.GetMovements)
+	group.POST("/items/:id/adjust", handler.AdjustItem)
+	group.POST("/items/:id/restore", handler.RestoreItem)
+	group.POST("/items/:id/merge", handler.MergeItems)
+	group.POST("/items/:id/assemble", handler.AssembleKit)
+	group.POST("/items/:id/disassemble", handler.DisassembleKit)
+	group.POST("/items/:id/attachments", handler.UploadAttachment)
+	group.GET("/items/:id/attachments", handler.GetAttachments)
+	group.GET("/attachments/:id", handler.DownloadAttachment)
+	group.GET("/attachments/:id/thumbnail", handler.GetAttachmentThumbnail)
+	group.GET("/items/:id/components", handler.GetKit)
+	group.POST("/reservations", handler.CreateReservation)
+	group.POST("/reservations/:id/confirm", handler.ConfirmReservation)
+	group.POST("/reservations/:id/release", handler.ReleaseReservation)
+	group.GET("/reservations", handler.GetReservations)
+	group.GET("/items/:id/lots", handler.GetLots)
+	group.GET("/items/:id/fefo", handler.SuggestPick)
+	group.GET("/lots/expiring", handler.GetExpiringLots)
+	group.GET("/items/:id/serials", handler.GetSerials)
+	group.GET("/items/:id/serials/:serial", handler.GetSerialHistory)
+	group.GET("/items/:id/reorder-rules", handler.GetReorderRules)
+	group.GET("/items/low-stock", handler.GetLowStock)
+	group.GET("/items/duplicates", handler.GetDuplicateItems)
+	group.GET("/alerts", handler.GetStockAlerts)
+	group.POST("/alerts/:id/acknowledge", handler.AcknowledgeStockAlert)
+	group.GET("/suppliers", handler.GetSuppliers)
+	group.GET("/suppliers/:id", handler.GetSupplierByID)
+	group.GET("/purchase-orders", handler.GetPurchaseOrders)
+	group.GET("/purchase-orders/:id", handler.GetPurchaseOrderByID)
+	group.GET("/receipts", handler.GetReceipts)
+	group.GET("/receipts/:id", handler.GetReceiptByID)
+	group.GET("/outbound-orders", handler.GetOutboundOrders)
+	group.GET("/outbound-orders/:id", handler.GetOutboundOrderByID)
+	group.GET("/transfer-orders", handler.GetTransferOrders)
+	group.GET("/transfer-orders/:id", handler.GetTransferOrderByID)
+	group.GET("/stocktakes", handler.GetStocktakes)
+	group.GET("/stocktakes/:id", handler.GetStocktakeByID)
+	group.GET("/stocktakes/:id/variances", handler.GetStocktakeVariances)
+	group.GET("/change-requests", handler.GetChangeRequests)
+	group.GET("/change-requests/:id", handler.GetChangeRequestByID)
+	group.GET("/reports/valuation", handler.GetValuation)
+	group.GET("/tags", handler.GetTags)
+	group.GET("/views", handler.GetSavedViews)
+	group.GET("/views/:id", handler.GetSavedViewByID)
 
 	// PUT requests
 	group.PUT("/items/:id", handler.UpdateItem)
+	group.PUT("/categories/:id", handler.UpdateCategory)
+	group.PUT("/items/:id/units/:unit", handler.SetItemUnit)
+	group.PUT("/warehouses/:id", handler.UpdateWarehouse)
+	group.PUT("/items/:id/reorder-rules", handler.SetReorderRule)
+	group.PUT("/items/:id/components", handler.SetKitComponents)
+	group.PUT("/suppliers/:id", handler.UpdateSupplier)
+	group.PUT("/suppliers/:id/items/:item_id", handler.SetSupplierItem)
+	group.PUT("/items/:id/tags", handler.SetItemTags)
+	group.PUT("/views/:id", handler.UpdateSavedView)
 
 	// DELETE requests
 	group.DELETE("/items/:id", handler.DeleteItem)
+	group.DELETE("/items/:id/purge", handler.PurgeItem)
+	group.DELETE("/categories/:id", handler.DeleteCategory)
+	group.DELETE("/categories/:id/attributes/:attribute_id", handler.DeleteAttributeDefinition)
+	group.DELETE("/items/:id/units/:unit", handler.DeleteItemUnit)
+	group.DELETE("/warehouses/:id", handler.DeleteWarehouse)
+	group.DELETE("/warehouses/:id/locations/:location_id", handler.DeleteLocation)
+	group.DELETE("/items/:id/reorder-rules", handler.DeleteReorderRule)
+	group.DELETE("/suppliers/:id", handler.DeleteSupplier)
+	group.DELETE("/suppliers/:id/items/:item_id", handler.DeleteSupplierItem)
+	group.DELETE("/purchase-orders/:id/lines/:line_id", handler.DeletePurchaseOrderLine)
+	group.DELETE("/attachments/:id", handler.DeleteAttachment)
+	group.DELETE("/views/:id", handler.DeleteSavedView)
 
 	// swagger
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
+
+// defaultJobInterval is used for jobs whose interval is not set in config
+const defaultJobInterval = time.Minute
+
+// runPeriodically calls job every interval until ctx is cancelled,
+// job returns number of processed rows that is logged with format
+func runPeriodically(ctx context.Context, interval time.Duration, format string, job func(context.Context) (int64, error)) {
+	if interval <= 0 {
+		interval = defaultJobInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processed, err := job(ctx)
+			if err != nil {
+				zlog.Logger.Error().Msg(err.Error())
+				continue
+			}
+			if processed > 0 {
+				zlog.Logger.Info().Msgf(format, processed)
+			}
+		}
+	}
+}
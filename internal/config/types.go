@@ -1,8 +1,19 @@
 package config
 
+import "time"
+
 type Config struct {
-	Postgres   PostgresConfig   `mapstructure:"postgres"`
-	HttpServer HttpServerConfig `mapstructure:"http_server"`
+	Postgres     PostgresConfig     `mapstructure:"postgres"`
+	HttpServer   HttpServerConfig   `mapstructure:"http_server"`
+	Warehouse    WarehouseConfig    `mapstructure:"warehouse"`
+	Stock        StockConfig        `mapstructure:"stock"`
+	Idempotency  IdempotencyConfig  `mapstructure:"idempotency"`
+	Reservations ReservationsConfig `mapstructure:"reservations"`
+	Receiving    ReceivingConfig    `mapstructure:"receiving"`
+	Approvals    ApprovalsConfig    `mapstructure:"approvals"`
+	Valuation    ValuationConfig    `mapstructure:"valuation"`
+	Attachments  AttachmentsConfig  `mapstructure:"attachments"`
+	Naming       NamingConfig       `mapstructure:"naming"`
 }
 
 type PostgresConfig struct {
@@ -17,3 +28,73 @@ type HttpServerConfig struct {
 	Address string `mapstructure:"address"`
 	Secret  string
 }
+
+type WarehouseConfig struct {
+	// DefaultID is used when stock changing request has no warehouse_id
+	DefaultID int `mapstructure:"default_id"`
+}
+
+type StockConfig struct {
+	// AllowNegative lets stock adjustments take quantity below zero
+	AllowNegative bool `mapstructure:"allow_negative"`
+}
+
+type IdempotencyConfig struct {
+	// TTL is how long response is replayed for retried Idempotency-Key
+	TTL time.Duration `mapstructure:"ttl"`
+	// Lease is how long unfinished request holds its key, after that
+	// retry handles request again, it has to outlast the slowest request
+	Lease time.Duration `mapstructure:"lease"`
+}
+
+type ReservationsConfig struct {
+	// DefaultTTL is used when reservation is created without expires_at
+	DefaultTTL time.Duration `mapstructure:"default_ttl"`
+	// SweepInterval is how often expired reservations are released
+	SweepInterval time.Duration `mapstructure:"sweep_interval"`
+}
+
+type ReceivingConfig struct {
+	// OverDeliveryTolerance is share of ordered quantity that may be
+	// received above the order, e.g. 0.1 accepts up to 110%
+	OverDeliveryTolerance float64 `mapstructure:"over_delivery_tolerance"`
+}
+
+// ApprovalsConfig holds rules that turn change made by manager into
+// change request waiting for admin, zero threshold disables its rule
+type ApprovalsConfig struct {
+	// QuantityChangePercent is largest change of stock relative to
+	// current quantity that is applied without approval
+	QuantityChangePercent float64 `mapstructure:"quantity_change_percent"`
+	// QuantityChangeUnits is largest absolute change of stock in base
+	// unit of item that is applied without approval
+	QuantityChangeUnits    float64 `mapstructure:"quantity_change_units"`
+	RenameRequiresApproval bool    `mapstructure:"rename_requires_approval"`
+	DeleteRequiresApproval bool    `mapstructure:"delete_requires_approval"`
+}
+
+type ValuationConfig struct {
+	// Method values stock and cost of goods, fifo or average
+	Method string `mapstructure:"method"`
+}
+
+type AttachmentsConfig struct {
+	// Dir is root directory of local blob storage
+	Dir string `mapstructure:"dir"`
+	// MaxSize is largest accepted file in bytes
+	MaxSize int64 `mapstructure:"max_size"`
+	// ThumbnailSize is longest side of image thumbnail in pixels
+	ThumbnailSize int `mapstructure:"thumbnail_size"`
+	// AllowedTypes are content types detected from file contents
+	// that may be uploaded, images of them get thumbnails
+	AllowedTypes []string `mapstructure:"allowed_types"`
+}
+
+type NamingConfig struct {
+	// UniqueScope is where normalized item names must be unique:
+	// none, global, category or warehouse
+	UniqueScope string `mapstructure:"unique_scope"`
+	// SimilarityThreshold is lowest trigram similarity of names
+	// reported as possible duplicates, from 0 to 1
+	SimilarityThreshold float64 `mapstructure:"similarity_threshold"`
+}
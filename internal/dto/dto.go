@@ -1,8 +1,33 @@
 package dto
 
+import (
+	"io"
+	"time"
+)
+
 type CreateItem struct {
-	Name  string `json:"name" validate:"required"`
-	Count int    `json:"count" validate:"required"`
+	// UserID is recorded as author of the initial stock movement
+	UserID int     `json:"user_id"`
+	Name   string  `json:"name" validate:"required"`
+	Count  float64 `json:"count" validate:"required"`
+	// Unit is the base unit item quantity is stored in, pcs by default
+	Unit string `json:"unit"`
+	// CountUnit is the unit Count is given in, base unit by default
+	CountUnit string     `json:"count_unit"`
+	Units     []ItemUnit `json:"units" validate:"dive"`
+	// WarehouseID receives the initial quantity, default warehouse if omitted
+	WarehouseID int `json:"warehouse_id"`
+	// LocationID is an optional location inside the warehouse
+	LocationID *int           `json:"location_id"`
+	CategoryID *int           `json:"category_id"`
+	Attributes map[string]any `json:"attributes"`
+	// Serialized enables serial tracking, Serials then name every initial unit
+	Serialized bool     `json:"serialized"`
+	Serials    []string `json:"serials" validate:"dive,required,max=100"`
+	// UnitCost is cost of one CountUnit of initial stock
+	UnitCost *float64 `json:"unit_cost" validate:"omitempty,gte=0"`
+	// NameScope is taken from configuration, not from request
+	NameScope string `json:"-"`
 }
 
 type CreateUser struct {
@@ -11,8 +36,539 @@ type CreateUser struct {
 }
 
 type UpdateItem struct {
+	ID        int
+	UserID    int      `json:"user_id" validate:"required"`
+	Name      *string  `json:"name"`
+	Count     *float64 `json:"count"`
+	CountUnit string   `json:"count_unit"`
+	// WarehouseID is the warehouse Count is set for, default warehouse if omitted
+	WarehouseID int `json:"warehouse_id"`
+	// LocationID narrows Count down to a location inside the warehouse
+	LocationID *int           `json:"location_id"`
+	CategoryID *int           `json:"category_id"`
+	Attributes map[string]any `json:"attributes"`
+	// Version is the item version client has read, it is taken
+	// from If-Match header when present and holds new version after update
+	Version *int `json:"version"`
+	// Serialized can be switched only while item has no stock
+	Serialized *bool `json:"serialized"`
+	// Serials name units added or removed by Count change of serialized item
+	Serials []string `json:"serials" validate:"dive,required,max=100"`
+	// NameScope is taken from configuration, not from request
+	NameScope string `json:"-"`
+}
+
+// AdjustItem changes stock by signed Delta relative to current
+// quantity instead of setting it, so concurrent changes do not race
+type AdjustItem struct {
 	ID     int
 	UserID int     `json:"user_id" validate:"required"`
-	Name   *string `json:"name"`
-	Count  *int    `json:"count"`
+	Delta  float64 `json:"delta" validate:"required"`
+	Unit   string  `json:"unit"`
+	Reason string  `json:"reason" validate:"required,oneof=damage theft found correction sample"`
+	// Reference is free text like document number or comment
+	Reference   string `json:"reference"`
+	WarehouseID int    `json:"warehouse_id"`
+	LocationID  *int   `json:"location_id"`
+	// LotID is required to take stock of lot tracked item
+	LotID *int `json:"lot_id"`
+	// Serials name every unit moved, required for serialized item
+	Serials []string `json:"serials" validate:"dive,required,max=100"`
+	// UnitCost is cost of one Unit of stock that comes in, only
+	// for positive delta, current average cost is used if omitted
+	UnitCost *float64 `json:"unit_cost" validate:"omitempty,gte=0"`
+	// AllowNegative is taken from configuration, not from request
+	AllowNegative bool `json:"-"`
+}
+
+type CreateReservation struct {
+	ItemID   int     `json:"item_id" validate:"required"`
+	UserID   int     `json:"user_id" validate:"required"`
+	Quantity float64 `json:"quantity" validate:"required,gt=0"`
+	Unit     string  `json:"unit"`
+	// Reference identifies owner of reservation, e.g. sales order number
+	Reference   string `json:"reference" validate:"required,max=255"`
+	WarehouseID int    `json:"warehouse_id"`
+	// ExpiresAt defaults to reservations.default_ttl from now
+	ExpiresAt *time.Time `json:"expires_at"`
+	// Serials optionally pick exact units of serialized item to hold
+	Serials []string `json:"serials" validate:"dive,required,max=100"`
+}
+
+type ConfirmReservation struct {
+	ID     int
+	UserID int `json:"user_id" validate:"required"`
+	// LotID is the lot shipped stock is taken from
+	LotID *int `json:"lot_id"`
+	// Serials name shipped units when reservation has not held exact ones
+	Serials []string `json:"serials" validate:"dive,required,max=100"`
+}
+
+type SetReorderRule struct {
+	ItemID int
+	// WarehouseID limits rule to the warehouse, rule covers total stock without it
+	WarehouseID     *int     `json:"warehouse_id"`
+	MinLevel        float64  `json:"min_level" validate:"gte=0"`
+	MaxLevel        *float64 `json:"max_level" validate:"omitempty,gte=0"`
+	ReorderQuantity *float64 `json:"reorder_quantity" validate:"omitempty,gt=0"`
+}
+
+type CreateSupplier struct {
+	Name         string `json:"name" validate:"required,max=255"`
+	ContactName  string `json:"contact_name" validate:"max=255"`
+	Email        string `json:"email" validate:"omitempty,email,max=255"`
+	Phone        string `json:"phone" validate:"max=50"`
+	LeadTimeDays int    `json:"lead_time_days" validate:"gte=0"`
+}
+
+type UpdateSupplier struct {
+	ID           int
+	Name         *string `json:"name" validate:"omitempty,max=255"`
+	ContactName  *string `json:"contact_name" validate:"omitempty,max=255"`
+	Email        *string `json:"email" validate:"omitempty,email,max=255"`
+	Phone        *string `json:"phone" validate:"omitempty,max=50"`
+	LeadTimeDays *int    `json:"lead_time_days" validate:"omitempty,gte=0"`
+}
+
+type SetSupplierItem struct {
+	SupplierID  int
+	ItemID      int
+	SupplierSKU string  `json:"supplier_sku" validate:"max=100"`
+	Price       float64 `json:"price" validate:"gte=0"`
+	// MinOrderQuantity is in item base unit
+	MinOrderQuantity float64 `json:"min_order_quantity" validate:"gte=0"`
+}
+
+type PurchaseOrderLine struct {
+	ItemID   int     `json:"item_id" validate:"required"`
+	Quantity float64 `json:"quantity" validate:"required,gt=0"`
+	Unit     string  `json:"unit"`
+	// Price is taken from supplier price list when omitted
+	Price *float64 `json:"price" validate:"omitempty,gte=0"`
+}
+
+type CreatePurchaseOrder struct {
+	SupplierID int `json:"supplier_id" validate:"required"`
+	UserID     int `json:"user_id" validate:"required"`
+	// WarehouseID receives delivered items, default warehouse if omitted
+	WarehouseID int                 `json:"warehouse_id"`
+	Reference   string              `json:"reference" validate:"max=255"`
+	Lines       []PurchaseOrderLine `json:"lines" validate:"required,min=1,dive"`
+}
+
+type AddPurchaseOrderLine struct {
+	PurchaseOrderID int
+	PurchaseOrderLine
+}
+
+// CreateLowStockOrders orders low stock from suppliers, only
+// rules of the warehouse are used when WarehouseID is given
+type CreateLowStockOrders struct {
+	UserID      int  `json:"user_id" validate:"required"`
+	WarehouseID *int `json:"warehouse_id"`
+}
+
+type PurchaseOrderFilter struct {
+	SupplierID *int
+	Status     string
+}
+
+type ReceiptLine struct {
+	PurchaseOrderLineID int `json:"line_id" validate:"required"`
+	// Quantity is put into stock, DamagedQuantity arrived unusable and is rejected
+	Quantity        float64 `json:"quantity" validate:"gte=0"`
+	DamagedQuantity float64 `json:"damaged_quantity" validate:"gte=0"`
+	Unit            string  `json:"unit"`
+	Note            string  `json:"note" validate:"max=500"`
+	LotID           *int    `json:"lot_id"`
+	// Serials name every accepted unit of serialized item
+	Serials []string `json:"serials" validate:"dive,required,max=100"`
+	// ItemID is taken from order line
+	ItemID int `json:"-"`
+}
+
+type CreateReceipt struct {
+	PurchaseOrderID int `json:"purchase_order_id" validate:"required"`
+	UserID          int `json:"user_id" validate:"required"`
+	// LocationID is where accepted stock is put away
+	LocationID *int `json:"location_id"`
+	// Reference is e.g. delivery note number
+	Reference string        `json:"reference" validate:"max=255"`
+	Lines     []ReceiptLine `json:"lines" validate:"required,min=1,dive"`
+	// Close closes order even when some lines are under-delivered
+	Close bool `json:"close"`
+	// WarehouseID is taken from order, OverDeliveryTolerance from configuration
+	WarehouseID           int     `json:"-"`
+	OverDeliveryTolerance float64 `json:"-"`
+}
+
+type ReceiptFilter struct {
+	PurchaseOrderID *int
+}
+
+type OutboundOrderLine struct {
+	ItemID   int     `json:"item_id" validate:"required"`
+	Quantity float64 `json:"quantity" validate:"required,gt=0"`
+	Unit     string  `json:"unit"`
+}
+
+type CreateOutboundOrder struct {
+	UserID int `json:"user_id" validate:"required"`
+	// WarehouseID ships the order, default warehouse if omitted
+	WarehouseID int                 `json:"warehouse_id"`
+	Customer    string              `json:"customer" validate:"required,max=255"`
+	Reference   string              `json:"reference" validate:"max=255"`
+	Lines       []OutboundOrderLine `json:"lines" validate:"required,min=1,dive"`
+}
+
+type PackedPick struct {
+	PickID int `json:"pick_id" validate:"required"`
+	// Serials name packed units of serialized item
+	Serials []string `json:"serials" validate:"dive,required,max=100"`
+	// LocationID tells where pick without location, such as a lot
+	// pick, was taken from
+	LocationID *int `json:"location_id"`
+}
+
+type PackOutboundOrder struct {
+	ID           int
+	PackageCount int          `json:"package_count" validate:"gte=0"`
+	Picks        []PackedPick `json:"picks" validate:"dive"`
+}
+
+type ShipOutboundOrder struct {
+	ID             int
+	UserID         int    `json:"user_id" validate:"required"`
+	Carrier        string `json:"carrier" validate:"max=100"`
+	TrackingNumber string `json:"tracking_number" validate:"max=100"`
+}
+
+type OutboundOrderFilter struct {
+	WarehouseID *int
+	Status      string
+}
+
+type TransferOrderLine struct {
+	ItemID   int     `json:"item_id" validate:"required"`
+	Quantity float64 `json:"quantity" validate:"required,gt=0"`
+	Unit     string  `json:"unit"`
+	LotID    *int    `json:"lot_id"`
+	// Serials name units of serialized item to move
+	Serials []string `json:"serials" validate:"dive,required,max=100"`
+}
+
+type CreateTransferOrder struct {
+	UserID                 int                 `json:"user_id" validate:"required"`
+	SourceWarehouseID      int                 `json:"source_warehouse_id" validate:"required"`
+	DestinationWarehouseID int                 `json:"destination_warehouse_id" validate:"required,nefield=SourceWarehouseID"`
+	Reference              string              `json:"reference" validate:"max=255"`
+	Lines                  []TransferOrderLine `json:"lines" validate:"required,min=1,dive"`
+}
+
+type DispatchTransferOrder struct {
+	ID     int
+	UserID int `json:"user_id" validate:"required"`
+}
+
+type ReceiveTransferLine struct {
+	LineID   int     `json:"line_id" validate:"required"`
+	Quantity float64 `json:"quantity" validate:"gte=0"`
+	Unit     string  `json:"unit"`
+	// Serials name units that arrived, all dispatched units if omitted
+	Serials []string `json:"serials" validate:"dive,required,max=100"`
+	Note    string   `json:"note" validate:"max=1000"`
+}
+
+type ReceiveTransferOrder struct {
+	ID         int
+	UserID     int  `json:"user_id" validate:"required"`
+	LocationID *int `json:"location_id"`
+	// Lines record what arrived, lines not listed arrived in full
+	Lines []ReceiveTransferLine `json:"lines" validate:"dive"`
+}
+
+type TransferOrderFilter struct {
+	// WarehouseID matches both source and destination
+	WarehouseID *int
+	Status      string
+}
+
+type CreateStocktake struct {
+	UserID      int `json:"user_id" validate:"required"`
+	WarehouseID int `json:"warehouse_id"`
+	// LocationID narrows stocktake to location and everything under it
+	LocationID *int `json:"location_id"`
+	// CategoryID narrows stocktake to items of category and its subcategories
+	CategoryID *int   `json:"category_id"`
+	Note       string `json:"note" validate:"max=1000"`
+}
+
+type StocktakeCount struct {
+	ItemID int `json:"item_id" validate:"required"`
+	// LocationID is omitted for stock that is not put away
+	LocationID *int    `json:"location_id"`
+	Quantity   float64 `json:"quantity" validate:"gte=0"`
+	Unit       string  `json:"unit"`
+}
+
+type RecordStocktakeCounts struct {
+	ID     int
+	UserID int              `json:"user_id" validate:"required"`
+	Counts []StocktakeCount `json:"counts" validate:"required,min=1,dive"`
+}
+
+type ApproveStocktake struct {
+	ID     int
+	UserID int `json:"user_id" validate:"required"`
+}
+
+type StocktakeFilter struct {
+	WarehouseID *int
+	Status      string
+}
+
+type KitComponent struct {
+	ItemID   int     `json:"item_id" validate:"required"`
+	Quantity float64 `json:"quantity" validate:"gt=0"`
+	// Unit is the unit Quantity is given in, base unit of component by default
+	Unit string `json:"unit"`
+}
+
+// SetKitComponents replaces bill of materials of kit,
+// empty Components turn kit back into plain item
+type SetKitComponents struct {
+	KitID      int
+	Components []KitComponent `json:"components" validate:"dive"`
+}
+
+// AssembleKit builds Quantity kits from component stock or, when
+// Disassemble is set, takes them apart back into components
+type AssembleKit struct {
+	KitID       int
+	UserID      int `json:"user_id" validate:"required"`
+	WarehouseID int `json:"warehouse_id"`
+	// LocationID is where components are taken from and kits are put to
+	LocationID  *int    `json:"location_id"`
+	Quantity    float64 `json:"quantity" validate:"gt=0"`
+	Reference   string  `json:"reference" validate:"max=255"`
+	Disassemble bool    `json:"-"`
+}
+
+type CreateChangeRequest struct {
+	ItemID   int
+	Kind     string
+	Changes  []byte
+	Original []byte
+	Version  int
+	Reasons  []string
+	UserID   *int
+}
+
+// ItemOriginal holds values of item fields changed by update request as
+// they were when request was made, approval compares them with item
+// instead of its version, so stock movements in the meantime do not
+// outdate request unless they touch the quantity being set
+type ItemOriginal struct {
+	Name       *string        `json:"name,omitempty"`
+	CategoryID *int           `json:"category_id,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Serialized *bool          `json:"serialized,omitempty"`
+	// Quantity is stock at warehouse or location Count is set for
+	Quantity *float64 `json:"quantity,omitempty"`
+}
+
+// ReviewChangeRequest approves or rejects pending change request
+type ReviewChangeRequest struct {
+	ID      int
+	UserID  int    `json:"user_id" validate:"required"`
+	Comment string `json:"comment" validate:"max=1000"`
+}
+
+// ApproveChangeRequest is review of change request together with change
+// prepared by service for it, only the change of request kind is set
+type ApproveChangeRequest struct {
+	Review     ReviewChangeRequest
+	UpdateItem *UpdateItem
+	// Original comes with UpdateItem
+	Original   *ItemOriginal
+	AdjustItem *AdjustItem
+	DeleteItem *DeleteItem
+}
+
+// DeleteItem archives item on behalf of UserID, item of any version
+// is archived when Version is nil
+type DeleteItem struct {
+	ID      int
+	Version *int
+	UserID  *int
+}
+
+type ChangeRequestFilter struct {
+	ItemID *int
+	Status string
+}
+
+type SerialFilter struct {
+	ItemID int
+	Status string
+}
+
+type CreateLot struct {
+	ItemID         int
+	LotNumber      string  `json:"lot_number" validate:"required,max=100"`
+	ManufacturedAt *string `json:"manufactured_at" validate:"omitempty,datetime=2006-01-02"`
+	ExpiresAt      *string `json:"expires_at" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type PickRequest struct {
+	ItemID      int
+	WarehouseID int
+	Quantity    float64
+	Unit        string
+}
+
+type ReservationFilter struct {
+	ItemID *int
+	Status string
+}
+
+type MovementFilter struct {
+	ItemID      int
+	WarehouseID *int
+	Limit       int
+}
+
+// UploadAttachment is file received for item, Content is read
+// by service up to configured size limit
+type UploadAttachment struct {
+	ItemID   int
+	UserID   *int
+	FileName string
+	Content  io.Reader
+}
+
+// CreateAttachment is attachment record of file already put to blob storage
+type CreateAttachment struct {
+	ItemID       int
+	Kind         string
+	FileName     string
+	ContentType  string
+	Size         int64
+	StorageKey   string
+	ThumbnailKey *string
+	UserID       *int
+}
+
+// ValuationFilter values stock on hand at the end of AsOf day,
+// Method is taken from configuration when query omits it
+type ValuationFilter struct {
+	AsOf        time.Time
+	WarehouseID *int
+	CategoryID  *int
+	Method      string
+}
+
+type ItemFilter struct {
+	CategoryID  *int
+	WarehouseID *int
+	// Attributes holds exact matches on custom attribute values
+	Attributes map[string]string
+	// IncludeArchived lists deleted items along with active ones
+	IncludeArchived bool
+	// Tags keeps items labeled with every one of them
+	Tags []string
+	// ViewID applies saved view of UserID, other fields override it
+	ViewID *int
+	UserID *int
+}
+
+// DuplicateFilter lists pairs of items with similar names,
+// only pairs with ItemID when it is set
+type DuplicateFilter struct {
+	ItemID    *int
+	Threshold float64
+	Limit     int
+}
+
+// MergeItems moves stock, history and everything else of source
+// item to target item and archives source
+type MergeItems struct {
+	SourceID int
+	TargetID int `json:"into_id" validate:"required"`
+	UserID   int `json:"user_id" validate:"required"`
+}
+
+// SetItemTags replaces all tags of item
+type SetItemTags struct {
+	ItemID int
+	Tags   []string `json:"tags" validate:"max=20,dive,required,max=50"`
+}
+
+// ViewFilter is item list filter kept in saved view
+type ViewFilter struct {
+	CategoryID      *int              `json:"category_id,omitempty"`
+	WarehouseID     *int              `json:"warehouse_id,omitempty"`
+	Tags            []string          `json:"tags,omitempty" validate:"max=20,dive,required,max=50"`
+	Attributes      map[string]string `json:"attributes,omitempty" validate:"max=20"`
+	IncludeArchived bool              `json:"include_archived,omitempty"`
+}
+
+// SaveView creates saved view or replaces name and filter of it
+type SaveView struct {
+	ID     int
+	UserID int        `json:"user_id" validate:"required"`
+	Name   string     `json:"name" validate:"required,max=100"`
+	Filter ViewFilter `json:"filter"`
+}
+
+type CreateCategory struct {
+	Name     string `json:"name" validate:"required"`
+	ParentID *int   `json:"parent_id"`
+}
+
+type UpdateCategory struct {
+	ID       int
+	Name     *string `json:"name"`
+	ParentID *int    `json:"parent_id"`
+}
+
+type CreateAttributeDefinition struct {
+	CategoryID    int
+	Name          string   `json:"name" validate:"required"`
+	Type          string   `json:"type" validate:"required,oneof=string number boolean"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values"`
+}
+
+type CreateUnit struct {
+	Code         string `json:"code" validate:"required,max=16"`
+	Name         string `json:"name" validate:"required"`
+	AllowDecimal bool   `json:"allow_decimal"`
+}
+
+type ItemUnit struct {
+	Unit   string  `json:"unit" validate:"required"`
+	Factor float64 `json:"factor" validate:"required,gt=0"`
+}
+
+type SetItemUnit struct {
+	Factor float64 `json:"factor" validate:"required,gt=0"`
+}
+
+type CreateWarehouse struct {
+	Name    string `json:"name" validate:"required"`
+	Address string `json:"address"`
+}
+
+type UpdateWarehouse struct {
+	ID      int
+	Name    *string `json:"name"`
+	Address *string `json:"address"`
+}
+
+type CreateLocation struct {
+	WarehouseID int
+	ParentID    *int   `json:"parent_id"`
+	Type        string `json:"type" validate:"required,oneof=zone aisle rack bin"`
+	Code        string `json:"code" validate:"required,max=32"`
 }
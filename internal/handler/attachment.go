@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/config"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// multipartOverhead is room left in upload body for form
+// boundaries, part headers and user_id field
+const multipartOverhead = 64 << 10
+
+// @Summary Upload attachment
+// @Description Upload image or document of item as multipart form, content type is detected from file contents and checked against allowed types and size limit, images get thumbnail, requires admin or manager authentication
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param file formData file true "Image or document"
+// @Param user_id formData int false "User uploading file"
+// @Success 201 {object} model.Attachment
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 413 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/attachments [post]
+// @Security BearerAuth
+func (h *Handler) UploadAttachment(c *ginext.Context) {
+	itemID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	// body is cut before multipart form is parsed, so too large
+	// upload is not spooled to disk just to be refused later
+	if maxSize := config.Cfg.Attachments.MaxSize; maxSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid file: " + err.Error())
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			tooLarge := fmt.Errorf("%w: limit is %d bytes", service.ErrAttachmentTooLarge, config.Cfg.Attachments.MaxSize)
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": tooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	upload := dto.UploadAttachment{ItemID: itemID, FileName: fileHeader.Filename}
+	if userParam := c.PostForm("user_id"); userParam != "" {
+		userID, err := strconv.Atoi(userParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid user id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		upload.UserID = &userID
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	upload.Content = file
+
+	attachment, err := h.service.UploadAttachment(h.ctx, &upload)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and uploaded attachment")
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// @Summary Get item attachments
+// @Description Get images and documents attached to item, requires authentication
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Item ID"
+// @Success 200 {array} model.Attachment
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/attachments [get]
+// @Security BearerAuth
+func (h *Handler) GetAttachments(c *ginext.Context) {
+	itemID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	attachments, err := h.service.GetAttachments(h.ctx, itemID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned attachments")
+	c.JSON(http.StatusOK, attachments)
+}
+
+// @Summary Download attachment
+// @Description Download file of attachment with its content type, images are shown inline and documents are downloaded, requires authentication
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /attachments/{id} [get]
+// @Security BearerAuth
+func (h *Handler) DownloadAttachment(c *ginext.Context) {
+	h.sendAttachment(c, false)
+}
+
+// @Summary Get attachment thumbnail
+// @Description Get png thumbnail of image attachment, documents have no thumbnail, requires authentication
+// @Tags attachments
+// @Produce png
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /attachments/{id}/thumbnail [get]
+// @Security BearerAuth
+func (h *Handler) GetAttachmentThumbnail(c *ginext.Context) {
+	h.sendAttachment(c, true)
+}
+
+// sendAttachment streams file of attachment or its thumbnail
+func (h *Handler) sendAttachment(c *ginext.Context, thumbnail bool) {
+	attachmentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment id or was not provided"})
+		return
+	}
+
+	attachment, file, err := h.service.OpenAttachment(h.ctx, attachmentID, thumbnail)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	if thumbnail {
+		zlog.Logger.Info().Msg("successfully handled request and returned attachment thumbnail")
+		c.DataFromReader(http.StatusOK, -1, "image/png", file, nil)
+		return
+	}
+
+	disposition := "attachment"
+	if attachment.Kind == model.AttachmentKindImage {
+		disposition = "inline"
+	}
+	headers := map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned attachment")
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, file, headers)
+}
+
+// @Summary Delete attachment
+// @Description Delete attachment with its file and thumbnail, requires admin or manager authentication
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /attachments/{id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteAttachment(c *ginext.Context) {
+	attachmentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment id or was not provided"})
+		return
+	}
+
+	if err := h.service.DeleteAttachment(h.ctx, attachmentID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted attachment")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted attachment"})
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+	"wharehouse-control/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_UploadAttachment(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		fileName         string
+		content          string
+		mockFunc         func(ctx context.Context, upload *dto.UploadAttachment) (*model.Attachment, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:     "success",
+			paramID:  "1",
+			fileName: "spec.pdf",
+			content:  "%PDF-1.4 spec sheet",
+			mockFunc: func(ctx context.Context, upload *dto.UploadAttachment) (*model.Attachment, error) {
+				data, err := io.ReadAll(upload.Content)
+				if err != nil {
+					return nil, err
+				}
+				return &model.Attachment{
+					ID:          5,
+					ItemID:      upload.ItemID,
+					Kind:        model.AttachmentKindDocument,
+					FileName:    upload.FileName,
+					ContentType: "application/pdf",
+					Size:        int64(len(data)),
+				}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResponse: map[string]interface{}{
+				"id":        float64(5),
+				"file_name": "spec.pdf",
+				"size":      float64(19),
+			},
+		},
+		{
+			name:           "no file",
+			paramID:        "1",
+			fileName:       "",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "file is required",
+			},
+		},
+		{
+			name:     "content type not allowed",
+			paramID:  "1",
+			fileName: "run.exe",
+			content:  "MZ",
+			mockFunc: func(ctx context.Context, upload *dto.UploadAttachment) (*model.Attachment, error) {
+				return nil, fmt.Errorf("%w: content type application/octet-stream is not allowed", service.ErrInvalidAttachment)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid attachment: content type application/octet-stream is not allowed",
+			},
+		},
+		{
+			name:     "too large",
+			paramID:  "1",
+			fileName: "photo.jpg",
+			content:  "large",
+			mockFunc: func(ctx context.Context, upload *dto.UploadAttachment) (*model.Attachment, error) {
+				return nil, fmt.Errorf("%w: limit is 10485760 bytes", service.ErrAttachmentTooLarge)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedResponse: map[string]interface{}{
+				"error": "attachment is too large: limit is 10485760 bytes",
+			},
+		},
+		{
+			name:           "body over limit",
+			paramID:        "1",
+			fileName:       "photo.jpg",
+			content:        strings.Repeat("a", 10485760+multipartOverhead),
+			mockFunc:       nil,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedResponse: map[string]interface{}{
+				"error": "attachment is too large: limit is 10485760 bytes",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			if tt.fileName != "" {
+				part, _ := writer.CreateFormFile("file", tt.fileName)
+				part.Write([]byte(tt.content))
+			}
+			writer.WriteField("user_id", "1")
+			writer.Close()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/items/"+tt.paramID+"/attachments", &body)
+			c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				uploadAttachmentFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.UploadAttachment(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_DownloadAttachment(t *testing.T) {
+	tests := []struct {
+		name                string
+		paramID             string
+		mockFunc            func(ctx context.Context, id int, thumbnail bool) (*model.Attachment, io.ReadCloser, error)
+		expectedStatus      int
+		expectedBody        string
+		expectedDisposition string
+	}{
+		{
+			name:    "success",
+			paramID: "5",
+			mockFunc: func(ctx context.Context, id int, thumbnail bool) (*model.Attachment, io.ReadCloser, error) {
+				return &model.Attachment{
+					ID:          id,
+					Kind:        model.AttachmentKindDocument,
+					FileName:    "spec.pdf",
+					ContentType: "application/pdf",
+					Size:        8,
+				}, io.NopCloser(strings.NewReader("%PDF-1.4")), nil
+			},
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "%PDF-1.4",
+			expectedDisposition: `attachment; filename=spec.pdf`,
+		},
+		{
+			name:    "not found",
+			paramID: "99",
+			mockFunc: func(ctx context.Context, id int, thumbnail bool) (*model.Attachment, io.ReadCloser, error) {
+				return nil, nil, repository.ErrNoSuchAttachment
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/attachments/"+tt.paramID, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				openAttachmentFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.DownloadAttachment(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if w.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, w.Body.String())
+			}
+			if disposition := w.Header().Get("Content-Disposition"); disposition != tt.expectedDisposition {
+				t.Errorf("expected disposition %q, got %q", tt.expectedDisposition, disposition)
+			}
+		})
+	}
+}
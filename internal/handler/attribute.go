@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Define a category attribute
+// @Description Add a custom attribute definition to category schema, items of the category and its subcategories are validated against it, requires admin authentication
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param attribute body dto.CreateAttributeDefinition true "Attribute definition"
+// @Success 200 {object} model.AttributeDefinition
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /categories/{id}/attributes [post]
+// @Security BearerAuth
+func (h *Handler) CreateAttributeDefinition(c *ginext.Context) {
+	id := c.Param("id")
+	categoryID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id or was not provided"})
+		return
+	}
+
+	var createDefinition dto.CreateAttributeDefinition
+	if err := c.BindJSON(&createDefinition); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	createDefinition.CategoryID = categoryID
+
+	if err := validator.Validate.Struct(createDefinition); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	definition, err := h.service.CreateAttributeDefinition(h.ctx, createDefinition)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created attribute definition")
+	c.JSON(http.StatusOK, definition)
+}
+
+// @Summary Get category attributes
+// @Description Get attribute definitions of category including inherited from parent categories, requires authentication
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {array} model.AttributeDefinition
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /categories/{id}/attributes [get]
+// @Security BearerAuth
+func (h *Handler) GetAttributeDefinitions(c *ginext.Context) {
+	id := c.Param("id")
+	categoryID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id or was not provided"})
+		return
+	}
+
+	definitions, err := h.service.GetAttributeDefinitions(h.ctx, categoryID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned attribute definitions")
+	c.JSON(http.StatusOK, definitions)
+}
+
+// @Summary Delete a category attribute
+// @Description Delete attribute definition from category schema, requires admin authentication
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param attribute_id path int true "Attribute definition ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /categories/{id}/attributes/{attribute_id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteAttributeDefinition(c *ginext.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id or was not provided"})
+		return
+	}
+
+	attributeID, err := strconv.Atoi(c.Param("attribute_id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attribute id or was not provided"})
+		return
+	}
+
+	err = h.service.DeleteAttributeDefinition(h.ctx, categoryID, attributeID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted attribute definition")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted attribute definition"})
+}
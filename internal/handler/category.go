@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Create a new category
+// @Description Create a new category, optionally nested under parent category, requires admin authentication
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body dto.CreateCategory true "Category creation data"
+// @Success 200 {object} model.Category
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /categories [post]
+// @Security BearerAuth
+func (h *Handler) CreateCategory(c *ginext.Context) {
+	var createCategory dto.CreateCategory
+
+	if err := c.BindJSON(&createCategory); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createCategory); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	category, err := h.service.CreateCategory(h.ctx, createCategory)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new category")
+	c.JSON(http.StatusOK, category)
+}
+
+// @Summary Get category tree
+// @Description Get all categories as a tree of root categories with nested children, requires authentication
+// @Tags categories
+// @Produce json
+// @Success 200 {array} model.Category
+// @Failure 500 {object} gin.H
+// @Router /categories [get]
+// @Security BearerAuth
+func (h *Handler) GetCategoryTree(c *ginext.Context) {
+	categories, err := h.service.GetCategoryTree(h.ctx)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned category tree")
+	c.JSON(http.StatusOK, categories)
+}
+
+// @Summary Get category
+// @Description Get category by ID, requires authentication
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} model.Category
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /categories/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetCategoryByID(c *ginext.Context) {
+	id := c.Param("id")
+	categoryID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id or was not provided"})
+		return
+	}
+
+	category, err := h.service.GetCategoryByID(h.ctx, categoryID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned category")
+	c.JSON(http.StatusOK, category)
+}
+
+// @Summary Update a category
+// @Description Rename category or move it under another parent (parent_id 0 moves it to the root), requires admin or manager authentication
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param category body dto.UpdateCategory true "Update data"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /categories/{id} [put]
+// @Security BearerAuth
+func (h *Handler) UpdateCategory(c *ginext.Context) {
+	id := c.Param("id")
+	categoryID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id or was not provided"})
+		return
+	}
+
+	var updateCategory dto.UpdateCategory
+	if err := c.BindJSON(&updateCategory); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updateCategory.ID = categoryID
+
+	err = h.service.UpdateCategory(h.ctx, &updateCategory)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and updated category")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully updated category"})
+}
+
+// @Summary Delete a category
+// @Description Delete a category without subcategories, its items become uncategorized, requires admin authentication
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /categories/{id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteCategory(c *ginext.Context) {
+	id := c.Param("id")
+	categoryID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id or was not provided"})
+		return
+	}
+
+	err = h.service.DeleteCategory(h.ctx, categoryID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted category")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted category"})
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_CreateCategory(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		mockFunc         func(ctx context.Context, createCategory dto.CreateCategory) (*model.Category, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "success",
+			body: `{"name":"fasteners","parent_id":1}`,
+			mockFunc: func(ctx context.Context, createCategory dto.CreateCategory) (*model.Category, error) {
+				return &model.Category{ID: 2, Name: "fasteners", ParentID: createCategory.ParentID, CreatedAt: time.Now()}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"id":        float64(2),
+				"name":      "fasteners",
+				"parent_id": float64(1),
+			},
+		},
+		{
+			name:           "validation error",
+			body:           `{"parent_id":1}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Name is required",
+			},
+		},
+		{
+			name: "no such parent",
+			body: `{"name":"fasteners","parent_id":42}`,
+			mockFunc: func(ctx context.Context, createCategory dto.CreateCategory) (*model.Category, error) {
+				return nil, repository.ErrNoSuchCategory
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchCategory.Error(),
+			},
+		},
+		{
+			name: "service error",
+			body: `{"name":"fasteners"}`,
+			mockFunc: func(ctx context.Context, createCategory dto.CreateCategory) (*model.Category, error) {
+				return nil, errors.New("service error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"error": "service error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/categories", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			mock := &mockService{
+				createCategoryFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.CreateCategory(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_UpdateCategory(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, updateCategory *dto.UpdateCategory) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "2",
+			body:    `{"parent_id":0}`,
+			mockFunc: func(ctx context.Context, updateCategory *dto.UpdateCategory) error {
+				if updateCategory.ID != 2 {
+					return errors.New("unexpected id")
+				}
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully updated category",
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			body:           `{}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid category id or was not provided",
+			},
+		},
+		{
+			name:    "cycle",
+			paramID: "1",
+			body:    `{"parent_id":3}`,
+			mockFunc: func(ctx context.Context, updateCategory *dto.UpdateCategory) error {
+				return repository.ErrCategoryCycle
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrCategoryCycle.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/categories/"+tt.paramID, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				updateCategoryFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.UpdateCategory(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
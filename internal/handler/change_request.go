@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Get change requests
+// @Description Get item changes made by managers that wait for or were given admin review, requires authentication
+// @Tags change-requests
+// @Produce json
+// @Param item_id query int false "Only change requests of this item"
+// @Param status query string false "Only change requests in this status: pending, approved or rejected"
+// @Success 200 {array} model.ChangeRequest
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /change-requests [get]
+// @Security BearerAuth
+func (h *Handler) GetChangeRequests(c *ginext.Context) {
+	var filter dto.ChangeRequestFilter
+	if itemParam := c.Query("item_id"); itemParam != "" {
+		itemID, err := strconv.Atoi(itemParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid item id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+			return
+		}
+		filter.ItemID = &itemID
+	}
+
+	switch status := c.Query("status"); status {
+	case "", model.ChangeRequestStatusPending, model.ChangeRequestStatusApproved, model.ChangeRequestStatusRejected:
+		filter.Status = status
+	default:
+		zlog.Logger.Error().Msg("invalid change request status: " + status)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid change request status"})
+		return
+	}
+
+	changeRequests, err := h.service.GetChangeRequests(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned change requests")
+	c.JSON(http.StatusOK, changeRequests)
+}
+
+// @Summary Get change request
+// @Description Get change request by ID with requested changes and rules they broke, requires authentication
+// @Tags change-requests
+// @Produce json
+// @Param id path int true "Change request ID"
+// @Success 200 {object} model.ChangeRequest
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /change-requests/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetChangeRequestByID(c *ginext.Context) {
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid change request id or was not provided"})
+		return
+	}
+
+	changeRequest, err := h.service.GetChangeRequestByID(h.ctx, requestID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned change request")
+	c.JSON(http.StatusOK, changeRequest)
+}
+
+// @Summary Approve a change request
+// @Description Apply pending item change, update whose changed fields were changed by someone else after the request fails with conflict, requires admin authentication
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Change request ID"
+// @Param review body dto.ReviewChangeRequest true "Review data"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /change-requests/{id}/approve [post]
+// @Security BearerAuth
+func (h *Handler) ApproveChangeRequest(c *ginext.Context) {
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid change request id or was not provided"})
+		return
+	}
+
+	var review dto.ReviewChangeRequest
+	if err := c.BindJSON(&review); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	review.ID = requestID
+
+	if err := validator.Validate.Struct(review); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.service.ApproveChangeRequest(h.ctx, review); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), errorBody(err))
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and approved change request")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully approved change request"})
+}
+
+// @Summary Reject a change request
+// @Description Close pending change request leaving item untouched, requires admin authentication
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Change request ID"
+// @Param review body dto.ReviewChangeRequest true "Review data"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /change-requests/{id}/reject [post]
+// @Security BearerAuth
+func (h *Handler) RejectChangeRequest(c *ginext.Context) {
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid change request id or was not provided"})
+		return
+	}
+
+	var review dto.ReviewChangeRequest
+	if err := c.BindJSON(&review); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	review.ID = requestID
+
+	if err := validator.Validate.Struct(review); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.service.RejectChangeRequest(h.ctx, review); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and rejected change request")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully rejected change request"})
+}
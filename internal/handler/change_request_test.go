@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_UpdateItemByManager(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		requestFunc      func(ctx context.Context, updateItem *dto.UpdateItem) (*model.ChangeRequest, error)
+		updateFunc       func(ctx context.Context, updateItem *dto.UpdateItem) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "large count change waits for approval",
+			body: `{"user_id":2,"count":500,"version":3}`,
+			requestFunc: func(ctx context.Context, updateItem *dto.UpdateItem) (*model.ChangeRequest, error) {
+				return &model.ChangeRequest{
+					ID:      1,
+					ItemID:  updateItem.ID,
+					Kind:    model.ChangeRequestKindUpdate,
+					Version: *updateItem.Version,
+					Reasons: []string{"quantity changes by 400 pcs, more than 100 allowed"},
+					Status:  model.ChangeRequestStatusPending,
+				}, nil
+			},
+			updateFunc:     nil,
+			expectedStatus: http.StatusAccepted,
+			expectedResponse: map[string]interface{}{
+				"kind":   "update",
+				"status": "pending",
+			},
+		},
+		{
+			name: "small change is applied",
+			body: `{"user_id":2,"count":101,"version":3}`,
+			requestFunc: func(ctx context.Context, updateItem *dto.UpdateItem) (*model.ChangeRequest, error) {
+				return nil, nil
+			},
+			updateFunc: func(ctx context.Context, updateItem *dto.UpdateItem) error {
+				version := *updateItem.Version + 1
+				updateItem.Version = &version
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status":  "successfully updated item",
+				"version": float64(4),
+			},
+		},
+		{
+			name: "stale version",
+			body: `{"user_id":2,"name":"renamed","version":2}`,
+			requestFunc: func(ctx context.Context, updateItem *dto.UpdateItem) (*model.ChangeRequest, error) {
+				return nil, repository.ErrVersionConflict
+			},
+			updateFunc:     nil,
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrVersionConflict.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/items/1", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+			c.Set("role", "manager")
+
+			mock := &mockService{
+				requestItemUpdateFunc: tt.requestFunc,
+				updateItemFunc:        tt.updateFunc,
+			}
+			h := New(context.Background(), mock)
+			h.UpdateItem(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_AdjustItemByManager(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		requestFunc      func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.ChangeRequest, error)
+		adjustFunc       func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "large adjustment waits for approval",
+			body: `{"user_id":2,"delta":-500,"reason":"damage"}`,
+			requestFunc: func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.ChangeRequest, error) {
+				return &model.ChangeRequest{
+					ID:      1,
+					ItemID:  adjustItem.ID,
+					Kind:    model.ChangeRequestKindAdjust,
+					Reasons: []string{"quantity changes by 500 pcs, more than 100 allowed"},
+					Status:  model.ChangeRequestStatusPending,
+				}, nil
+			},
+			adjustFunc:     nil,
+			expectedStatus: http.StatusAccepted,
+			expectedResponse: map[string]interface{}{
+				"kind":   "adjust",
+				"status": "pending",
+			},
+		},
+		{
+			name: "small adjustment is applied",
+			body: `{"user_id":2,"delta":-1,"reason":"damage"}`,
+			requestFunc: func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.ChangeRequest, error) {
+				return nil, nil
+			},
+			adjustFunc: func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error) {
+				return &model.Movement{ID: 7, ItemID: adjustItem.ID, Quantity: adjustItem.Delta}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResponse: map[string]interface{}{
+				"id":       float64(7),
+				"quantity": float64(-1),
+			},
+		},
+		{
+			name: "archived item",
+			body: `{"user_id":2,"delta":-1,"reason":"damage"}`,
+			requestFunc: func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.ChangeRequest, error) {
+				return nil, repository.ErrItemArchived
+			},
+			adjustFunc:     nil,
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrItemArchived.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/items/1/adjust", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+			c.Set("role", "manager")
+
+			mock := &mockService{
+				requestItemAdjustFunc: tt.requestFunc,
+				adjustItemFunc:        tt.adjustFunc,
+			}
+			h := New(context.Background(), mock)
+			h.AdjustItem(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_DeleteItemByManager(t *testing.T) {
+	tests := []struct {
+		name             string
+		requestFunc      func(ctx context.Context, id, version int, userID *int) (*model.ChangeRequest, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "deletion waits for approval",
+			requestFunc: func(ctx context.Context, id, version int, userID *int) (*model.ChangeRequest, error) {
+				return &model.ChangeRequest{
+					ID:      1,
+					ItemID:  id,
+					Kind:    model.ChangeRequestKindDelete,
+					Version: version,
+					Status:  model.ChangeRequestStatusPending,
+				}, nil
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedResponse: map[string]interface{}{
+				"kind":   "delete",
+				"status": "pending",
+			},
+		},
+		{
+			name: "direct deletion is not allowed",
+			requestFunc: func(ctx context.Context, id, version int, userID *int) (*model.ChangeRequest, error) {
+				return nil, nil
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponse: map[string]interface{}{
+				"error": "not allowed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/items/1?version=3", nil)
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+			c.Set("role", "manager")
+
+			mock := &mockService{
+				requestItemDeleteFunc: tt.requestFunc,
+			}
+			h := New(context.Background(), mock)
+			h.DeleteItem(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ApproveChangeRequest(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, review dto.ReviewChangeRequest) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			body:    `{"user_id":1,"comment":"recount confirmed"}`,
+			mockFunc: func(ctx context.Context, review dto.ReviewChangeRequest) error {
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully approved change request",
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			body:           `{"user_id":1}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid change request id or was not provided",
+			},
+		},
+		{
+			name:           "no reviewer",
+			paramID:        "1",
+			body:           `{"comment":"ok"}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "UserID is required",
+			},
+		},
+		{
+			name:    "already reviewed",
+			paramID: "1",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, review dto.ReviewChangeRequest) error {
+				return repository.ErrChangeRequestStatus
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrChangeRequestStatus.Error(),
+			},
+		},
+		{
+			name:    "item changed since request",
+			paramID: "1",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, review dto.ReviewChangeRequest) error {
+				return repository.ErrVersionConflict
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrVersionConflict.Error(),
+			},
+		},
+		{
+			name:    "not found",
+			paramID: "99",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, review dto.ReviewChangeRequest) error {
+				return repository.ErrNoSuchChangeRequest
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchChangeRequest.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/change-requests/"+tt.paramID+"/approve", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				approveChangeFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.ApproveChangeRequest(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
@@ -60,13 +60,16 @@ func (h *Handler) CreateUser(c *ginext.Context) {
 }
 
 // @Summary Create a new item
-// @Description Create a new item, requires admin authentication
+// @Description Create a new item, name must be unique after case and spaces are normalized in configured scope, clash returns 409 with existing_item_id, requires admin authentication
 // @Tags items
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Key to safely retry the request"
 // @Param item body dto.CreateItem true "Item creation data"
 // @Success 200 {object} model.Item
 // @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /items [post]
 // @Security BearerAuth
@@ -89,7 +92,7 @@ func (h *Handler) CreateItem(c *ginext.Context) {
 	item, err := h.service.CreateItem(h.ctx, createItem)
 	if err != nil {
 		zlog.Logger.Error().Msg(err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorBody(err))
 		return
 	}
 
@@ -3,6 +3,8 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"wharehouse-control/internal/middleware"
+	_ "wharehouse-control/internal/model"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wb-go/wbf/ginext"
@@ -10,12 +12,21 @@ import (
 )
 
 // @Summary Delete an item
-// @Description Delete an item by ID, requires admin authentication
+// @Description Archive an item by ID, archived item is hidden from item list and can be restored, item version read by client must be sent in If-Match header or version query, requires admin or manager authentication. Manager deletion waits for admin approval as change request returned with 202, managers cannot delete at all when deletions do not need approval
 // @Tags items
 // @Produce json
 // @Param id path int true "Item ID"
+// @Param If-Match header string false "Item version from ETag"
+// @Param version query int false "Item version, used when If-Match is not sent"
+// @Param user_id query int false "User deleting item, recorded as deleted_by or in change request"
 // @Success 200 {object} gin.H
+// @Success 202 {object} model.ChangeRequest
 // @Failure 400 {object} gin.H
+// @Failure 401 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 412 {object} gin.H
+// @Failure 428 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /items/{id} [delete]
 // @Security BearerAuth
@@ -28,13 +39,129 @@ func (h *Handler) DeleteItem(c *ginext.Context) {
 		return
 	}
 
-	err = h.service.DeleteItem(h.ctx, itemID)
+	version, fromHeader, err := ifMatchVersion(c)
 	if err != nil {
 		zlog.Logger.Error().Msg(err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !fromHeader {
+		versionParam := c.Query("version")
+		if versionParam == "" {
+			zlog.Logger.Error().Msg(errVersionRequired.Error())
+			c.JSON(http.StatusPreconditionRequired, gin.H{"error": errVersionRequired.Error()})
+			return
+		}
 
-	zlog.Logger.Info().Msg("successfully handled request and deleted item")
-	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted item"})
+		version, err = strconv.Atoi(versionParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid version: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+			return
+		}
+	}
+
+	var userID *int
+	if userParam := c.Query("user_id"); userParam != "" {
+		id, err := strconv.Atoi(userParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid user id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		userID = &id
+	}
+
+	if middleware.IsManager(c) {
+		changeRequest, err := h.service.RequestItemDelete(h.ctx, itemID, version, userID)
+		if err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+			c.JSON(versionStatus(err, fromHeader), gin.H{"error": err.Error()})
+			return
+		}
+		if changeRequest == nil {
+			// deletions not needing approval do not let managers
+			// delete directly, that stays with admins
+			zlog.Logger.Error().Msg("manager is not allowed to delete item directly")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "not allowed"})
+			return
+		}
+
+		zlog.Logger.Info().Msg("successfully handled request and created change request for item deletion")
+		c.JSON(http.StatusAccepted, changeRequest)
+		return
+	}
+
+	err = h.service.DeleteItem(h.ctx, itemID, version, userID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(versionStatus(err, fromHeader), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and archived item")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully archived item"})
+}
+
+// @Summary Restore an item
+// @Description Bring archived item back to item list, its new version is returned in ETag header. Name clash with active item returns 409 with existing_item_id, merged item cannot be restored, requires admin or manager authentication
+// @Tags items
+// @Produce json
+// @Param id path int true "Item ID"
+// @Success 200 {object} gin.H
+// @Header 200 {string} ETag "New item version"
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/restore [post]
+// @Security BearerAuth
+func (h *Handler) RestoreItem(c *ginext.Context) {
+	itemID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	version, err := h.service.RestoreItem(h.ctx, itemID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), errorBody(err))
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and restored item")
+	c.Header("ETag", etag(version))
+	c.JSON(http.StatusOK, gin.H{"status": "successfully restored item", "version": version})
+}
+
+// @Summary Purge an item
+// @Description Permanently delete archived item together with its movements and history, item still referenced by documents, reservations, kits or stock is refused, requires admin authentication
+// @Tags items
+// @Produce json
+// @Param id path int true "Item ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/purge [delete]
+// @Security BearerAuth
+func (h *Handler) PurgeItem(c *ginext.Context) {
+	itemID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	if err := h.service.PurgeItem(h.ctx, itemID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and purged item")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully purged item"})
 }
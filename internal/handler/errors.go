@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"wharehouse-control/internal/repository"
+	"wharehouse-control/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusFromError maps known storage errors to http status codes,
+// everything else is treated as internal error
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidAttribute),
+		errors.Is(err, service.ErrInvalidQuantity),
+		errors.Is(err, service.ErrInvalidLocation),
+		errors.Is(err, service.ErrInvalidReservation),
+		errors.Is(err, service.ErrInvalidLot),
+		errors.Is(err, service.ErrInvalidReorderRule),
+		errors.Is(err, service.ErrInvalidPurchaseOrder),
+		errors.Is(err, service.ErrInvalidReceipt),
+		errors.Is(err, service.ErrInvalidOutboundOrder),
+		errors.Is(err, service.ErrInvalidTransferOrder),
+		errors.Is(err, service.ErrInvalidStocktake),
+		errors.Is(err, service.ErrInvalidKit),
+		errors.Is(err, service.ErrInvalidCost),
+		errors.Is(err, service.ErrInvalidAttachment),
+		errors.Is(err, service.ErrInvalidTag),
+		errors.Is(err, service.ErrInvalidMerge),
+		errors.Is(err, repository.ErrStocktakeScope),
+		errors.Is(err, repository.ErrStocktakeTracked),
+		errors.Is(err, repository.ErrLotItem),
+		errors.Is(err, repository.ErrNotSerialized),
+		errors.Is(err, repository.ErrSerialsMismatch),
+		errors.Is(err, repository.ErrLocationWarehouse):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrNoSuchItem),
+		errors.Is(err, repository.ErrNoSuchUser),
+		errors.Is(err, repository.ErrNoSuchCategory),
+		errors.Is(err, repository.ErrNoSuchAttribute),
+		errors.Is(err, repository.ErrNoSuchUnit),
+		errors.Is(err, repository.ErrNoSuchWarehouse),
+		errors.Is(err, repository.ErrNoSuchLocation),
+		errors.Is(err, repository.ErrNoSuchReservation),
+		errors.Is(err, repository.ErrNoSuchLot),
+		errors.Is(err, repository.ErrNoSuchSerial),
+		errors.Is(err, repository.ErrNoSuchReorderRule),
+		errors.Is(err, repository.ErrNoSuchAlert),
+		errors.Is(err, repository.ErrNoSuchSupplier),
+		errors.Is(err, repository.ErrNoSuchSupplierItem),
+		errors.Is(err, repository.ErrNoSuchPurchaseOrder),
+		errors.Is(err, repository.ErrNoSuchPurchaseOrderLine),
+		errors.Is(err, repository.ErrNoSuchReceipt),
+		errors.Is(err, repository.ErrNoSuchOutboundOrder),
+		errors.Is(err, repository.ErrNoSuchPick),
+		errors.Is(err, repository.ErrNoSuchTransferOrder),
+		errors.Is(err, repository.ErrNoSuchTransferOrderLine),
+		errors.Is(err, repository.ErrNoSuchStocktake),
+		errors.Is(err, repository.ErrNoSuchChangeRequest),
+		errors.Is(err, repository.ErrNoSuchAttachment),
+		errors.Is(err, repository.ErrNoSuchSavedView),
+		errors.Is(err, repository.ErrNoSuchKit):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrVersionConflict),
+		errors.Is(err, repository.ErrItemArchived),
+		errors.Is(err, repository.ErrItemNotArchived),
+		errors.Is(err, repository.ErrItemInUse),
+		errors.Is(err, repository.ErrItemNameTaken),
+		errors.Is(err, repository.ErrItemMerged),
+		errors.Is(err, repository.ErrMergeConflict),
+		errors.Is(err, repository.ErrCategoryCycle),
+		errors.Is(err, repository.ErrCategoryHasChildren),
+		errors.Is(err, repository.ErrAttributeExists),
+		errors.Is(err, repository.ErrUnitExists),
+		errors.Is(err, repository.ErrWarehouseExists),
+		errors.Is(err, repository.ErrWarehouseHasStock),
+		errors.Is(err, repository.ErrLocationExists),
+		errors.Is(err, repository.ErrLocationNotEmpty),
+		errors.Is(err, repository.ErrBelowLocatedStock),
+		errors.Is(err, repository.ErrInsufficientStock),
+		errors.Is(err, repository.ErrReservationNotActive),
+		errors.Is(err, repository.ErrInsufficientAvailable),
+		errors.Is(err, repository.ErrLotExists),
+		errors.Is(err, repository.ErrBelowLottedStock),
+		errors.Is(err, repository.ErrSerialState),
+		errors.Is(err, repository.ErrSerializedStock),
+		errors.Is(err, repository.ErrSupplierExists),
+		errors.Is(err, repository.ErrSavedViewExists),
+		errors.Is(err, repository.ErrSupplierHasOrders),
+		errors.Is(err, repository.ErrPurchaseOrderLineExists),
+		errors.Is(err, repository.ErrPurchaseOrderStatus),
+		errors.Is(err, repository.ErrPurchaseOrderEmpty),
+		errors.Is(err, repository.ErrOverDelivery),
+		errors.Is(err, repository.ErrOutboundOrderStatus),
+		errors.Is(err, repository.ErrTransferOrderStatus),
+		errors.Is(err, repository.ErrOverTransferReceipt),
+		errors.Is(err, repository.ErrStocktakeStatus),
+		errors.Is(err, repository.ErrStocktakeIncomplete),
+		errors.Is(err, repository.ErrChangeRequestStatus),
+		errors.Is(err, repository.ErrKitNesting):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorBody is body of error response, name clash also
+// points to the item that already has the name
+func errorBody(err error) gin.H {
+	body := gin.H{"error": err.Error()}
+
+	var nameErr *repository.DuplicateNameError
+	if errors.As(err, &nameErr) {
+		body["existing_item_id"] = nameErr.ItemID
+	}
+
+	return body
+}
@@ -2,27 +2,102 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wb-go/wbf/ginext"
 	"github.com/wb-go/wbf/zlog"
 
+	"wharehouse-control/internal/dto"
 	_ "wharehouse-control/internal/model"
 )
 
+const attributeQueryPrefix = "attr."
+
 // @Summary Get all items
-// @Description Get all items, requires authentication
+// @Description Get all items, archived items are left out unless include=archived, filter may come from saved view, requires authentication
 // @Tags items
 // @Produce json
+// @Param category_id query int false "Only items of this category and its subcategories"
+// @Param warehouse_id query int false "Only items stocked in this warehouse"
+// @Param attr.name query string false "Only items whose custom attribute equals value, e.g. attr.color=red"
+// @Param include query string false "archived to list deleted items too"
+// @Param tag query []string false "Only items labeled with every given tag, repeat for more tags" collectionFormat(multi)
+// @Param view_id query int false "Saved view to apply, request parameters are added on top of it"
+// @Param user_id query int false "Owner of saved view, required with view_id"
 // @Success 200 {array} model.Item
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /items [get]
 // @Security BearerAuth
 func (h *Handler) GetAllItems(c *ginext.Context) {
-	items, err := h.service.GetAllItems(h.ctx)
+	var filter dto.ItemFilter
+	if categoryParam := c.Query("category_id"); categoryParam != "" {
+		categoryID, err := strconv.Atoi(categoryParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid category id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+			return
+		}
+		filter.CategoryID = &categoryID
+	}
+
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		warehouseID, err := strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+		filter.WarehouseID = &warehouseID
+	}
+
+	switch include := c.Query("include"); include {
+	case "":
+	case "archived":
+		filter.IncludeArchived = true
+	default:
+		zlog.Logger.Error().Msg("invalid include: " + include)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid include, only archived is supported"})
+		return
+	}
+
+	for key, values := range c.Request.URL.Query() {
+		name, ok := strings.CutPrefix(key, attributeQueryPrefix)
+		if !ok || name == "" || len(values) == 0 {
+			continue
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(map[string]string)
+		}
+		filter.Attributes[name] = values[0]
+	}
+
+	filter.Tags = c.QueryArray("tag")
+
+	if viewParam := c.Query("view_id"); viewParam != "" {
+		viewID, err := strconv.Atoi(viewParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid view id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid view id"})
+			return
+		}
+
+		userID, err := queryUserID(c)
+		if err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter.ViewID, filter.UserID = &viewID, &userID
+	}
+
+	items, err := h.service.GetAllItems(h.ctx, filter)
 	if err != nil {
 		zlog.Logger.Error().Msg(err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -30,6 +105,39 @@ func (h *Handler) GetAllItems(c *ginext.Context) {
 	c.JSON(http.StatusOK, items)
 }
 
+// @Summary Get an item
+// @Description Get an item by ID, its version is returned in ETag header for later If-Match, requires authentication
+// @Tags items
+// @Produce json
+// @Param id path int true "Item ID"
+// @Success 200 {object} model.Item
+// @Header 200 {string} ETag "Item version"
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetItemByID(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	item, err := h.service.GetItemByID(h.ctx, itemID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned item")
+	c.Header("ETag", etag(item.Version))
+	c.JSON(http.StatusOK, item)
+}
+
 // @Summary Get users with changes
 // @Description Get users with their change history, requires authentication
 // @Tags users
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"io"
 	"wharehouse-control/internal/dto"
 	"wharehouse-control/internal/model"
 )
@@ -10,10 +11,116 @@ type Service interface {
 	CreateItem(ctx context.Context, createItem dto.CreateItem) (*model.Item, error)
 	CreateUser(ctx context.Context, createUser dto.CreateUser) (*model.User, error)
 	GetUsersWithChanges(ctx context.Context) ([]model.UserHistory, error)
-	GetAllItems(ctx context.Context) ([]model.Item, error)
+	GetAllItems(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error)
 	GetUserRole(ctx context.Context, id int) (string, error)
 	UpdateItem(ctx context.Context, updateItem *dto.UpdateItem) error
-	DeleteItem(ctx context.Context, id int) error
+	GetItemByID(ctx context.Context, id int) (*model.Item, error)
+	DeleteItem(ctx context.Context, id, version int, userID *int) error
+	RestoreItem(ctx context.Context, id int) (int, error)
+	PurgeItem(ctx context.Context, id int) error
+	CreateCategory(ctx context.Context, createCategory dto.CreateCategory) (*model.Category, error)
+	GetCategoryTree(ctx context.Context) ([]model.Category, error)
+	GetCategoryByID(ctx context.Context, id int) (*model.Category, error)
+	UpdateCategory(ctx context.Context, updateCategory *dto.UpdateCategory) error
+	DeleteCategory(ctx context.Context, id int) error
+	CreateAttributeDefinition(ctx context.Context, createDefinition dto.CreateAttributeDefinition) (*model.AttributeDefinition, error)
+	GetAttributeDefinitions(ctx context.Context, categoryID int) ([]model.AttributeDefinition, error)
+	DeleteAttributeDefinition(ctx context.Context, categoryID, id int) error
+	CreateUnit(ctx context.Context, createUnit dto.CreateUnit) (*model.Unit, error)
+	GetUnits(ctx context.Context) ([]model.Unit, error)
+	SetItemUnit(ctx context.Context, itemUnit model.ItemUnit) error
+	GetItemUnits(ctx context.Context, itemID int) ([]model.ItemUnit, error)
+	DeleteItemUnit(ctx context.Context, itemID int, unit string) error
+	CreateWarehouse(ctx context.Context, createWarehouse dto.CreateWarehouse) (*model.Warehouse, error)
+	GetWarehouses(ctx context.Context) ([]model.Warehouse, error)
+	GetWarehouseByID(ctx context.Context, id int) (*model.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, updateWarehouse *dto.UpdateWarehouse) error
+	DeleteWarehouse(ctx context.Context, id int) error
+	CreateLocation(ctx context.Context, createLocation dto.CreateLocation) (*model.Location, error)
+	GetLocations(ctx context.Context, warehouseID int) ([]model.Location, error)
+	DeleteLocation(ctx context.Context, warehouseID, id int) error
+	GetItemLocations(ctx context.Context, itemID int) ([]model.ItemLocation, error)
+	GetMovements(ctx context.Context, filter dto.MovementFilter) ([]model.Movement, error)
+	AdjustItem(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error)
+	CreateReservation(ctx context.Context, createReservation dto.CreateReservation) (*model.Reservation, error)
+	GetReservations(ctx context.Context, filter dto.ReservationFilter) ([]model.Reservation, error)
+	ConfirmReservation(ctx context.Context, confirmReservation dto.ConfirmReservation) error
+	ReleaseReservation(ctx context.Context, id int) error
+	CreateLot(ctx context.Context, createLot dto.CreateLot) (*model.Lot, error)
+	GetLots(ctx context.Context, itemID int) ([]model.Lot, error)
+	SuggestPick(ctx context.Context, pickRequest dto.PickRequest) (*model.PickSuggestion, error)
+	GetExpiringLots(ctx context.Context, days int, warehouseID *int) ([]model.ExpiringLot, error)
+	GetSerials(ctx context.Context, filter dto.SerialFilter) ([]model.Serial, error)
+	GetSerialHistory(ctx context.Context, itemID int, serialNumber string) (*model.SerialHistory, error)
+	SetReorderRule(ctx context.Context, setReorderRule dto.SetReorderRule) (*model.ReorderRule, error)
+	GetReorderRules(ctx context.Context, itemID int) ([]model.ReorderRule, error)
+	DeleteReorderRule(ctx context.Context, itemID int, warehouseID *int) error
+	GetLowStock(ctx context.Context, warehouseID *int) ([]model.LowStockItem, error)
+	GetStockAlerts(ctx context.Context, onlyActive bool) ([]model.StockAlert, error)
+	AcknowledgeStockAlert(ctx context.Context, id int64) error
+	CreateSupplier(ctx context.Context, createSupplier dto.CreateSupplier) (*model.Supplier, error)
+	GetSuppliers(ctx context.Context) ([]model.Supplier, error)
+	GetSupplierByID(ctx context.Context, id int) (*model.Supplier, error)
+	UpdateSupplier(ctx context.Context, updateSupplier *dto.UpdateSupplier) error
+	DeleteSupplier(ctx context.Context, id int) error
+	SetSupplierItem(ctx context.Context, setSupplierItem dto.SetSupplierItem) (*model.SupplierItem, error)
+	DeleteSupplierItem(ctx context.Context, supplierID, itemID int) error
+	CreatePurchaseOrder(ctx context.Context, createOrder dto.CreatePurchaseOrder) (*model.PurchaseOrder, error)
+	CreateLowStockOrders(ctx context.Context, createOrders dto.CreateLowStockOrders) (*model.LowStockOrders, error)
+	GetPurchaseOrders(ctx context.Context, filter dto.PurchaseOrderFilter) ([]model.PurchaseOrder, error)
+	GetPurchaseOrderByID(ctx context.Context, id int) (*model.PurchaseOrder, error)
+	AddPurchaseOrderLine(ctx context.Context, addLine dto.AddPurchaseOrderLine) (*model.PurchaseOrderLine, error)
+	DeletePurchaseOrderLine(ctx context.Context, orderID, lineID int) error
+	SendPurchaseOrder(ctx context.Context, id int) error
+	ClosePurchaseOrder(ctx context.Context, id int) error
+	CreateReceipt(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error)
+	GetReceipts(ctx context.Context, filter dto.ReceiptFilter) ([]model.Receipt, error)
+	GetReceiptByID(ctx context.Context, id int) (*model.Receipt, error)
+	CreateOutboundOrder(ctx context.Context, createOrder dto.CreateOutboundOrder) (*model.OutboundOrder, error)
+	GetOutboundOrders(ctx context.Context, filter dto.OutboundOrderFilter) ([]model.OutboundOrder, error)
+	GetOutboundOrderByID(ctx context.Context, id int) (*model.OutboundOrder, error)
+	GeneratePickList(ctx context.Context, orderID int) (*model.OutboundOrder, error)
+	PackOutboundOrder(ctx context.Context, packOrder *dto.PackOutboundOrder) error
+	ShipOutboundOrder(ctx context.Context, shipOrder *dto.ShipOutboundOrder) error
+	CancelOutboundOrder(ctx context.Context, id int) error
+	CreateTransferOrder(ctx context.Context, createOrder dto.CreateTransferOrder) (*model.TransferOrder, error)
+	GetTransferOrders(ctx context.Context, filter dto.TransferOrderFilter) ([]model.TransferOrder, error)
+	GetTransferOrderByID(ctx context.Context, id int) (*model.TransferOrder, error)
+	DispatchTransferOrder(ctx context.Context, dispatchOrder dto.DispatchTransferOrder) error
+	ReceiveTransferOrder(ctx context.Context, receiveOrder *dto.ReceiveTransferOrder) error
+	CancelTransferOrder(ctx context.Context, id int) error
+	CreateStocktake(ctx context.Context, createStocktake dto.CreateStocktake) (*model.Stocktake, error)
+	GetStocktakes(ctx context.Context, filter dto.StocktakeFilter) ([]model.Stocktake, error)
+	GetStocktakeByID(ctx context.Context, id int) (*model.Stocktake, error)
+	GetStocktakeVariances(ctx context.Context, id int) ([]model.StocktakeLine, error)
+	RecordStocktakeCounts(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error
+	ApproveStocktake(ctx context.Context, approveStocktake dto.ApproveStocktake) error
+	CancelStocktake(ctx context.Context, id int) error
+	SetKitComponents(ctx context.Context, setComponents *dto.SetKitComponents) (*model.Kit, error)
+	GetKit(ctx context.Context, kitID, warehouseID int) (*model.Kit, error)
+	AssembleKit(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error)
+	DisassembleKit(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error)
+	GetValuation(ctx context.Context, filter dto.ValuationFilter) (*model.Valuation, error)
+	UploadAttachment(ctx context.Context, upload *dto.UploadAttachment) (*model.Attachment, error)
+	GetAttachments(ctx context.Context, itemID int) ([]model.Attachment, error)
+	OpenAttachment(ctx context.Context, id int, thumbnail bool) (*model.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, id int) error
+	RequestItemUpdate(ctx context.Context, updateItem *dto.UpdateItem) (*model.ChangeRequest, error)
+	RequestItemAdjust(ctx context.Context, adjustItem *dto.AdjustItem) (*model.ChangeRequest, error)
+	RequestItemDelete(ctx context.Context, id, version int, userID *int) (*model.ChangeRequest, error)
+	GetChangeRequests(ctx context.Context, filter dto.ChangeRequestFilter) ([]model.ChangeRequest, error)
+	GetChangeRequestByID(ctx context.Context, id int) (*model.ChangeRequest, error)
+	ApproveChangeRequest(ctx context.Context, review dto.ReviewChangeRequest) error
+	RejectChangeRequest(ctx context.Context, review dto.ReviewChangeRequest) error
+	SetItemTags(ctx context.Context, setTags *dto.SetItemTags) (*model.Item, error)
+	GetTags(ctx context.Context) ([]model.TagCount, error)
+	CreateSavedView(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error)
+	GetSavedViews(ctx context.Context, userID int) ([]model.SavedView, error)
+	GetSavedViewByID(ctx context.Context, id, userID int) (*model.SavedView, error)
+	UpdateSavedView(ctx context.Context, saveView *dto.SaveView) (*model.SavedView, error)
+	DeleteSavedView(ctx context.Context, id, userID int) error
+	GetDuplicateItems(ctx context.Context, filter dto.DuplicateFilter) ([]model.DuplicatePair, error)
+	MergeItems(ctx context.Context, mergeItems dto.MergeItems) (*model.Item, error)
 }
 
 type Handler struct {
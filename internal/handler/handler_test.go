@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -13,18 +15,67 @@ import (
 
 	"wharehouse-control/internal/dto"
 	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+	"wharehouse-control/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
+// mockService embeds Service so that tests only need to stub
+// the methods they exercise, calling anything else panics
 type mockService struct {
+	Service
+
 	createUserFunc          func(ctx context.Context, createUser dto.CreateUser) (*model.User, error)
 	createItemFunc          func(ctx context.Context, createItem dto.CreateItem) (*model.Item, error)
-	getAllItemsFunc         func(ctx context.Context) ([]model.Item, error)
+	getAllItemsFunc         func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error)
 	getUsersWithChangesFunc func(ctx context.Context) ([]model.UserHistory, error)
 	updateItemFunc          func(ctx context.Context, updateItem *dto.UpdateItem) error
-	deleteItemFunc          func(ctx context.Context, id int) error
+	deleteItemFunc          func(ctx context.Context, id, version int, userID *int) error
+	restoreItemFunc         func(ctx context.Context, id int) (int, error)
+	purgeItemFunc           func(ctx context.Context, id int) error
+	getItemByIDFunc         func(ctx context.Context, id int) (*model.Item, error)
 	getUserRoleFunc         func(ctx context.Context, id int) (string, error)
+	createCategoryFunc      func(ctx context.Context, createCategory dto.CreateCategory) (*model.Category, error)
+	updateCategoryFunc      func(ctx context.Context, updateCategory *dto.UpdateCategory) error
+	setItemUnitFunc         func(ctx context.Context, itemUnit model.ItemUnit) error
+	deleteWarehouseFunc     func(ctx context.Context, id int) error
+	getItemLocationsFunc    func(ctx context.Context, itemID int) ([]model.ItemLocation, error)
+	getMovementsFunc        func(ctx context.Context, filter dto.MovementFilter) ([]model.Movement, error)
+	adjustItemFunc          func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error)
+	createReservationFunc   func(ctx context.Context, createReservation dto.CreateReservation) (*model.Reservation, error)
+	confirmReservationFunc  func(ctx context.Context, confirmReservation dto.ConfirmReservation) error
+	createLotFunc           func(ctx context.Context, createLot dto.CreateLot) (*model.Lot, error)
+	suggestPickFunc         func(ctx context.Context, pickRequest dto.PickRequest) (*model.PickSuggestion, error)
+	getSerialHistoryFunc    func(ctx context.Context, itemID int, serialNumber string) (*model.SerialHistory, error)
+	setReorderRuleFunc      func(ctx context.Context, setReorderRule dto.SetReorderRule) (*model.ReorderRule, error)
+	getLowStockFunc         func(ctx context.Context, warehouseID *int) ([]model.LowStockItem, error)
+	createSupplierFunc      func(ctx context.Context, createSupplier dto.CreateSupplier) (*model.Supplier, error)
+	createPurchaseOrderFunc func(ctx context.Context, createOrder dto.CreatePurchaseOrder) (*model.PurchaseOrder, error)
+	sendPurchaseOrderFunc   func(ctx context.Context, id int) error
+	createReceiptFunc       func(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error)
+	createOutboundOrderFunc func(ctx context.Context, createOrder dto.CreateOutboundOrder) (*model.OutboundOrder, error)
+	generatePickListFunc    func(ctx context.Context, orderID int) (*model.OutboundOrder, error)
+	shipOutboundOrderFunc   func(ctx context.Context, shipOrder *dto.ShipOutboundOrder) error
+	createTransferOrderFunc func(ctx context.Context, createOrder dto.CreateTransferOrder) (*model.TransferOrder, error)
+	dispatchTransferFunc    func(ctx context.Context, dispatchOrder dto.DispatchTransferOrder) error
+	receiveTransferFunc     func(ctx context.Context, receiveOrder *dto.ReceiveTransferOrder) error
+	createStocktakeFunc     func(ctx context.Context, createStocktake dto.CreateStocktake) (*model.Stocktake, error)
+	recordCountsFunc        func(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error
+	approveStocktakeFunc    func(ctx context.Context, approveStocktake dto.ApproveStocktake) error
+	setKitComponentsFunc    func(ctx context.Context, setComponents *dto.SetKitComponents) (*model.Kit, error)
+	assembleKitFunc         func(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error)
+	requestItemUpdateFunc   func(ctx context.Context, updateItem *dto.UpdateItem) (*model.ChangeRequest, error)
+	requestItemAdjustFunc   func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.ChangeRequest, error)
+	requestItemDeleteFunc   func(ctx context.Context, id, version int, userID *int) (*model.ChangeRequest, error)
+	approveChangeFunc       func(ctx context.Context, review dto.ReviewChangeRequest) error
+	getValuationFunc        func(ctx context.Context, filter dto.ValuationFilter) (*model.Valuation, error)
+	uploadAttachmentFunc    func(ctx context.Context, upload *dto.UploadAttachment) (*model.Attachment, error)
+	openAttachmentFunc      func(ctx context.Context, id int, thumbnail bool) (*model.Attachment, io.ReadCloser, error)
+	setItemTagsFunc         func(ctx context.Context, setTags *dto.SetItemTags) (*model.Item, error)
+	createSavedViewFunc     func(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error)
+	getDuplicateItemsFunc   func(ctx context.Context, filter dto.DuplicateFilter) ([]model.DuplicatePair, error)
+	mergeItemsFunc          func(ctx context.Context, mergeItems dto.MergeItems) (*model.Item, error)
 }
 
 func (m *mockService) CreateUser(ctx context.Context, createUser dto.CreateUser) (*model.User, error) {
@@ -35,8 +86,8 @@ func (m *mockService) CreateItem(ctx context.Context, createItem dto.CreateItem)
 	return m.createItemFunc(ctx, createItem)
 }
 
-func (m *mockService) GetAllItems(ctx context.Context) ([]model.Item, error) {
-	return m.getAllItemsFunc(ctx)
+func (m *mockService) GetAllItems(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
+	return m.getAllItemsFunc(ctx, filter)
 }
 
 func (m *mockService) GetUsersWithChanges(ctx context.Context) ([]model.UserHistory, error) {
@@ -47,14 +98,186 @@ func (m *mockService) UpdateItem(ctx context.Context, updateItem *dto.UpdateItem
 	return m.updateItemFunc(ctx, updateItem)
 }
 
-func (m *mockService) DeleteItem(ctx context.Context, id int) error {
-	return m.deleteItemFunc(ctx, id)
+func (m *mockService) DeleteItem(ctx context.Context, id, version int, userID *int) error {
+	return m.deleteItemFunc(ctx, id, version, userID)
+}
+
+func (m *mockService) RestoreItem(ctx context.Context, id int) (int, error) {
+	return m.restoreItemFunc(ctx, id)
+}
+
+func (m *mockService) PurgeItem(ctx context.Context, id int) error {
+	return m.purgeItemFunc(ctx, id)
+}
+
+func (m *mockService) GetItemByID(ctx context.Context, id int) (*model.Item, error) {
+	return m.getItemByIDFunc(ctx, id)
 }
 
 func (m *mockService) GetUserRole(ctx context.Context, id int) (string, error) {
 	return m.getUserRoleFunc(ctx, id)
 }
 
+func (m *mockService) CreateCategory(ctx context.Context, createCategory dto.CreateCategory) (*model.Category, error) {
+	return m.createCategoryFunc(ctx, createCategory)
+}
+
+func (m *mockService) UpdateCategory(ctx context.Context, updateCategory *dto.UpdateCategory) error {
+	return m.updateCategoryFunc(ctx, updateCategory)
+}
+
+func (m *mockService) SetItemUnit(ctx context.Context, itemUnit model.ItemUnit) error {
+	return m.setItemUnitFunc(ctx, itemUnit)
+}
+
+func (m *mockService) DeleteWarehouse(ctx context.Context, id int) error {
+	return m.deleteWarehouseFunc(ctx, id)
+}
+
+func (m *mockService) GetItemLocations(ctx context.Context, itemID int) ([]model.ItemLocation, error) {
+	return m.getItemLocationsFunc(ctx, itemID)
+}
+
+func (m *mockService) GetMovements(ctx context.Context, filter dto.MovementFilter) ([]model.Movement, error) {
+	return m.getMovementsFunc(ctx, filter)
+}
+
+func (m *mockService) AdjustItem(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error) {
+	return m.adjustItemFunc(ctx, adjustItem)
+}
+
+func (m *mockService) CreateReservation(ctx context.Context, createReservation dto.CreateReservation) (*model.Reservation, error) {
+	return m.createReservationFunc(ctx, createReservation)
+}
+
+func (m *mockService) ConfirmReservation(ctx context.Context, confirmReservation dto.ConfirmReservation) error {
+	return m.confirmReservationFunc(ctx, confirmReservation)
+}
+
+func (m *mockService) CreateLot(ctx context.Context, createLot dto.CreateLot) (*model.Lot, error) {
+	return m.createLotFunc(ctx, createLot)
+}
+
+func (m *mockService) SuggestPick(ctx context.Context, pickRequest dto.PickRequest) (*model.PickSuggestion, error) {
+	return m.suggestPickFunc(ctx, pickRequest)
+}
+
+func (m *mockService) GetSerialHistory(ctx context.Context, itemID int, serialNumber string) (*model.SerialHistory, error) {
+	return m.getSerialHistoryFunc(ctx, itemID, serialNumber)
+}
+
+func (m *mockService) SetReorderRule(ctx context.Context, setReorderRule dto.SetReorderRule) (*model.ReorderRule, error) {
+	return m.setReorderRuleFunc(ctx, setReorderRule)
+}
+
+func (m *mockService) GetLowStock(ctx context.Context, warehouseID *int) ([]model.LowStockItem, error) {
+	return m.getLowStockFunc(ctx, warehouseID)
+}
+
+func (m *mockService) CreateSupplier(ctx context.Context, createSupplier dto.CreateSupplier) (*model.Supplier, error) {
+	return m.createSupplierFunc(ctx, createSupplier)
+}
+
+func (m *mockService) CreatePurchaseOrder(ctx context.Context, createOrder dto.CreatePurchaseOrder) (*model.PurchaseOrder, error) {
+	return m.createPurchaseOrderFunc(ctx, createOrder)
+}
+
+func (m *mockService) SendPurchaseOrder(ctx context.Context, id int) error {
+	return m.sendPurchaseOrderFunc(ctx, id)
+}
+
+func (m *mockService) CreateReceipt(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error) {
+	return m.createReceiptFunc(ctx, createReceipt)
+}
+
+func (m *mockService) CreateOutboundOrder(ctx context.Context, createOrder dto.CreateOutboundOrder) (*model.OutboundOrder, error) {
+	return m.createOutboundOrderFunc(ctx, createOrder)
+}
+
+func (m *mockService) GeneratePickList(ctx context.Context, orderID int) (*model.OutboundOrder, error) {
+	return m.generatePickListFunc(ctx, orderID)
+}
+
+func (m *mockService) ShipOutboundOrder(ctx context.Context, shipOrder *dto.ShipOutboundOrder) error {
+	return m.shipOutboundOrderFunc(ctx, shipOrder)
+}
+
+func (m *mockService) CreateTransferOrder(ctx context.Context, createOrder dto.CreateTransferOrder) (*model.TransferOrder, error) {
+	return m.createTransferOrderFunc(ctx, createOrder)
+}
+
+func (m *mockService) DispatchTransferOrder(ctx context.Context, dispatchOrder dto.DispatchTransferOrder) error {
+	return m.dispatchTransferFunc(ctx, dispatchOrder)
+}
+
+func (m *mockService) ReceiveTransferOrder(ctx context.Context, receiveOrder *dto.ReceiveTransferOrder) error {
+	return m.receiveTransferFunc(ctx, receiveOrder)
+}
+
+func (m *mockService) CreateStocktake(ctx context.Context, createStocktake dto.CreateStocktake) (*model.Stocktake, error) {
+	return m.createStocktakeFunc(ctx, createStocktake)
+}
+
+func (m *mockService) RecordStocktakeCounts(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error {
+	return m.recordCountsFunc(ctx, recordCounts)
+}
+
+func (m *mockService) ApproveStocktake(ctx context.Context, approveStocktake dto.ApproveStocktake) error {
+	return m.approveStocktakeFunc(ctx, approveStocktake)
+}
+
+func (m *mockService) SetKitComponents(ctx context.Context, setComponents *dto.SetKitComponents) (*model.Kit, error) {
+	return m.setKitComponentsFunc(ctx, setComponents)
+}
+
+func (m *mockService) AssembleKit(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error) {
+	return m.assembleKitFunc(ctx, assembleKit)
+}
+
+func (m *mockService) RequestItemUpdate(ctx context.Context, updateItem *dto.UpdateItem) (*model.ChangeRequest, error) {
+	return m.requestItemUpdateFunc(ctx, updateItem)
+}
+
+func (m *mockService) RequestItemAdjust(ctx context.Context, adjustItem *dto.AdjustItem) (*model.ChangeRequest, error) {
+	return m.requestItemAdjustFunc(ctx, adjustItem)
+}
+
+func (m *mockService) RequestItemDelete(ctx context.Context, id, version int, userID *int) (*model.ChangeRequest, error) {
+	return m.requestItemDeleteFunc(ctx, id, version, userID)
+}
+
+func (m *mockService) ApproveChangeRequest(ctx context.Context, review dto.ReviewChangeRequest) error {
+	return m.approveChangeFunc(ctx, review)
+}
+
+func (m *mockService) GetValuation(ctx context.Context, filter dto.ValuationFilter) (*model.Valuation, error) {
+	return m.getValuationFunc(ctx, filter)
+}
+
+func (m *mockService) UploadAttachment(ctx context.Context, upload *dto.UploadAttachment) (*model.Attachment, error) {
+	return m.uploadAttachmentFunc(ctx, upload)
+}
+
+func (m *mockService) OpenAttachment(ctx context.Context, id int, thumbnail bool) (*model.Attachment, io.ReadCloser, error) {
+	return m.openAttachmentFunc(ctx, id, thumbnail)
+}
+
+func (m *mockService) SetItemTags(ctx context.Context, setTags *dto.SetItemTags) (*model.Item, error) {
+	return m.setItemTagsFunc(ctx, setTags)
+}
+
+func (m *mockService) CreateSavedView(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error) {
+	return m.createSavedViewFunc(ctx, saveView)
+}
+
+func (m *mockService) GetDuplicateItems(ctx context.Context, filter dto.DuplicateFilter) ([]model.DuplicatePair, error) {
+	return m.getDuplicateItemsFunc(ctx, filter)
+}
+
+func (m *mockService) MergeItems(ctx context.Context, mergeItems dto.MergeItems) (*model.Item, error) {
+	return m.mergeItemsFunc(ctx, mergeItems)
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
@@ -189,6 +412,29 @@ func TestHandler_CreateItem(t *testing.T) {
 				"error": "Name is required",
 			},
 		},
+		{
+			name: "name taken",
+			body: `{"name":"bolt m6 ","count":10}`,
+			mockFunc: func(ctx context.Context, createItem dto.CreateItem) (*model.Item, error) {
+				return nil, &repository.DuplicateNameError{ItemID: 4, Name: "Bolt M6"}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error":            `item with such name already exists: item 4 "Bolt M6"`,
+				"existing_item_id": float64(4),
+			},
+		},
+		{
+			name: "invalid attributes",
+			body: `{"name":"item","count":10,"category_id":1,"attributes":{"color":"purple"}}`,
+			mockFunc: func(ctx context.Context, createItem dto.CreateItem) (*model.Item, error) {
+				return nil, fmt.Errorf("%w: color must be one of [red green]", service.ErrInvalidAttribute)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid item attributes: color must be one of [red green]",
+			},
+		},
 		{
 			name: "service error",
 			body: `{"name":"item","count":10}`,
@@ -234,13 +480,15 @@ func TestHandler_CreateItem(t *testing.T) {
 func TestHandler_GetAllItems(t *testing.T) {
 	tests := []struct {
 		name             string
-		mockFunc         func(ctx context.Context) ([]model.Item, error)
+		query            string
+		mockFunc         func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error)
 		expectedStatus   int
 		expectedResponse []map[string]interface{}
+		expectedError    string
 	}{
 		{
 			name: "success",
-			mockFunc: func(ctx context.Context) ([]model.Item, error) {
+			mockFunc: func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
 				return []model.Item{{ID: 1, Name: "item", Count: 10, CreatedAt: time.Now()}}, nil
 			},
 			expectedStatus: http.StatusOK,
@@ -252,13 +500,147 @@ func TestHandler_GetAllItems(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "filter by warehouse",
+			query: "?warehouse_id=2",
+			mockFunc: func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
+				if filter.WarehouseID == nil || *filter.WarehouseID != 2 {
+					return nil, errors.New("unexpected filter")
+				}
+				return []model.Item{{ID: 5, Name: "cable", Count: 30, Stock: []model.StockLevel{
+					{WarehouseID: 1, Quantity: 10},
+					{WarehouseID: 2, Quantity: 20},
+				}}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: []map[string]interface{}{
+				{
+					"id":    float64(5),
+					"count": float64(30),
+				},
+			},
+		},
+		{
+			name:  "filter by category",
+			query: "?category_id=3",
+			mockFunc: func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
+				if filter.CategoryID == nil || *filter.CategoryID != 3 {
+					return nil, errors.New("unexpected filter")
+				}
+				categoryID := 3
+				return []model.Item{{ID: 2, Name: "bolt", Count: 5, CategoryID: &categoryID}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: []map[string]interface{}{
+				{
+					"id":          float64(2),
+					"category_id": float64(3),
+				},
+			},
+		},
+		{
+			name:  "filter by attribute",
+			query: "?attr.color=red",
+			mockFunc: func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
+				if filter.Attributes["color"] != "red" {
+					return nil, errors.New("unexpected filter")
+				}
+				return []model.Item{{ID: 4, Name: "cap", Count: 1, Attributes: map[string]any{"color": "red"}}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: []map[string]interface{}{
+				{
+					"id": float64(4),
+				},
+			},
+		},
+		{
+			name:  "include archived",
+			query: "?include=archived",
+			mockFunc: func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
+				if !filter.IncludeArchived {
+					return nil, errors.New("unexpected filter")
+				}
+				deletedAt := time.Now()
+				return []model.Item{{ID: 6, Name: "old", DeletedAt: &deletedAt}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: []map[string]interface{}{
+				{
+					"id": float64(6),
+				},
+			},
+		},
+		{
+			name:  "filter by tags",
+			query: "?tag=fragile&tag=promo",
+			mockFunc: func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
+				if len(filter.Tags) != 2 || filter.Tags[0] != "fragile" || filter.Tags[1] != "promo" {
+					return nil, errors.New("unexpected filter")
+				}
+				return []model.Item{{ID: 7, Name: "vase", Tags: []string{"fragile", "promo"}}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: []map[string]interface{}{
+				{
+					"id": float64(7),
+				},
+			},
+		},
+		{
+			name:  "saved view",
+			query: "?view_id=3&user_id=1",
+			mockFunc: func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
+				if filter.ViewID == nil || *filter.ViewID != 3 || filter.UserID == nil || *filter.UserID != 1 {
+					return nil, errors.New("unexpected filter")
+				}
+				return []model.Item{{ID: 8, Name: "lamp"}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: []map[string]interface{}{
+				{
+					"id": float64(8),
+				},
+			},
+		},
+		{
+			name:           "saved view without user",
+			query:          "?view_id=3",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "user_id is required",
+		},
+		{
+			name:  "saved view of other user",
+			query: "?view_id=3&user_id=2",
+			mockFunc: func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
+				return nil, repository.ErrNoSuchSavedView
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "no saved view with such id",
+		},
+		{
+			name:           "invalid include",
+			query:          "?include=everything",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid include, only archived is supported",
+		},
+		{
+			name:           "invalid category id",
+			query:          "?category_id=abc",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid category id",
+		},
 		{
 			name: "service error",
-			mockFunc: func(ctx context.Context) ([]model.Item, error) {
+			mockFunc: func(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
 				return nil, errors.New("service error")
 			},
 			expectedStatus:   http.StatusInternalServerError,
 			expectedResponse: nil,
+			expectedError:    "service error",
 		},
 	}
 
@@ -266,7 +648,7 @@ func TestHandler_GetAllItems(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
-			c.Request = httptest.NewRequest(http.MethodGet, "/items", nil)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items"+tt.query, nil)
 
 			mock := &mockService{
 				getAllItemsFunc: tt.mockFunc,
@@ -291,8 +673,8 @@ func TestHandler_GetAllItems(t *testing.T) {
 			} else {
 				var resp map[string]interface{}
 				json.Unmarshal(w.Body.Bytes(), &resp)
-				if resp["error"] != "service error" {
-					t.Errorf("expected error 'service error', got %v", resp["error"])
+				if resp["error"] != tt.expectedError {
+					t.Errorf("expected error '%s', got %v", tt.expectedError, resp["error"])
 				}
 			}
 		})
@@ -371,6 +753,7 @@ func TestHandler_UpdateItem(t *testing.T) {
 	tests := []struct {
 		name             string
 		paramID          string
+		ifMatch          string
 		body             string
 		mockFunc         func(ctx context.Context, updateItem *dto.UpdateItem) error
 		expectedStatus   int
@@ -379,8 +762,27 @@ func TestHandler_UpdateItem(t *testing.T) {
 		{
 			name:    "success",
 			paramID: "1",
-			body:    `{"user_id":1,"name":"updated"}`,
+			body:    `{"user_id":1,"name":"updated","version":3}`,
 			mockFunc: func(ctx context.Context, updateItem *dto.UpdateItem) error {
+				version := *updateItem.Version + 1
+				updateItem.Version = &version
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status":  "successfully updated item",
+				"version": float64(4),
+			},
+		},
+		{
+			name:    "if-match header",
+			paramID: "1",
+			ifMatch: `"3"`,
+			body:    `{"user_id":1,"name":"updated","version":1}`,
+			mockFunc: func(ctx context.Context, updateItem *dto.UpdateItem) error {
+				if *updateItem.Version != 3 {
+					return errors.New("header version was not used")
+				}
 				return nil
 			},
 			expectedStatus: http.StatusOK,
@@ -388,6 +790,41 @@ func TestHandler_UpdateItem(t *testing.T) {
 				"status": "successfully updated item",
 			},
 		},
+		{
+			name:           "version missing",
+			paramID:        "1",
+			body:           `{"user_id":1,"name":"updated"}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusPreconditionRequired,
+			expectedResponse: map[string]interface{}{
+				"error": "If-Match header or item version is required",
+			},
+		},
+		{
+			name:    "stale if-match header",
+			paramID: "1",
+			ifMatch: `"2"`,
+			body:    `{"user_id":1,"name":"updated"}`,
+			mockFunc: func(ctx context.Context, updateItem *dto.UpdateItem) error {
+				return repository.ErrVersionConflict
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrVersionConflict.Error(),
+			},
+		},
+		{
+			name:    "stale body version",
+			paramID: "1",
+			body:    `{"user_id":1,"name":"updated","version":2}`,
+			mockFunc: func(ctx context.Context, updateItem *dto.UpdateItem) error {
+				return repository.ErrVersionConflict
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrVersionConflict.Error(),
+			},
+		},
 		{
 			name:           "invalid id",
 			paramID:        "abc",
@@ -418,10 +855,34 @@ func TestHandler_UpdateItem(t *testing.T) {
 				"error": "UserID is required",
 			},
 		},
+		{
+			name:    "insufficient stock",
+			paramID: "1",
+			body:    `{"user_id":1,"count":5,"location_id":3,"version":1}`,
+			mockFunc: func(ctx context.Context, updateItem *dto.UpdateItem) error {
+				return repository.ErrBelowLocatedStock
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrBelowLocatedStock.Error(),
+			},
+		},
+		{
+			name:    "fractional pieces",
+			paramID: "1",
+			body:    `{"user_id":1,"count":1.5,"version":1}`,
+			mockFunc: func(ctx context.Context, updateItem *dto.UpdateItem) error {
+				return fmt.Errorf("%w: pcs must be a whole number", service.ErrInvalidQuantity)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid quantity: pcs must be a whole number",
+			},
+		},
 		{
 			name:    "service error",
 			paramID: "1",
-			body:    `{"user_id":1}`,
+			body:    `{"user_id":1,"version":1}`,
 			mockFunc: func(ctx context.Context, updateItem *dto.UpdateItem) error {
 				return errors.New("service error")
 			},
@@ -438,6 +899,9 @@ func TestHandler_UpdateItem(t *testing.T) {
 			c, _ := gin.CreateTestContext(w)
 			c.Request = httptest.NewRequest(http.MethodPut, "/items/"+tt.paramID, strings.NewReader(tt.body))
 			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
 			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
 
 			mock := &mockService{
@@ -466,19 +930,25 @@ func TestHandler_DeleteItem(t *testing.T) {
 	tests := []struct {
 		name             string
 		paramID          string
-		mockFunc         func(ctx context.Context, id int) error
+		ifMatch          string
+		query            string
+		mockFunc         func(ctx context.Context, id, version int, userID *int) error
 		expectedStatus   int
 		expectedResponse map[string]interface{}
 	}{
 		{
 			name:    "success",
 			paramID: "1",
-			mockFunc: func(ctx context.Context, id int) error {
+			ifMatch: `"5"`,
+			mockFunc: func(ctx context.Context, id, version int, userID *int) error {
+				if version != 5 {
+					return errors.New("unexpected version")
+				}
 				return nil
 			},
 			expectedStatus: http.StatusOK,
 			expectedResponse: map[string]interface{}{
-				"status": "successfully deleted item",
+				"status": "successfully archived item",
 			},
 		},
 		{
@@ -490,10 +960,74 @@ func TestHandler_DeleteItem(t *testing.T) {
 				"error": "invalid item is or was not provided",
 			},
 		},
+		{
+			name:    "version query",
+			paramID: "1",
+			query:   "?version=2",
+			mockFunc: func(ctx context.Context, id, version int, userID *int) error {
+				if version != 2 {
+					return errors.New("unexpected version")
+				}
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully archived item",
+			},
+		},
+		{
+			name:           "version missing",
+			paramID:        "1",
+			mockFunc:       nil,
+			expectedStatus: http.StatusPreconditionRequired,
+			expectedResponse: map[string]interface{}{
+				"error": "If-Match header or item version is required",
+			},
+		},
+		{
+			name:    "deleted by user",
+			paramID: "1",
+			query:   "?version=2&user_id=3",
+			mockFunc: func(ctx context.Context, id, version int, userID *int) error {
+				if userID == nil || *userID != 3 {
+					return errors.New("unexpected user")
+				}
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully archived item",
+			},
+		},
+		{
+			name:    "already archived",
+			paramID: "1",
+			query:   "?version=2",
+			mockFunc: func(ctx context.Context, id, version int, userID *int) error {
+				return repository.ErrItemArchived
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrItemArchived.Error(),
+			},
+		},
+		{
+			name:    "stale if-match header",
+			paramID: "1",
+			ifMatch: `"4"`,
+			mockFunc: func(ctx context.Context, id, version int, userID *int) error {
+				return repository.ErrVersionConflict
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrVersionConflict.Error(),
+			},
+		},
 		{
 			name:    "service error",
 			paramID: "1",
-			mockFunc: func(ctx context.Context, id int) error {
+			query:   "?version=1",
+			mockFunc: func(ctx context.Context, id, version int, userID *int) error {
 				return errors.New("service error")
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -507,7 +1041,10 @@ func TestHandler_DeleteItem(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
-			c.Request = httptest.NewRequest(http.MethodDelete, "/items/"+tt.paramID, nil)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/items/"+tt.paramID+tt.query, nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
 			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
 
 			mock := &mockService{
@@ -531,3 +1068,226 @@ func TestHandler_DeleteItem(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_RestoreItem(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		mockFunc         func(ctx context.Context, id int) (int, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			mockFunc: func(ctx context.Context, id int) (int, error) {
+				return 7, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status":  "successfully restored item",
+				"version": float64(7),
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid item is or was not provided",
+			},
+		},
+		{
+			name:    "not archived",
+			paramID: "1",
+			mockFunc: func(ctx context.Context, id int) (int, error) {
+				return 0, repository.ErrItemNotArchived
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrItemNotArchived.Error(),
+			},
+		},
+		{
+			name:    "not found",
+			paramID: "99",
+			mockFunc: func(ctx context.Context, id int) (int, error) {
+				return 0, repository.ErrNoSuchItem
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchItem.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/items/"+tt.paramID+"/restore", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				restoreItemFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.RestoreItem(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if w.Code == http.StatusOK && w.Header().Get("ETag") != `"7"` {
+				t.Errorf("expected ETag %q, got %q", `"7"`, w.Header().Get("ETag"))
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_PurgeItem(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		mockFunc         func(ctx context.Context, id int) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			mockFunc: func(ctx context.Context, id int) error {
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully purged item",
+			},
+		},
+		{
+			name:    "active item",
+			paramID: "1",
+			mockFunc: func(ctx context.Context, id int) error {
+				return repository.ErrItemNotArchived
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrItemNotArchived.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/items/"+tt.paramID+"/purge", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				purgeItemFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.PurgeItem(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_GetItemByID(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		mockFunc         func(ctx context.Context, id int) (*model.Item, error)
+		expectedStatus   int
+		expectedETag     string
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			mockFunc: func(ctx context.Context, id int) (*model.Item, error) {
+				return &model.Item{ID: id, Name: "item1", Count: 10, Unit: "pcs", Version: 7}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"7"`,
+			expectedResponse: map[string]interface{}{
+				"name":    "item1",
+				"version": float64(7),
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid item is or was not provided",
+			},
+		},
+		{
+			name:    "not found",
+			paramID: "2",
+			mockFunc: func(ctx context.Context, id int) (*model.Item, error) {
+				return nil, repository.ErrNoSuchItem
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchItem.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/"+tt.paramID, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				getItemByIDFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.GetItemByID(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if etag := w.Header().Get("ETag"); etag != tt.expectedETag {
+				t.Errorf("expected ETag %s, got %s", tt.expectedETag, etag)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Set kit components
+// @Description Replace bill of materials of kit item, component quantity is for one kit and may be given in any unit of component, empty list turns kit back into plain item, requires admin or manager authentication
+// @Tags kits
+// @Accept json
+// @Produce json
+// @Param id path int true "Kit item ID"
+// @Param components body dto.SetKitComponents true "Bill of materials"
+// @Success 200 {object} model.Kit
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/components [put]
+// @Security BearerAuth
+func (h *Handler) SetKitComponents(c *ginext.Context) {
+	kitID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var setComponents dto.SetKitComponents
+	if err := c.BindJSON(&setComponents); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setComponents.KitID = kitID
+
+	if err := validator.Validate.Struct(setComponents); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	kit, err := h.service.SetKitComponents(h.ctx, &setComponents)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and set kit components")
+	c.JSON(http.StatusOK, kit)
+}
+
+// @Summary Get kit
+// @Description Get bill of materials of kit item with available stock of every component and number of kits it is enough for, requires authentication
+// @Tags kits
+// @Produce json
+// @Param id path int true "Kit item ID"
+// @Param warehouse_id query int false "Warehouse to count available stock in, default warehouse if omitted"
+// @Success 200 {object} model.Kit
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/components [get]
+// @Security BearerAuth
+func (h *Handler) GetKit(c *ginext.Context) {
+	kitID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var warehouseID int
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		warehouseID, err = strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+	}
+
+	kit, err := h.service.GetKit(h.ctx, kitID, warehouseID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned kit")
+	c.JSON(http.StatusOK, kit)
+}
+
+// @Summary Assemble kits
+// @Description Take components of quantity kits from available stock and add kits to stock in one operation, requires admin or manager authentication
+// @Tags kits
+// @Accept json
+// @Produce json
+// @Param id path int true "Kit item ID"
+// @Param Idempotency-Key header string false "Key to safely retry the request"
+// @Param assembly body dto.AssembleKit true "Assembly data"
+// @Success 200 {array} model.Movement
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/assemble [post]
+// @Security BearerAuth
+func (h *Handler) AssembleKit(c *ginext.Context) {
+	kitID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var assembleKit dto.AssembleKit
+	if err := c.BindJSON(&assembleKit); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	assembleKit.KitID = kitID
+
+	if err := validator.Validate.Struct(assembleKit); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	movements, err := h.service.AssembleKit(h.ctx, &assembleKit)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and assembled kits")
+	c.JSON(http.StatusOK, movements)
+}
+
+// @Summary Disassemble kits
+// @Description Take quantity kits from available stock and return their components to stock in one operation, requires admin or manager authentication
+// @Tags kits
+// @Accept json
+// @Produce json
+// @Param id path int true "Kit item ID"
+// @Param Idempotency-Key header string false "Key to safely retry the request"
+// @Param disassembly body dto.AssembleKit true "Disassembly data"
+// @Success 200 {array} model.Movement
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/disassemble [post]
+// @Security BearerAuth
+func (h *Handler) DisassembleKit(c *ginext.Context) {
+	kitID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var assembleKit dto.AssembleKit
+	if err := c.BindJSON(&assembleKit); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	assembleKit.KitID = kitID
+
+	if err := validator.Validate.Struct(assembleKit); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	movements, err := h.service.DisassembleKit(h.ctx, &assembleKit)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and disassembled kits")
+	c.JSON(http.StatusOK, movements)
+}
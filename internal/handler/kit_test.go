@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+	"wharehouse-control/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_SetKitComponents(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, setComponents *dto.SetKitComponents) (*model.Kit, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "10",
+			body:    `{"components":[{"item_id":1,"quantity":2},{"item_id":2,"quantity":1,"unit":"box"}]}`,
+			mockFunc: func(ctx context.Context, setComponents *dto.SetKitComponents) (*model.Kit, error) {
+				return &model.Kit{
+					ItemID:      setComponents.KitID,
+					WarehouseID: 1,
+					Components: []model.KitComponent{
+						{ComponentID: 1, Quantity: 2, Available: 7, Kits: 3},
+						{ComponentID: 2, Quantity: 12, Available: 60, Kits: 5},
+					},
+					Available: 3,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"item_id":   float64(10),
+				"available": float64(3),
+			},
+		},
+		{
+			name:           "zero quantity",
+			paramID:        "10",
+			body:           `{"components":[{"item_id":1,"quantity":0}]}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Quantity is not valid due to gt",
+			},
+		},
+		{
+			name:    "kit as own component",
+			paramID: "10",
+			body:    `{"components":[{"item_id":10,"quantity":1}]}`,
+			mockFunc: func(ctx context.Context, setComponents *dto.SetKitComponents) (*model.Kit, error) {
+				return nil, fmt.Errorf("%w: kit cannot be its own component", service.ErrInvalidKit)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid kit: kit cannot be its own component",
+			},
+		},
+		{
+			name:    "nested kit",
+			paramID: "10",
+			body:    `{"components":[{"item_id":11,"quantity":1}]}`,
+			mockFunc: func(ctx context.Context, setComponents *dto.SetKitComponents) (*model.Kit, error) {
+				return nil, repository.ErrKitNesting
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrKitNesting.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/items/"+tt.paramID+"/components", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				setKitComponentsFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.SetKitComponents(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_AssembleKit(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error)
+		expectedStatus   int
+		expectedCount    int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "10",
+			body:    `{"user_id":1,"warehouse_id":1,"quantity":3}`,
+			mockFunc: func(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error) {
+				return []model.Movement{
+					{ID: 1, ItemID: 1, Quantity: -6, Reason: model.MovementReasonKitAssembly},
+					{ID: 2, ItemID: 2, Quantity: -3, Reason: model.MovementReasonKitAssembly},
+					{ID: 3, ItemID: assembleKit.KitID, Quantity: assembleKit.Quantity, Reason: model.MovementReasonKitAssembly},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  3,
+		},
+		{
+			name:           "no user",
+			paramID:        "10",
+			body:           `{"quantity":3}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "UserID is required",
+			},
+		},
+		{
+			name:    "not a kit",
+			paramID: "1",
+			body:    `{"user_id":1,"quantity":1}`,
+			mockFunc: func(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error) {
+				return nil, repository.ErrNoSuchKit
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchKit.Error(),
+			},
+		},
+		{
+			name:    "components short",
+			paramID: "10",
+			body:    `{"user_id":1,"quantity":50}`,
+			mockFunc: func(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error) {
+				return nil, fmt.Errorf("%w: item 1 has 7 available", repository.ErrInsufficientAvailable)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrInsufficientAvailable.Error() + ": item 1 has 7 available",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/items/"+tt.paramID+"/assemble", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				assembleKitFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.AssembleKit(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var movements []model.Movement
+				json.Unmarshal(w.Body.Bytes(), &movements)
+				if len(movements) != tt.expectedCount {
+					t.Errorf("expected %d movements, got %d", tt.expectedCount, len(movements))
+				}
+				return
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
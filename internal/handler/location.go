@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Create a location
+// @Description Create a zone, aisle, rack or bin inside warehouse, nested locations must be deeper than their parent, requires admin authentication
+// @Tags locations
+// @Accept json
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Param location body dto.CreateLocation true "Location creation data"
+// @Success 200 {object} model.Location
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /warehouses/{id}/locations [post]
+// @Security BearerAuth
+func (h *Handler) CreateLocation(c *ginext.Context) {
+	id := c.Param("id")
+	warehouseID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id or was not provided"})
+		return
+	}
+
+	var createLocation dto.CreateLocation
+	if err := c.BindJSON(&createLocation); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	createLocation.WarehouseID = warehouseID
+
+	if err := validator.Validate.Struct(createLocation); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	location, err := h.service.CreateLocation(h.ctx, createLocation)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new location")
+	c.JSON(http.StatusOK, location)
+}
+
+// @Summary Get warehouse locations
+// @Description Get all locations of warehouse ordered by their path, requires authentication
+// @Tags locations
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Success 200 {array} model.Location
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /warehouses/{id}/locations [get]
+// @Security BearerAuth
+func (h *Handler) GetLocations(c *ginext.Context) {
+	id := c.Param("id")
+	warehouseID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id or was not provided"})
+		return
+	}
+
+	locations, err := h.service.GetLocations(h.ctx, warehouseID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned warehouse locations")
+	c.JSON(http.StatusOK, locations)
+}
+
+// @Summary Delete a location
+// @Description Delete location without stock and nested locations, requires admin authentication
+// @Tags locations
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Param location_id path int true "Location ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /warehouses/{id}/locations/{location_id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteLocation(c *ginext.Context) {
+	warehouseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id or was not provided"})
+		return
+	}
+
+	locationID, err := strconv.Atoi(c.Param("location_id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location id or was not provided"})
+		return
+	}
+
+	if err := h.service.DeleteLocation(h.ctx, warehouseID, locationID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted location")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted location"})
+}
+
+// @Summary Get item locations
+// @Description Get where item stock physically sits, entries without location_id hold stock not put away to any location yet, requires authentication
+// @Tags locations
+// @Produce json
+// @Param id path int true "Item ID"
+// @Success 200 {array} model.ItemLocation
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/locations [get]
+// @Security BearerAuth
+func (h *Handler) GetItemLocations(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	locations, err := h.service.GetItemLocations(h.ctx, itemID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned item locations")
+	c.JSON(http.StatusOK, locations)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_GetItemLocations(t *testing.T) {
+	binID := 7
+
+	tests := []struct {
+		name             string
+		paramID          string
+		mockFunc         func(ctx context.Context, itemID int) ([]model.ItemLocation, error)
+		expectedStatus   int
+		expectedResponse []map[string]interface{}
+		expectedError    string
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			mockFunc: func(ctx context.Context, itemID int) ([]model.ItemLocation, error) {
+				return []model.ItemLocation{
+					{WarehouseID: 1, WarehouseName: "Main", LocationID: &binID, Path: "A-03-B-2", Quantity: 40},
+					{WarehouseID: 1, WarehouseName: "Main", Quantity: 5},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: []map[string]interface{}{
+				{
+					"location_id": float64(7),
+					"path":        "A-03-B-2",
+					"quantity":    float64(40),
+				},
+				{
+					"location_id": nil,
+					"quantity":    float64(5),
+				},
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid item is or was not provided",
+		},
+		{
+			name:    "no such item",
+			paramID: "42",
+			mockFunc: func(ctx context.Context, itemID int) ([]model.ItemLocation, error) {
+				return nil, repository.ErrNoSuchItem
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  repository.ErrNoSuchItem.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/"+tt.paramID+"/locations", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				getItemLocationsFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.GetItemLocations(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedResponse != nil {
+				var resp []map[string]interface{}
+				json.Unmarshal(w.Body.Bytes(), &resp)
+				if len(resp) != len(tt.expectedResponse) {
+					t.Fatalf("expected %d locations, got %d", len(tt.expectedResponse), len(resp))
+				}
+				for i, expected := range tt.expectedResponse {
+					for k, v := range expected {
+						if resp[i][k] != v {
+							t.Errorf("expected %v for %s, got %v", v, k, resp[i][k])
+						}
+					}
+				}
+			} else {
+				var resp map[string]interface{}
+				json.Unmarshal(w.Body.Bytes(), &resp)
+				if resp["error"] != tt.expectedError {
+					t.Errorf("expected error '%s', got %v", tt.expectedError, resp["error"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+const defaultExpiringDays = 30
+
+// @Summary Create a lot
+// @Description Register production batch of item with manufacture and expiry dates in YYYY-MM-DD format, requires admin authentication
+// @Tags lots
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param lot body dto.CreateLot true "Lot data"
+// @Success 200 {object} model.Lot
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/lots [post]
+// @Security BearerAuth
+func (h *Handler) CreateLot(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var createLot dto.CreateLot
+	if err := c.BindJSON(&createLot); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	createLot.ItemID = itemID
+
+	if err := validator.Validate.Struct(createLot); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	lot, err := h.service.CreateLot(h.ctx, createLot)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new lot")
+	c.JSON(http.StatusOK, lot)
+}
+
+// @Summary Get item lots
+// @Description Get lots of item with their total stock, soonest expiring first, requires authentication
+// @Tags lots
+// @Produce json
+// @Param id path int true "Item ID"
+// @Success 200 {array} model.Lot
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/lots [get]
+// @Security BearerAuth
+func (h *Handler) GetLots(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	lots, err := h.service.GetLots(h.ctx, itemID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned item lots")
+	c.JSON(http.StatusOK, lots)
+}
+
+// @Summary Suggest lots to pick
+// @Description Spread quantity over unexpired lots first-expired-first-out, shortage is quantity no lot covers, requires authentication
+// @Tags lots
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param quantity query number true "Quantity to pick"
+// @Param unit query string false "Unit quantity is given in, item base unit by default"
+// @Param warehouse_id query int false "Warehouse to pick from, default warehouse if omitted"
+// @Success 200 {object} model.PickSuggestion
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/fefo [get]
+// @Security BearerAuth
+func (h *Handler) SuggestPick(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	pickRequest := dto.PickRequest{ItemID: itemID, Unit: c.Query("unit")}
+	pickRequest.Quantity, err = strconv.ParseFloat(c.Query("quantity"), 64)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid quantity: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid quantity or was not provided"})
+		return
+	}
+
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		pickRequest.WarehouseID, err = strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+	}
+
+	suggestion, err := h.service.SuggestPick(h.ctx, pickRequest)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and suggested lots to pick")
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// @Summary Get expiring lots
+// @Description Get lots in stock that expire within given number of days including already expired ones, requires authentication
+// @Tags lots
+// @Produce json
+// @Param days query int false "Days ahead, 30 by default"
+// @Param warehouse_id query int false "Only stock in this warehouse"
+// @Success 200 {array} model.ExpiringLot
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /lots/expiring [get]
+// @Security BearerAuth
+func (h *Handler) GetExpiringLots(c *ginext.Context) {
+	days := defaultExpiringDays
+	if daysParam := c.Query("days"); daysParam != "" {
+		var err error
+		days, err = strconv.Atoi(daysParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid days: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days"})
+			return
+		}
+	}
+
+	var warehouseID *int
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		id, err := strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+		warehouseID = &id
+	}
+
+	lots, err := h.service.GetExpiringLots(h.ctx, days, warehouseID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned expiring lots")
+	c.JSON(http.StatusOK, lots)
+}
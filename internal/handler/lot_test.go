@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_CreateLot(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, createLot dto.CreateLot) (*model.Lot, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			body:    `{"lot_number":"L-2025-01","manufactured_at":"2025-01-10","expires_at":"2026-01-10"}`,
+			mockFunc: func(ctx context.Context, createLot dto.CreateLot) (*model.Lot, error) {
+				return &model.Lot{ID: 1, ItemID: createLot.ItemID, LotNumber: createLot.LotNumber}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"item_id":    float64(1),
+				"lot_number": "L-2025-01",
+			},
+		},
+		{
+			name:           "invalid date",
+			paramID:        "1",
+			body:           `{"lot_number":"L-2025-01","expires_at":"10.01.2026"}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "ExpiresAt is not valid due to datetime",
+			},
+		},
+		{
+			name:    "duplicate lot number",
+			paramID: "1",
+			body:    `{"lot_number":"L-2025-01"}`,
+			mockFunc: func(ctx context.Context, createLot dto.CreateLot) (*model.Lot, error) {
+				return nil, repository.ErrLotExists
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrLotExists.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/items/"+tt.paramID+"/lots", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				createLotFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.CreateLot(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_SuggestPick(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		query            string
+		mockFunc         func(ctx context.Context, pickRequest dto.PickRequest) (*model.PickSuggestion, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			query:   "?quantity=12&warehouse_id=2",
+			mockFunc: func(ctx context.Context, pickRequest dto.PickRequest) (*model.PickSuggestion, error) {
+				return &model.PickSuggestion{
+					ItemID:      pickRequest.ItemID,
+					WarehouseID: pickRequest.WarehouseID,
+					Requested:   pickRequest.Quantity,
+					Shortage:    2,
+					Picks: []model.LotPick{
+						{LotID: 3, LotNumber: "L-1", Available: 10, Quantity: 10},
+					},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"warehouse_id": float64(2),
+				"requested":    float64(12),
+				"shortage":     float64(2),
+			},
+		},
+		{
+			name:           "missing quantity",
+			paramID:        "1",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid quantity or was not provided",
+			},
+		},
+		{
+			name:    "no such item",
+			paramID: "9",
+			query:   "?quantity=1",
+			mockFunc: func(ctx context.Context, pickRequest dto.PickRequest) (*model.PickSuggestion, error) {
+				return nil, repository.ErrNoSuchItem
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchItem.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/"+tt.paramID+"/fefo"+tt.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				suggestPickFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.SuggestPick(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
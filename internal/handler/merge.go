@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+const maxDuplicateLimit = 500
+
+// @Summary Get duplicate items
+// @Description Suggest pairs of active items with similar names, names are compared by trigram similarity after case and spaces are normalized, most similar pairs first, requires authentication
+// @Tags items
+// @Produce json
+// @Param item_id query int false "Only pairs with this item"
+// @Param threshold query number false "Lowest similarity from 0 to 1, configured value by default"
+// @Param limit query int false "Largest number of pairs, 50 by default"
+// @Success 200 {array} model.DuplicatePair
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/duplicates [get]
+// @Security BearerAuth
+func (h *Handler) GetDuplicateItems(c *ginext.Context) {
+	var filter dto.DuplicateFilter
+	if itemParam := c.Query("item_id"); itemParam != "" {
+		itemID, err := strconv.Atoi(itemParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid item id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+			return
+		}
+		filter.ItemID = &itemID
+	}
+
+	if thresholdParam := c.Query("threshold"); thresholdParam != "" {
+		threshold, err := strconv.ParseFloat(thresholdParam, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			zlog.Logger.Error().Msg("invalid threshold: " + thresholdParam)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid threshold, must be above 0 and at most 1"})
+			return
+		}
+		filter.Threshold = threshold
+	}
+
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit <= 0 || limit > maxDuplicateLimit {
+			zlog.Logger.Error().Msg("invalid limit: " + limitParam)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit, must be from 1 to 500"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	pairs, err := h.service.GetDuplicateItems(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned duplicate items")
+	c.JSON(http.StatusOK, pairs)
+}
+
+// @Summary Merge items
+// @Description Merge item into another one: stock, movements, cost layers, change history, lots, serials, documents, attachments and tags move to target, quantities in the same warehouse or location are added up. Merged item is archived with merged_into pointing to target and cannot be restored. Items must have the same base unit and serial tracking, requires admin authentication
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path int true "Item to merge"
+// @Param merge body dto.MergeItems true "Target item and user"
+// @Success 200 {object} model.Item
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/merge [post]
+// @Security BearerAuth
+func (h *Handler) MergeItems(c *ginext.Context) {
+	sourceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var mergeItems dto.MergeItems
+	if err := c.BindJSON(&mergeItems); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mergeItems.SourceID = sourceID
+
+	if err := validator.Validate.Struct(mergeItems); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	item, err := h.service.MergeItems(h.ctx, mergeItems)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and merged items")
+	c.JSON(http.StatusOK, item)
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+	"wharehouse-control/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_GetDuplicateItems(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockFunc       func(ctx context.Context, filter dto.DuplicateFilter) ([]model.DuplicatePair, error)
+		expectedStatus int
+		expectedPairs  int
+	}{
+		{
+			name:  "success",
+			query: "?threshold=0.4&limit=10",
+			mockFunc: func(ctx context.Context, filter dto.DuplicateFilter) ([]model.DuplicatePair, error) {
+				if filter.Threshold != 0.4 || filter.Limit != 10 || filter.ItemID != nil {
+					return nil, errors.New("unexpected filter")
+				}
+				return []model.DuplicatePair{
+					{ItemID: 1, ItemName: "Bolt M6", DuplicateID: 2, DuplicateName: "bolt m6 ", Similarity: 1},
+					{ItemID: 3, ItemName: "Bolt M8", DuplicateID: 4, DuplicateName: "Bolts M8", Similarity: 0.7},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedPairs:  2,
+		},
+		{
+			name:  "for item",
+			query: "?item_id=3",
+			mockFunc: func(ctx context.Context, filter dto.DuplicateFilter) ([]model.DuplicatePair, error) {
+				if filter.ItemID == nil || *filter.ItemID != 3 || filter.Threshold != 0 {
+					return nil, errors.New("unexpected filter")
+				}
+				return []model.DuplicatePair{{ItemID: 3, DuplicateID: 4, Similarity: 0.7}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedPairs:  1,
+		},
+		{
+			name:           "threshold out of range",
+			query:          "?threshold=1.5",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid limit",
+			query:          "?limit=0",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/duplicates"+tt.query, nil)
+
+			mock := &mockService{
+				getDuplicateItemsFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.GetDuplicateItems(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp []map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			if len(resp) != tt.expectedPairs {
+				t.Errorf("expected %d pairs, got %d", tt.expectedPairs, len(resp))
+			}
+		})
+	}
+}
+
+func TestHandler_MergeItems(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, mergeItems dto.MergeItems) (*model.Item, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "2",
+			body:    `{"into_id": 1, "user_id": 1}`,
+			mockFunc: func(ctx context.Context, mergeItems dto.MergeItems) (*model.Item, error) {
+				if mergeItems.SourceID != 2 || mergeItems.TargetID != 1 {
+					return nil, errors.New("unexpected merge")
+				}
+				return &model.Item{ID: 1, Name: "Bolt M6", Count: 150}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"id":    float64(1),
+				"count": float64(150),
+			},
+		},
+		{
+			name:           "missing target",
+			paramID:        "2",
+			body:           `{"user_id": 1}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "into itself",
+			paramID: "1",
+			body:    `{"into_id": 1, "user_id": 1}`,
+			mockFunc: func(ctx context.Context, mergeItems dto.MergeItems) (*model.Item, error) {
+				return nil, fmt.Errorf("%w: item cannot be merged into itself", service.ErrInvalidMerge)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid merge: item cannot be merged into itself",
+			},
+		},
+		{
+			name:    "different units",
+			paramID: "2",
+			body:    `{"into_id": 1, "user_id": 1}`,
+			mockFunc: func(ctx context.Context, mergeItems dto.MergeItems) (*model.Item, error) {
+				return nil, fmt.Errorf("%w: items have different base units", repository.ErrMergeConflict)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": "items cannot be merged: items have different base units",
+			},
+		},
+		{
+			name:    "target not found",
+			paramID: "2",
+			body:    `{"into_id": 99, "user_id": 1}`,
+			mockFunc: func(ctx context.Context, mergeItems dto.MergeItems) (*model.Item, error) {
+				return nil, repository.ErrNoSuchItem
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/items/"+tt.paramID+"/merge", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				mergeItemsFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.MergeItems(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
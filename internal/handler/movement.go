@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/middleware"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Get item stock movements
+// @Description Get stock ledger of item newest first, on-hand quantity is the sum of all movements, requires authentication
+// @Tags movements
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param warehouse_id query int false "Only movements in this warehouse"
+// @Param limit query int false "Maximum number of movements, 100 by default"
+// @Success 200 {array} model.Movement
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/movements [get]
+// @Security BearerAuth
+func (h *Handler) GetMovements(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	filter := dto.MovementFilter{ItemID: itemID}
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		warehouseID, err := strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+		filter.WarehouseID = &warehouseID
+	}
+
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid limit: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	movements, err := h.service.GetMovements(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned item movements")
+	c.JSON(http.StatusOK, movements)
+}
+
+// @Summary Adjust item stock
+// @Description Change item stock by signed delta with reason code, the change is relative so concurrent adjustments do not overwrite each other, requires admin or manager authentication, adjustment of manager that breaks approval rules is saved as change request
+// @Tags movements
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param Idempotency-Key header string false "Key to safely retry the request"
+// @Param adjustment body dto.AdjustItem true "Adjustment data"
+// @Success 201 {object} model.Movement
+// @Success 202 {object} model.ChangeRequest
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/adjust [post]
+// @Security BearerAuth
+func (h *Handler) AdjustItem(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var adjustItem dto.AdjustItem
+	if err := c.BindJSON(&adjustItem); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adjustItem.ID = itemID
+
+	if err := validator.Validate.Struct(adjustItem); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if middleware.IsManager(c) {
+		changeRequest, err := h.service.RequestItemAdjust(h.ctx, &adjustItem)
+		if err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+			c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+			return
+		}
+		if changeRequest != nil {
+			zlog.Logger.Info().Msg("successfully handled request and created change request for stock adjustment")
+			c.JSON(http.StatusAccepted, changeRequest)
+			return
+		}
+	}
+
+	movement, err := h.service.AdjustItem(h.ctx, &adjustItem)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and adjusted item stock")
+	c.JSON(http.StatusCreated, movement)
+}
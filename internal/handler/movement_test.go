@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+	"wharehouse-control/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_GetMovements(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		query            string
+		mockFunc         func(ctx context.Context, filter dto.MovementFilter) ([]model.Movement, error)
+		expectedStatus   int
+		expectedResponse []map[string]interface{}
+		expectedError    string
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			query:   "?warehouse_id=2&limit=10",
+			mockFunc: func(ctx context.Context, filter dto.MovementFilter) ([]model.Movement, error) {
+				if filter.ItemID != 1 || filter.WarehouseID == nil || *filter.WarehouseID != 2 || filter.Limit != 10 {
+					return nil, errors.New("unexpected filter")
+				}
+				return []model.Movement{
+					{ID: 2, ItemID: 1, WarehouseID: 2, Quantity: -3, Reason: model.MovementReasonCorrection, CreatedAt: time.Now()},
+					{ID: 1, ItemID: 1, WarehouseID: 2, Quantity: 10, Reason: model.MovementReasonInitial, CreatedAt: time.Now()},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: []map[string]interface{}{
+				{
+					"id":       float64(2),
+					"quantity": float64(-3),
+					"reason":   "correction",
+				},
+				{
+					"id":       float64(1),
+					"quantity": float64(10),
+					"reason":   "initial",
+				},
+			},
+		},
+		{
+			name:           "invalid limit",
+			paramID:        "1",
+			query:          "?limit=many",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid limit",
+		},
+		{
+			name:    "service error",
+			paramID: "1",
+			mockFunc: func(ctx context.Context, filter dto.MovementFilter) ([]model.Movement, error) {
+				return nil, errors.New("service error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "service error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/"+tt.paramID+"/movements"+tt.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				getMovementsFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.GetMovements(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedResponse != nil {
+				var resp []map[string]interface{}
+				json.Unmarshal(w.Body.Bytes(), &resp)
+				if len(resp) != len(tt.expectedResponse) {
+					t.Fatalf("expected %d movements, got %d", len(tt.expectedResponse), len(resp))
+				}
+				for i, expected := range tt.expectedResponse {
+					for k, v := range expected {
+						if resp[i][k] != v {
+							t.Errorf("expected %v for %s, got %v", v, k, resp[i][k])
+						}
+					}
+				}
+			} else {
+				var resp map[string]interface{}
+				json.Unmarshal(w.Body.Bytes(), &resp)
+				if resp["error"] != tt.expectedError {
+					t.Errorf("expected error '%s', got %v", tt.expectedError, resp["error"])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_AdjustItem(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			body:    `{"user_id":1,"delta":-2,"reason":"damage","reference":"broken on shelf"}`,
+			mockFunc: func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error) {
+				if adjustItem.ID != 1 || adjustItem.Delta != -2 {
+					return nil, errors.New("unexpected adjustment")
+				}
+				return &model.Movement{
+					ID:          7,
+					ItemID:      adjustItem.ID,
+					WarehouseID: 1,
+					Quantity:    adjustItem.Delta,
+					Reason:      adjustItem.Reason,
+					Reference:   adjustItem.Reference,
+					UserID:      &adjustItem.UserID,
+					CreatedAt:   time.Now(),
+				}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResponse: map[string]interface{}{
+				"id":       float64(7),
+				"quantity": float64(-2),
+				"reason":   "damage",
+			},
+		},
+		{
+			name:           "unknown reason",
+			paramID:        "1",
+			body:           `{"user_id":1,"delta":-2,"reason":"lunch"}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Reason is not valid due to oneof",
+			},
+		},
+		{
+			name:           "zero delta",
+			paramID:        "1",
+			body:           `{"user_id":1,"delta":0,"reason":"found"}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Delta is required",
+			},
+		},
+		{
+			name:    "insufficient stock",
+			paramID: "1",
+			body:    `{"user_id":1,"delta":-100,"reason":"theft"}`,
+			mockFunc: func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error) {
+				return nil, repository.ErrInsufficientStock
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrInsufficientStock.Error(),
+			},
+		},
+		{
+			name:    "unit cost on outbound",
+			paramID: "1",
+			body:    `{"user_id":1,"delta":-2,"reason":"damage","unit_cost":12.5}`,
+			mockFunc: func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error) {
+				return nil, fmt.Errorf("%w: unit cost is only for stock that comes in", service.ErrInvalidCost)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid cost: unit cost is only for stock that comes in",
+			},
+		},
+		{
+			name:    "serials do not match delta",
+			paramID: "1",
+			body:    `{"user_id":1,"delta":-2,"reason":"damage","serials":["SN-0001"]}`,
+			mockFunc: func(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error) {
+				return nil, repository.ErrSerialsMismatch
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrSerialsMismatch.Error(),
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			body:           `{"user_id":1,"delta":1,"reason":"found"}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid item is or was not provided",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/items/"+tt.paramID+"/adjust", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				adjustItemFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.AdjustItem(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
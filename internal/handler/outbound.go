@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Create an outbound order
+// @Description Create order to ship goods to customer, ordered quantity is held like a reservation until the order is shipped or cancelled, requires admin or manager authentication
+// @Tags outbound orders
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key to safely retry the request"
+// @Param order body dto.CreateOutboundOrder true "Outbound order data"
+// @Success 200 {object} model.OutboundOrder
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /outbound-orders [post]
+// @Security BearerAuth
+func (h *Handler) CreateOutboundOrder(c *ginext.Context) {
+	var createOrder dto.CreateOutboundOrder
+
+	if err := c.BindJSON(&createOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createOrder); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	order, err := h.service.CreateOutboundOrder(h.ctx, createOrder)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new outbound order")
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Get outbound orders
+// @Description Get outbound orders without lines and picks, requires authentication
+// @Tags outbound orders
+// @Produce json
+// @Param warehouse_id query int false "Only orders shipped from this warehouse"
+// @Param status query string false "Only orders in this status: new, picking, packed, shipped or cancelled"
+// @Success 200 {array} model.OutboundOrder
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /outbound-orders [get]
+// @Security BearerAuth
+func (h *Handler) GetOutboundOrders(c *ginext.Context) {
+	var filter dto.OutboundOrderFilter
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		warehouseID, err := strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+		filter.WarehouseID = &warehouseID
+	}
+
+	switch status := c.Query("status"); status {
+	case "", model.OutboundOrderStatusNew, model.OutboundOrderStatusPicking, model.OutboundOrderStatusPacked,
+		model.OutboundOrderStatusShipped, model.OutboundOrderStatusCancelled:
+		filter.Status = status
+	default:
+		zlog.Logger.Error().Msg("invalid outbound order status: " + status)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid outbound order status"})
+		return
+	}
+
+	orders, err := h.service.GetOutboundOrders(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned outbound orders")
+	c.JSON(http.StatusOK, orders)
+}
+
+// @Summary Get outbound order
+// @Description Get outbound order by ID with its lines and pick list, requires authentication
+// @Tags outbound orders
+// @Produce json
+// @Param id path int true "Outbound order ID"
+// @Success 200 {object} model.OutboundOrder
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /outbound-orders/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetOutboundOrderByID(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid outbound order id or was not provided"})
+		return
+	}
+
+	order, err := h.service.GetOutboundOrderByID(h.ctx, orderID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned outbound order")
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Generate pick list
+// @Description Spread order lines over locations and lots of the warehouse, lots are picked first-expired-first-out and the list is sorted in route order, generating again replaces previous list, requires admin or manager authentication
+// @Tags outbound orders
+// @Produce json
+// @Param id path int true "Outbound order ID"
+// @Success 200 {object} model.OutboundOrder
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /outbound-orders/{id}/pick-list [post]
+// @Security BearerAuth
+func (h *Handler) GeneratePickList(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid outbound order id or was not provided"})
+		return
+	}
+
+	order, err := h.service.GeneratePickList(h.ctx, orderID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and generated pick list")
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Confirm packing
+// @Description Confirm picked goods are packed, serial numbers of serialized items and location of picks without one, such as lot picks, are given per pick, requires admin or manager authentication
+// @Tags outbound orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Outbound order ID"
+// @Param packing body dto.PackOutboundOrder true "Packing data"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /outbound-orders/{id}/pack [post]
+// @Security BearerAuth
+func (h *Handler) PackOutboundOrder(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid outbound order id or was not provided"})
+		return
+	}
+
+	var packOrder dto.PackOutboundOrder
+	if err := c.BindJSON(&packOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	packOrder.ID = orderID
+
+	if err := validator.Validate.Struct(packOrder); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.service.PackOutboundOrder(h.ctx, &packOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and packed outbound order")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully packed outbound order"})
+}
+
+// @Summary Ship an outbound order
+// @Description Post shipment movements for every pick, stock leaves the warehouse and the order is closed as shipped, requires admin or manager authentication
+// @Tags outbound orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Outbound order ID"
+// @Param shipment body dto.ShipOutboundOrder true "Shipment data"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /outbound-orders/{id}/ship [post]
+// @Security BearerAuth
+func (h *Handler) ShipOutboundOrder(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid outbound order id or was not provided"})
+		return
+	}
+
+	var shipOrder dto.ShipOutboundOrder
+	if err := c.BindJSON(&shipOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	shipOrder.ID = orderID
+
+	if err := validator.Validate.Struct(shipOrder); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.service.ShipOutboundOrder(h.ctx, &shipOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and shipped outbound order")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully shipped outbound order"})
+}
+
+// @Summary Cancel an outbound order
+// @Description Cancel order that is not shipped yet, stock held by it becomes available again, requires admin or manager authentication
+// @Tags outbound orders
+// @Produce json
+// @Param id path int true "Outbound order ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /outbound-orders/{id}/cancel [post]
+// @Security BearerAuth
+func (h *Handler) CancelOutboundOrder(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid outbound order id or was not provided"})
+		return
+	}
+
+	if err := h.service.CancelOutboundOrder(h.ctx, orderID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and cancelled outbound order")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully cancelled outbound order"})
+}
@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+	"wharehouse-control/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_CreateOutboundOrder(t *testing.T) {
+	errInsufficient := fmt.Errorf("%w: item 2", repository.ErrInsufficientAvailable)
+
+	tests := []struct {
+		name             string
+		body             string
+		mockFunc         func(ctx context.Context, createOrder dto.CreateOutboundOrder) (*model.OutboundOrder, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "success",
+			body: `{"user_id":1,"customer":"ACME","lines":[{"item_id":1,"quantity":3}]}`,
+			mockFunc: func(ctx context.Context, createOrder dto.CreateOutboundOrder) (*model.OutboundOrder, error) {
+				return &model.OutboundOrder{
+					ID:       1,
+					Customer: createOrder.Customer,
+					Status:   model.OutboundOrderStatusNew,
+					Lines:    []model.OutboundOrderLine{{ID: 1, OrderID: 1, ItemID: 1, Quantity: 3}},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"customer": "ACME",
+				"status":   "new",
+			},
+		},
+		{
+			name:           "no customer",
+			body:           `{"user_id":1,"lines":[{"item_id":1,"quantity":3}]}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Customer is required",
+			},
+		},
+		{
+			name:           "no lines",
+			body:           `{"user_id":1,"customer":"ACME","lines":[]}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Lines is not valid due to min",
+			},
+		},
+		{
+			name: "item ordered twice",
+			body: `{"user_id":1,"customer":"ACME","lines":[{"item_id":1,"quantity":3},{"item_id":1,"quantity":1}]}`,
+			mockFunc: func(ctx context.Context, createOrder dto.CreateOutboundOrder) (*model.OutboundOrder, error) {
+				return nil, service.ErrInvalidOutboundOrder
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": service.ErrInvalidOutboundOrder.Error(),
+			},
+		},
+		{
+			name: "not enough available",
+			body: `{"user_id":1,"customer":"ACME","lines":[{"item_id":2,"quantity":100}]}`,
+			mockFunc: func(ctx context.Context, createOrder dto.CreateOutboundOrder) (*model.OutboundOrder, error) {
+				return nil, errInsufficient
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": errInsufficient.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/outbound-orders", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			mock := &mockService{
+				createOutboundOrderFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.CreateOutboundOrder(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_GeneratePickList(t *testing.T) {
+	errShortage := fmt.Errorf("%w: 2 of item 1 cannot be picked", repository.ErrInsufficientStock)
+
+	tests := []struct {
+		name             string
+		paramID          string
+		mockFunc         func(ctx context.Context, orderID int) (*model.OutboundOrder, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			mockFunc: func(ctx context.Context, orderID int) (*model.OutboundOrder, error) {
+				locationID := 4
+				return &model.OutboundOrder{
+					ID:     orderID,
+					Status: model.OutboundOrderStatusPicking,
+					Picks: []model.Pick{
+						{ID: 1, OrderID: orderID, LineID: 1, Sequence: 1, ItemID: 1, LocationID: &locationID, Path: "A/01", Quantity: 3},
+					},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"id":     float64(1),
+				"status": "picking",
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid outbound order id or was not provided",
+			},
+		},
+		{
+			name:    "not found",
+			paramID: "99",
+			mockFunc: func(ctx context.Context, orderID int) (*model.OutboundOrder, error) {
+				return nil, repository.ErrNoSuchOutboundOrder
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchOutboundOrder.Error(),
+			},
+		},
+		{
+			name:    "already packed",
+			paramID: "2",
+			mockFunc: func(ctx context.Context, orderID int) (*model.OutboundOrder, error) {
+				return nil, repository.ErrOutboundOrderStatus
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrOutboundOrderStatus.Error(),
+			},
+		},
+		{
+			name:    "stock shortage",
+			paramID: "3",
+			mockFunc: func(ctx context.Context, orderID int) (*model.OutboundOrder, error) {
+				return nil, errShortage
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": errShortage.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/outbound-orders/"+tt.paramID+"/pick-list", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				generatePickListFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.GeneratePickList(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ShipOutboundOrder(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, shipOrder *dto.ShipOutboundOrder) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			body:    `{"user_id":1,"carrier":"DHL","tracking_number":"JD0001"}`,
+			mockFunc: func(ctx context.Context, shipOrder *dto.ShipOutboundOrder) error {
+				if shipOrder.ID != 1 {
+					return repository.ErrNoSuchOutboundOrder
+				}
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully shipped outbound order",
+			},
+		},
+		{
+			name:           "no user",
+			paramID:        "1",
+			body:           `{"carrier":"DHL"}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "UserID is required",
+			},
+		},
+		{
+			name:    "not packed",
+			paramID: "2",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, shipOrder *dto.ShipOutboundOrder) error {
+				return repository.ErrOutboundOrderStatus
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrOutboundOrderStatus.Error(),
+			},
+		},
+		{
+			name:    "serials missing",
+			paramID: "3",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, shipOrder *dto.ShipOutboundOrder) error {
+				return repository.ErrSerialsMismatch
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrSerialsMismatch.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/outbound-orders/"+tt.paramID+"/ship", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				shipOutboundOrderFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.ShipOutboundOrder(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
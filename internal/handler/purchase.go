@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Create a purchase order
+// @Description Create draft order to supplier, line prices default to supplier price list, requires admin or manager authentication
+// @Tags purchase orders
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key to safely retry the request"
+// @Param order body dto.CreatePurchaseOrder true "Purchase order data"
+// @Success 200 {object} model.PurchaseOrder
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /purchase-orders [post]
+// @Security BearerAuth
+func (h *Handler) CreatePurchaseOrder(c *ginext.Context) {
+	var createOrder dto.CreatePurchaseOrder
+
+	if err := c.BindJSON(&createOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createOrder); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	order, err := h.service.CreatePurchaseOrder(h.ctx, createOrder)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new purchase order")
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Create purchase orders from low stock
+// @Description Draft one order per supplier and warehouse for stock below reorder point, every item goes to its cheapest supplier and quantity already on order is not ordered again, requires admin or manager authentication
+// @Tags purchase orders
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key to safely retry the request"
+// @Param request body dto.CreateLowStockOrders true "Author and optional warehouse"
+// @Success 200 {object} model.LowStockOrders
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /purchase-orders/from-low-stock [post]
+// @Security BearerAuth
+func (h *Handler) CreateLowStockOrders(c *ginext.Context) {
+	var createOrders dto.CreateLowStockOrders
+
+	if err := c.BindJSON(&createOrders); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createOrders); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	orders, err := h.service.CreateLowStockOrders(h.ctx, createOrders)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created purchase orders from low stock")
+	c.JSON(http.StatusOK, orders)
+}
+
+// @Summary Get purchase orders
+// @Description Get purchase orders without lines, requires authentication
+// @Tags purchase orders
+// @Produce json
+// @Param supplier_id query int false "Only orders to this supplier"
+// @Param status query string false "Only orders in this status: draft, sent, partially_received or closed"
+// @Success 200 {array} model.PurchaseOrder
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /purchase-orders [get]
+// @Security BearerAuth
+func (h *Handler) GetPurchaseOrders(c *ginext.Context) {
+	var filter dto.PurchaseOrderFilter
+	if supplierParam := c.Query("supplier_id"); supplierParam != "" {
+		supplierID, err := strconv.Atoi(supplierParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid supplier id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid supplier id"})
+			return
+		}
+		filter.SupplierID = &supplierID
+	}
+
+	switch status := c.Query("status"); status {
+	case "", model.PurchaseOrderStatusDraft, model.PurchaseOrderStatusSent,
+		model.PurchaseOrderStatusPartiallyReceived, model.PurchaseOrderStatusClosed:
+		filter.Status = status
+	default:
+		zlog.Logger.Error().Msg("invalid purchase order status: " + status)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid purchase order status"})
+		return
+	}
+
+	orders, err := h.service.GetPurchaseOrders(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned purchase orders")
+	c.JSON(http.StatusOK, orders)
+}
+
+// @Summary Get purchase order
+// @Description Get purchase order by ID with its lines, requires authentication
+// @Tags purchase orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} model.PurchaseOrder
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /purchase-orders/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetPurchaseOrderByID(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid purchase order id or was not provided"})
+		return
+	}
+
+	order, err := h.service.GetPurchaseOrderByID(h.ctx, orderID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned purchase order")
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Add purchase order line
+// @Description Add item to draft purchase order, requires admin or manager authentication
+// @Tags purchase orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param line body dto.PurchaseOrderLine true "Order line"
+// @Success 200 {object} model.PurchaseOrderLine
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /purchase-orders/{id}/lines [post]
+// @Security BearerAuth
+func (h *Handler) AddPurchaseOrderLine(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid purchase order id or was not provided"})
+		return
+	}
+
+	var addLine dto.AddPurchaseOrderLine
+	if err := c.BindJSON(&addLine.PurchaseOrderLine); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	addLine.PurchaseOrderID = orderID
+
+	if err := validator.Validate.Struct(addLine); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	line, err := h.service.AddPurchaseOrderLine(h.ctx, addLine)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and added purchase order line")
+	c.JSON(http.StatusOK, line)
+}
+
+// @Summary Delete purchase order line
+// @Description Remove line from draft purchase order, requires admin authentication
+// @Tags purchase orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param line_id path int true "Line ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /purchase-orders/{id}/lines/{line_id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeletePurchaseOrderLine(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid purchase order id or was not provided"})
+		return
+	}
+
+	lineID, err := strconv.Atoi(c.Param("line_id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid line id or was not provided"})
+		return
+	}
+
+	err = h.service.DeletePurchaseOrderLine(h.ctx, orderID, lineID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted purchase order line")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted purchase order line"})
+}
+
+// @Summary Send a purchase order
+// @Description Move draft order to sent, expected delivery date follows supplier lead time, requires admin or manager authentication
+// @Tags purchase orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /purchase-orders/{id}/send [post]
+// @Security BearerAuth
+func (h *Handler) SendPurchaseOrder(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid purchase order id or was not provided"})
+		return
+	}
+
+	if err := h.service.SendPurchaseOrder(h.ctx, orderID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and sent purchase order")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully sent purchase order"})
+}
+
+// @Summary Close a purchase order
+// @Description Close order nothing more is expected from, closing a draft abandons it, requires admin or manager authentication
+// @Tags purchase orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /purchase-orders/{id}/close [post]
+// @Security BearerAuth
+func (h *Handler) ClosePurchaseOrder(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid purchase order id or was not provided"})
+		return
+	}
+
+	if err := h.service.ClosePurchaseOrder(h.ctx, orderID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and closed purchase order")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully closed purchase order"})
+}
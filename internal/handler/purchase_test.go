@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+	"wharehouse-control/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_CreatePurchaseOrder(t *testing.T) {
+	errNoPrice := fmt.Errorf("%w: no price for item 3", service.ErrInvalidPurchaseOrder)
+
+	tests := []struct {
+		name             string
+		body             string
+		mockFunc         func(ctx context.Context, createOrder dto.CreatePurchaseOrder) (*model.PurchaseOrder, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "success",
+			body: `{"supplier_id":1,"user_id":1,"lines":[{"item_id":3,"quantity":10}]}`,
+			mockFunc: func(ctx context.Context, createOrder dto.CreatePurchaseOrder) (*model.PurchaseOrder, error) {
+				return &model.PurchaseOrder{
+					ID:         1,
+					SupplierID: createOrder.SupplierID,
+					Status:     model.PurchaseOrderStatusDraft,
+					Lines: []model.PurchaseOrderLine{
+						{ID: 1, PurchaseOrderID: 1, ItemID: 3, Quantity: 10, Price: 2.5},
+					},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"supplier_id": float64(1),
+				"status":      "draft",
+			},
+		},
+		{
+			name:           "no lines",
+			body:           `{"supplier_id":1,"user_id":1,"lines":[]}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Lines is not valid due to min",
+			},
+		},
+		{
+			name:           "zero line quantity",
+			body:           `{"supplier_id":1,"user_id":1,"lines":[{"item_id":3,"quantity":0}]}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Quantity is required",
+			},
+		},
+		{
+			name: "no price",
+			body: `{"supplier_id":1,"user_id":1,"lines":[{"item_id":3,"quantity":10}]}`,
+			mockFunc: func(ctx context.Context, createOrder dto.CreatePurchaseOrder) (*model.PurchaseOrder, error) {
+				return nil, errNoPrice
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": errNoPrice.Error(),
+			},
+		},
+		{
+			name: "no such supplier",
+			body: `{"supplier_id":9,"user_id":1,"lines":[{"item_id":3,"quantity":10}]}`,
+			mockFunc: func(ctx context.Context, createOrder dto.CreatePurchaseOrder) (*model.PurchaseOrder, error) {
+				return nil, repository.ErrNoSuchSupplier
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchSupplier.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/purchase-orders", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			mock := &mockService{
+				createPurchaseOrderFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.CreatePurchaseOrder(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_SendPurchaseOrder(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		mockFunc         func(ctx context.Context, id int) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			mockFunc: func(ctx context.Context, id int) error {
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully sent purchase order",
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid purchase order id or was not provided",
+			},
+		},
+		{
+			name:    "already sent",
+			paramID: "1",
+			mockFunc: func(ctx context.Context, id int) error {
+				return repository.ErrPurchaseOrderStatus
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrPurchaseOrderStatus.Error(),
+			},
+		},
+		{
+			name:    "empty order",
+			paramID: "2",
+			mockFunc: func(ctx context.Context, id int) error {
+				return repository.ErrPurchaseOrderEmpty
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrPurchaseOrderEmpty.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/purchase-orders/"+tt.paramID+"/send", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				sendPurchaseOrderFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.SendPurchaseOrder(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Receive goods against purchase order
+// @Description Put accepted quantities into stock, note damaged ones and update received status of the order in one transaction, requires admin or manager authentication
+// @Tags receipts
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key to safely retry the request"
+// @Param receipt body dto.CreateReceipt true "Received quantities per order line"
+// @Success 200 {object} model.Receipt
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /receipts [post]
+// @Security BearerAuth
+func (h *Handler) CreateReceipt(c *ginext.Context) {
+	var createReceipt dto.CreateReceipt
+
+	if err := c.BindJSON(&createReceipt); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createReceipt); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	receipt, err := h.service.CreateReceipt(h.ctx, &createReceipt)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new receipt")
+	c.JSON(http.StatusOK, receipt)
+}
+
+// @Summary Get receipts
+// @Description Get receipts without lines, requires authentication
+// @Tags receipts
+// @Produce json
+// @Param purchase_order_id query int false "Only receipts of this purchase order"
+// @Success 200 {array} model.Receipt
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /receipts [get]
+// @Security BearerAuth
+func (h *Handler) GetReceipts(c *ginext.Context) {
+	var filter dto.ReceiptFilter
+	if orderParam := c.Query("purchase_order_id"); orderParam != "" {
+		orderID, err := strconv.Atoi(orderParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid purchase order id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid purchase order id"})
+			return
+		}
+		filter.PurchaseOrderID = &orderID
+	}
+
+	receipts, err := h.service.GetReceipts(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned receipts")
+	c.JSON(http.StatusOK, receipts)
+}
+
+// @Summary Get receipt
+// @Description Get receipt by ID with its lines, requires authentication
+// @Tags receipts
+// @Produce json
+// @Param id path int true "Receipt ID"
+// @Success 200 {object} model.Receipt
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /receipts/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetReceiptByID(c *ginext.Context) {
+	receiptID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid receipt id or was not provided"})
+		return
+	}
+
+	receipt, err := h.service.GetReceiptByID(h.ctx, receiptID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned receipt")
+	c.JSON(http.StatusOK, receipt)
+}
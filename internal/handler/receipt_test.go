@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_CreateReceipt(t *testing.T) {
+	errOverDelivery := fmt.Errorf("%w: line 1", repository.ErrOverDelivery)
+
+	tests := []struct {
+		name             string
+		body             string
+		mockFunc         func(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "partial delivery",
+			body: `{"purchase_order_id":1,"user_id":1,"lines":[{"line_id":1,"quantity":8,"damaged_quantity":2,"note":"crushed boxes"}]}`,
+			mockFunc: func(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error) {
+				line := createReceipt.Lines[0]
+				return &model.Receipt{
+					ID:                  1,
+					PurchaseOrderID:     createReceipt.PurchaseOrderID,
+					PurchaseOrderStatus: model.PurchaseOrderStatusPartiallyReceived,
+					Lines: []model.ReceiptLine{
+						{ID: 1, PurchaseOrderLineID: line.PurchaseOrderLineID, Quantity: line.Quantity, DamagedQuantity: line.DamagedQuantity, Note: line.Note},
+					},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"purchase_order_id":     float64(1),
+				"purchase_order_status": "partially_received",
+			},
+		},
+		{
+			name:           "negative quantity",
+			body:           `{"purchase_order_id":1,"user_id":1,"lines":[{"line_id":1,"quantity":-1}]}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Quantity is not valid due to gte",
+			},
+		},
+		{
+			name: "over delivery",
+			body: `{"purchase_order_id":1,"user_id":1,"lines":[{"line_id":1,"quantity":50}]}`,
+			mockFunc: func(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error) {
+				return nil, errOverDelivery
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": errOverDelivery.Error(),
+			},
+		},
+		{
+			name: "order not sent",
+			body: `{"purchase_order_id":2,"user_id":1,"lines":[{"line_id":3,"quantity":1}]}`,
+			mockFunc: func(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error) {
+				return nil, repository.ErrPurchaseOrderStatus
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrPurchaseOrderStatus.Error(),
+			},
+		},
+		{
+			name: "line of another order",
+			body: `{"purchase_order_id":1,"user_id":1,"lines":[{"line_id":9,"quantity":1}]}`,
+			mockFunc: func(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error) {
+				return nil, repository.ErrNoSuchPurchaseOrderLine
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchPurchaseOrderLine.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/receipts", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			mock := &mockService{
+				createReceiptFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.CreateReceipt(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
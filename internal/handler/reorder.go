@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Set item reorder rule
+// @Description Create or replace min/max stock levels and reorder quantity of item in the warehouse or, without warehouse_id, of its total stock, requires admin or manager authentication
+// @Tags reorder
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param rule body dto.SetReorderRule true "Reorder rule"
+// @Success 200 {object} model.ReorderRule
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/reorder-rules [put]
+// @Security BearerAuth
+func (h *Handler) SetReorderRule(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var setReorderRule dto.SetReorderRule
+	if err := c.BindJSON(&setReorderRule); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setReorderRule.ItemID = itemID
+
+	if err := validator.Validate.Struct(setReorderRule); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	rule, err := h.service.SetReorderRule(h.ctx, setReorderRule)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and set reorder rule")
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Get item reorder rules
+// @Description Get reorder rules of item, rule without warehouse covers total stock, requires authentication
+// @Tags reorder
+// @Produce json
+// @Param id path int true "Item ID"
+// @Success 200 {array} model.ReorderRule
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/reorder-rules [get]
+// @Security BearerAuth
+func (h *Handler) GetReorderRules(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	rules, err := h.service.GetReorderRules(h.ctx, itemID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned reorder rules")
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary Delete item reorder rule
+// @Description Delete reorder rule of item in the warehouse or, without warehouse_id, the rule for its total stock, requires admin authentication
+// @Tags reorder
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param warehouse_id query int false "Warehouse of the rule"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/reorder-rules [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteReorderRule(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var warehouseID *int
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		id, err := strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+		warehouseID = &id
+	}
+
+	if err := h.service.DeleteReorderRule(h.ctx, itemID, warehouseID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted reorder rule")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted reorder rule"})
+}
+
+// @Summary Get low stock
+// @Description Get stock below reorder point with suggested order quantities, requires authentication
+// @Tags reorder
+// @Produce json
+// @Param warehouse_id query int false "Only rules of this warehouse"
+// @Success 200 {array} model.LowStockItem
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/low-stock [get]
+// @Security BearerAuth
+func (h *Handler) GetLowStock(c *ginext.Context) {
+	var warehouseID *int
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		id, err := strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+		warehouseID = &id
+	}
+
+	items, err := h.service.GetLowStock(h.ctx, warehouseID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned low stock")
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Get stock alerts
+// @Description Get alerts raised when movement crosses reorder point, newest first, requires authentication
+// @Tags reorder
+// @Produce json
+// @Param all query bool false "Include acknowledged alerts"
+// @Success 200 {array} model.StockAlert
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /alerts [get]
+// @Security BearerAuth
+func (h *Handler) GetStockAlerts(c *ginext.Context) {
+	all := false
+	if allParam := c.Query("all"); allParam != "" {
+		var err error
+		all, err = strconv.ParseBool(allParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid all flag: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid all flag"})
+			return
+		}
+	}
+
+	alerts, err := h.service.GetStockAlerts(h.ctx, !all)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned stock alerts")
+	c.JSON(http.StatusOK, alerts)
+}
+
+// @Summary Acknowledge stock alert
+// @Description Mark stock alert as handled, requires admin or manager authentication
+// @Tags reorder
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /alerts/{id}/acknowledge [post]
+// @Security BearerAuth
+func (h *Handler) AcknowledgeStockAlert(c *ginext.Context) {
+	id := c.Param("id")
+	alertID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert id or was not provided"})
+		return
+	}
+
+	if err := h.service.AcknowledgeStockAlert(h.ctx, alertID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and acknowledged stock alert")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully acknowledged stock alert"})
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+	"wharehouse-control/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_SetReorderRule(t *testing.T) {
+	errMaxBelowMin := fmt.Errorf("%w: max level cannot be less than min level", service.ErrInvalidReorderRule)
+
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, setReorderRule dto.SetReorderRule) (*model.ReorderRule, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			body:    `{"warehouse_id":2,"min_level":10,"max_level":50}`,
+			mockFunc: func(ctx context.Context, setReorderRule dto.SetReorderRule) (*model.ReorderRule, error) {
+				return &model.ReorderRule{
+					ID:          1,
+					ItemID:      setReorderRule.ItemID,
+					WarehouseID: setReorderRule.WarehouseID,
+					MinLevel:    setReorderRule.MinLevel,
+					MaxLevel:    setReorderRule.MaxLevel,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"item_id":      float64(1),
+				"warehouse_id": float64(2),
+				"min_level":    float64(10),
+				"max_level":    float64(50),
+			},
+		},
+		{
+			name:           "negative min level",
+			paramID:        "1",
+			body:           `{"min_level":-1}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "MinLevel is not valid due to gte",
+			},
+		},
+		{
+			name:    "max below min",
+			paramID: "1",
+			body:    `{"min_level":10,"max_level":5}`,
+			mockFunc: func(ctx context.Context, setReorderRule dto.SetReorderRule) (*model.ReorderRule, error) {
+				return nil, errMaxBelowMin
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": errMaxBelowMin.Error(),
+			},
+		},
+		{
+			name:    "no such item",
+			paramID: "9",
+			body:    `{"min_level":10}`,
+			mockFunc: func(ctx context.Context, setReorderRule dto.SetReorderRule) (*model.ReorderRule, error) {
+				return nil, repository.ErrNoSuchItem
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchItem.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/items/"+tt.paramID+"/reorder-rules", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				setReorderRuleFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.SetReorderRule(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_GetLowStock(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockFunc       func(ctx context.Context, warehouseID *int) ([]model.LowStockItem, error)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:  "success",
+			query: "?warehouse_id=2",
+			mockFunc: func(ctx context.Context, warehouseID *int) ([]model.LowStockItem, error) {
+				if warehouseID == nil || *warehouseID != 2 {
+					return nil, errors.New("warehouse filter was not passed")
+				}
+				return []model.LowStockItem{
+					{ReorderRule: model.ReorderRule{ItemID: 1, WarehouseID: warehouseID, MinLevel: 10}, Quantity: 4, SuggestedQuantity: 6},
+					{ReorderRule: model.ReorderRule{ItemID: 2, WarehouseID: warehouseID, MinLevel: 5}, Quantity: 0, SuggestedQuantity: 5},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "invalid warehouse",
+			query:          "?warehouse_id=abc",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "storage error",
+			mockFunc: func(ctx context.Context, warehouseID *int) ([]model.LowStockItem, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/low-stock"+tt.query, nil)
+
+			mock := &mockService{
+				getLowStockFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.GetLowStock(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp []map[string]interface{}
+				json.Unmarshal(w.Body.Bytes(), &resp)
+
+				if len(resp) != tt.expectedCount {
+					t.Errorf("expected %d items, got %d", tt.expectedCount, len(resp))
+				}
+			}
+		})
+	}
+}
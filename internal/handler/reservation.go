@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Create a reservation
+// @Description Hold item stock for an order, it lowers available quantity but not on-hand, requires admin or manager authentication
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key to safely retry the request"
+// @Param reservation body dto.CreateReservation true "Reservation data"
+// @Success 200 {object} model.Reservation
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /reservations [post]
+// @Security BearerAuth
+func (h *Handler) CreateReservation(c *ginext.Context) {
+	var createReservation dto.CreateReservation
+
+	if err := c.BindJSON(&createReservation); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createReservation); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	reservation, err := h.service.CreateReservation(h.ctx, createReservation)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new reservation")
+	c.JSON(http.StatusOK, reservation)
+}
+
+// @Summary Get reservations
+// @Description Get reservations, requires authentication
+// @Tags reservations
+// @Produce json
+// @Param item_id query int false "Only reservations of this item"
+// @Param status query string false "Only reservations in this status: active, confirmed, released or expired"
+// @Success 200 {array} model.Reservation
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /reservations [get]
+// @Security BearerAuth
+func (h *Handler) GetReservations(c *ginext.Context) {
+	var filter dto.ReservationFilter
+	if itemParam := c.Query("item_id"); itemParam != "" {
+		itemID, err := strconv.Atoi(itemParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid item id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+			return
+		}
+		filter.ItemID = &itemID
+	}
+
+	switch status := c.Query("status"); status {
+	case "", model.ReservationStatusActive, model.ReservationStatusConfirmed,
+		model.ReservationStatusReleased, model.ReservationStatusExpired:
+		filter.Status = status
+	default:
+		zlog.Logger.Error().Msg("invalid reservation status: " + status)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reservation status"})
+		return
+	}
+
+	reservations, err := h.service.GetReservations(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned reservations")
+	c.JSON(http.StatusOK, reservations)
+}
+
+// @Summary Confirm a reservation
+// @Description Ship reserved stock, on-hand quantity is reduced and reservation is closed, requires admin or manager authentication
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param id path int true "Reservation ID"
+// @Param confirmation body dto.ConfirmReservation true "Confirmation data"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /reservations/{id}/confirm [post]
+// @Security BearerAuth
+func (h *Handler) ConfirmReservation(c *ginext.Context) {
+	id := c.Param("id")
+	reservationID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reservation id or was not provided"})
+		return
+	}
+
+	var confirmReservation dto.ConfirmReservation
+	if err := c.BindJSON(&confirmReservation); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	confirmReservation.ID = reservationID
+
+	if err := validator.Validate.Struct(confirmReservation); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.service.ConfirmReservation(h.ctx, confirmReservation); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and confirmed reservation")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully confirmed reservation"})
+}
+
+// @Summary Release a reservation
+// @Description Release reserved stock back to available quantity, requires admin or manager authentication
+// @Tags reservations
+// @Produce json
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /reservations/{id}/release [post]
+// @Security BearerAuth
+func (h *Handler) ReleaseReservation(c *ginext.Context) {
+	id := c.Param("id")
+	reservationID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reservation id or was not provided"})
+		return
+	}
+
+	if err := h.service.ReleaseReservation(h.ctx, reservationID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and released reservation")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully released reservation"})
+}
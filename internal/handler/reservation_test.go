@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_CreateReservation(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		mockFunc         func(ctx context.Context, createReservation dto.CreateReservation) (*model.Reservation, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "success",
+			body: `{"item_id":1,"user_id":1,"quantity":3,"reference":"SO-1001"}`,
+			mockFunc: func(ctx context.Context, createReservation dto.CreateReservation) (*model.Reservation, error) {
+				return &model.Reservation{
+					ID:          1,
+					ItemID:      createReservation.ItemID,
+					WarehouseID: 1,
+					Quantity:    createReservation.Quantity,
+					Reference:   createReservation.Reference,
+					Status:      model.ReservationStatusActive,
+					ExpiresAt:   time.Now().Add(time.Hour),
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"id":        float64(1),
+				"reference": "SO-1001",
+				"status":    "active",
+			},
+		},
+		{
+			name:           "validation error",
+			body:           `{"item_id":1,"user_id":1,"quantity":-3,"reference":"SO-1001"}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Quantity is not valid due to gt",
+			},
+		},
+		{
+			name: "not enough available",
+			body: `{"item_id":1,"user_id":1,"quantity":300,"reference":"SO-1002"}`,
+			mockFunc: func(ctx context.Context, createReservation dto.CreateReservation) (*model.Reservation, error) {
+				return nil, repository.ErrInsufficientAvailable
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrInsufficientAvailable.Error(),
+			},
+		},
+		{
+			name: "service error",
+			body: `{"item_id":1,"user_id":1,"quantity":3,"reference":"SO-1003"}`,
+			mockFunc: func(ctx context.Context, createReservation dto.CreateReservation) (*model.Reservation, error) {
+				return nil, errors.New("service error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"error": "service error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/reservations", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			mock := &mockService{
+				createReservationFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.CreateReservation(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ConfirmReservation(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, confirmReservation dto.ConfirmReservation) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, confirmReservation dto.ConfirmReservation) error {
+				if confirmReservation.ID != 1 {
+					return errors.New("unexpected reservation")
+				}
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully confirmed reservation",
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			body:           `{"user_id":1}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid reservation id or was not provided",
+			},
+		},
+		{
+			name:    "expired",
+			paramID: "2",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, confirmReservation dto.ConfirmReservation) error {
+				return repository.ErrReservationNotActive
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrReservationNotActive.Error(),
+			},
+		},
+		{
+			name:    "not found",
+			paramID: "3",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, confirmReservation dto.ConfirmReservation) error {
+				return repository.ErrNoSuchReservation
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchReservation.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/reservations/"+tt.paramID+"/confirm", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				confirmReservationFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.ConfirmReservation(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Get item serials
+// @Description Get serial numbers of serialized item, requires authentication
+// @Tags serials
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param status query string false "Only serials in this status: in_stock, reserved, shipped, returned or in_transit"
+// @Success 200 {array} model.Serial
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/serials [get]
+// @Security BearerAuth
+func (h *Handler) GetSerials(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	filter := dto.SerialFilter{ItemID: itemID}
+	switch status := c.Query("status"); status {
+	case "", model.SerialStatusInStock, model.SerialStatusReserved,
+		model.SerialStatusShipped, model.SerialStatusReturned, model.SerialStatusInTransit:
+		filter.Status = status
+	default:
+		zlog.Logger.Error().Msg("invalid serial status: " + status)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid serial status"})
+		return
+	}
+
+	serials, err := h.service.GetSerials(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned item serials")
+	c.JSON(http.StatusOK, serials)
+}
+
+// @Summary Get serial history
+// @Description Get serial number with every movement of the unit from receipt to shipment, requires authentication
+// @Tags serials
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param serial path string true "Serial number"
+// @Success 200 {object} model.SerialHistory
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/serials/{serial} [get]
+// @Security BearerAuth
+func (h *Handler) GetSerialHistory(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	history, err := h.service.GetSerialHistory(h.ctx, itemID, c.Param("serial"))
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned serial history")
+	c.JSON(http.StatusOK, history)
+}
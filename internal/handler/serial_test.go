@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_GetSerialHistory(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		serial           string
+		mockFunc         func(ctx context.Context, itemID int, serialNumber string) (*model.SerialHistory, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+		expectedMoves    int
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			serial:  "SN-0001",
+			mockFunc: func(ctx context.Context, itemID int, serialNumber string) (*model.SerialHistory, error) {
+				return &model.SerialHistory{
+					Serial: model.Serial{ID: 5, ItemID: itemID, SerialNumber: serialNumber, Status: model.SerialStatusShipped},
+					Movements: []model.Movement{
+						{ID: 1, ItemID: itemID, Quantity: 1, Reason: model.MovementReasonInitial, Serials: []string{serialNumber}},
+						{ID: 2, ItemID: itemID, Quantity: -1, Reason: model.MovementReasonReservation, Serials: []string{serialNumber}},
+					},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"serial_number": "SN-0001",
+				"status":        "shipped",
+			},
+			expectedMoves: 2,
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			serial:         "SN-0001",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid item is or was not provided",
+			},
+		},
+		{
+			name:    "unknown serial",
+			paramID: "1",
+			serial:  "SN-9999",
+			mockFunc: func(ctx context.Context, itemID int, serialNumber string) (*model.SerialHistory, error) {
+				return nil, repository.ErrNoSuchSerial
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchSerial.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/"+tt.paramID+"/serials/"+tt.serial, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}, {Key: "serial", Value: tt.serial}}
+
+			mock := &mockService{
+				getSerialHistoryFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.GetSerialHistory(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+
+			if tt.expectedMoves > 0 {
+				movements, _ := resp["movements"].([]interface{})
+				if len(movements) != tt.expectedMoves {
+					t.Errorf("expected %d movements, got %d", tt.expectedMoves, len(movements))
+				}
+			}
+		})
+	}
+}
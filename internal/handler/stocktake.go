@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Start a stocktake
+// @Description Freeze expected quantities of warehouse, location subtree or category to count, serialized items are counted by serial numbers and are left out, requires admin or manager authentication
+// @Tags stocktakes
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key to safely retry the request"
+// @Param stocktake body dto.CreateStocktake true "Stocktake scope"
+// @Success 200 {object} model.Stocktake
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /stocktakes [post]
+// @Security BearerAuth
+func (h *Handler) CreateStocktake(c *ginext.Context) {
+	var createStocktake dto.CreateStocktake
+
+	if err := c.BindJSON(&createStocktake); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createStocktake); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	stocktake, err := h.service.CreateStocktake(h.ctx, createStocktake)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and started new stocktake")
+	c.JSON(http.StatusOK, stocktake)
+}
+
+// @Summary Get stocktakes
+// @Description Get stocktakes without lines, requires authentication
+// @Tags stocktakes
+// @Produce json
+// @Param warehouse_id query int false "Only stocktakes of this warehouse"
+// @Param status query string false "Only stocktakes in this status: open, approved or cancelled"
+// @Success 200 {array} model.Stocktake
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /stocktakes [get]
+// @Security BearerAuth
+func (h *Handler) GetStocktakes(c *ginext.Context) {
+	var filter dto.StocktakeFilter
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		warehouseID, err := strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+		filter.WarehouseID = &warehouseID
+	}
+
+	switch status := c.Query("status"); status {
+	case "", model.StocktakeStatusOpen, model.StocktakeStatusApproved, model.StocktakeStatusCancelled:
+		filter.Status = status
+	default:
+		zlog.Logger.Error().Msg("invalid stocktake status: " + status)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid stocktake status"})
+		return
+	}
+
+	stocktakes, err := h.service.GetStocktakes(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned stocktakes")
+	c.JSON(http.StatusOK, stocktakes)
+}
+
+// @Summary Get stocktake
+// @Description Get stocktake by ID with lines in walking order, expected, counted quantities and variances, requires authentication
+// @Tags stocktakes
+// @Produce json
+// @Param id path int true "Stocktake ID"
+// @Success 200 {object} model.Stocktake
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /stocktakes/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetStocktakeByID(c *ginext.Context) {
+	stocktakeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid stocktake id or was not provided"})
+		return
+	}
+
+	stocktake, err := h.service.GetStocktakeByID(h.ctx, stocktakeID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned stocktake")
+	c.JSON(http.StatusOK, stocktake)
+}
+
+// @Summary Get stocktake variances
+// @Description Get counted lines of stocktake that differ from expected quantities, requires authentication
+// @Tags stocktakes
+// @Produce json
+// @Param id path int true "Stocktake ID"
+// @Success 200 {array} model.StocktakeLine
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /stocktakes/{id}/variances [get]
+// @Security BearerAuth
+func (h *Handler) GetStocktakeVariances(c *ginext.Context) {
+	stocktakeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid stocktake id or was not provided"})
+		return
+	}
+
+	variances, err := h.service.GetStocktakeVariances(h.ctx, stocktakeID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned stocktake variances")
+	c.JSON(http.StatusOK, variances)
+}
+
+// @Summary Record counts
+// @Description Record counted quantities of items at locations, counters may send counts in parallel and the latest count of a line wins, item found where it was not expected adds a line, requires admin or manager authentication
+// @Tags stocktakes
+// @Accept json
+// @Produce json
+// @Param id path int true "Stocktake ID"
+// @Param counts body dto.RecordStocktakeCounts true "Counted quantities"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /stocktakes/{id}/counts [post]
+// @Security BearerAuth
+func (h *Handler) RecordStocktakeCounts(c *ginext.Context) {
+	stocktakeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid stocktake id or was not provided"})
+		return
+	}
+
+	var recordCounts dto.RecordStocktakeCounts
+	if err := c.BindJSON(&recordCounts); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	recordCounts.ID = stocktakeID
+
+	if err := validator.Validate.Struct(recordCounts); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.service.RecordStocktakeCounts(h.ctx, &recordCounts); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and recorded stocktake counts")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully recorded stocktake counts"})
+}
+
+// @Summary Approve a stocktake
+// @Description Post variance of every line as stocktake adjustment movement and close stocktake, every line has to be counted, requires admin authentication
+// @Tags stocktakes
+// @Accept json
+// @Produce json
+// @Param id path int true "Stocktake ID"
+// @Param approval body dto.ApproveStocktake true "Approval data"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /stocktakes/{id}/approve [post]
+// @Security BearerAuth
+func (h *Handler) ApproveStocktake(c *ginext.Context) {
+	stocktakeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid stocktake id or was not provided"})
+		return
+	}
+
+	var approveStocktake dto.ApproveStocktake
+	if err := c.BindJSON(&approveStocktake); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	approveStocktake.ID = stocktakeID
+
+	if err := validator.Validate.Struct(approveStocktake); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.service.ApproveStocktake(h.ctx, approveStocktake); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and approved stocktake")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully approved stocktake"})
+}
+
+// @Summary Cancel a stocktake
+// @Description Cancel open stocktake without changing stock, requires admin authentication
+// @Tags stocktakes
+// @Produce json
+// @Param id path int true "Stocktake ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /stocktakes/{id}/cancel [post]
+// @Security BearerAuth
+func (h *Handler) CancelStocktake(c *ginext.Context) {
+	stocktakeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid stocktake id or was not provided"})
+		return
+	}
+
+	if err := h.service.CancelStocktake(h.ctx, stocktakeID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and cancelled stocktake")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully cancelled stocktake"})
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_CreateStocktake(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		mockFunc         func(ctx context.Context, createStocktake dto.CreateStocktake) (*model.Stocktake, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "zone of warehouse",
+			body: `{"user_id":1,"warehouse_id":1,"location_id":3}`,
+			mockFunc: func(ctx context.Context, createStocktake dto.CreateStocktake) (*model.Stocktake, error) {
+				return &model.Stocktake{
+					ID:          1,
+					WarehouseID: createStocktake.WarehouseID,
+					LocationID:  createStocktake.LocationID,
+					Status:      model.StocktakeStatusOpen,
+					Lines:       []model.StocktakeLine{{ID: 1, StocktakeID: 1, ItemID: 1, ExpectedQuantity: 10}},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"location_id": float64(3),
+				"status":      "open",
+			},
+		},
+		{
+			name:           "no user",
+			body:           `{"warehouse_id":1}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "UserID is required",
+			},
+		},
+		{
+			name: "location of another warehouse",
+			body: `{"user_id":1,"warehouse_id":2,"location_id":3}`,
+			mockFunc: func(ctx context.Context, createStocktake dto.CreateStocktake) (*model.Stocktake, error) {
+				return nil, repository.ErrLocationWarehouse
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrLocationWarehouse.Error(),
+			},
+		},
+		{
+			name: "unknown category",
+			body: `{"user_id":1,"category_id":99}`,
+			mockFunc: func(ctx context.Context, createStocktake dto.CreateStocktake) (*model.Stocktake, error) {
+				return nil, repository.ErrNoSuchCategory
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchCategory.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/stocktakes", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			mock := &mockService{
+				createStocktakeFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.CreateStocktake(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_RecordStocktakeCounts(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			body:    `{"user_id":2,"counts":[{"item_id":1,"location_id":4,"quantity":9},{"item_id":2,"quantity":0}]}`,
+			mockFunc: func(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error {
+				if recordCounts.ID != 1 || len(recordCounts.Counts) != 2 {
+					return repository.ErrNoSuchStocktake
+				}
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully recorded stocktake counts",
+			},
+		},
+		{
+			name:           "no counts",
+			paramID:        "1",
+			body:           `{"user_id":2,"counts":[]}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Counts is not valid due to min",
+			},
+		},
+		{
+			name:    "location outside of scope",
+			paramID: "1",
+			body:    `{"user_id":2,"counts":[{"item_id":1,"location_id":40,"quantity":1}]}`,
+			mockFunc: func(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error {
+				return repository.ErrStocktakeScope
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrStocktakeScope.Error(),
+			},
+		},
+		{
+			name:    "serialized item",
+			paramID: "1",
+			body:    `{"user_id":2,"counts":[{"item_id":5,"quantity":1}]}`,
+			mockFunc: func(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error {
+				return fmt.Errorf("%w: item 5", repository.ErrStocktakeTracked)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrStocktakeTracked.Error() + ": item 5",
+			},
+		},
+		{
+			name:    "already approved",
+			paramID: "2",
+			body:    `{"user_id":2,"counts":[{"item_id":1,"quantity":1}]}`,
+			mockFunc: func(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error {
+				return repository.ErrStocktakeStatus
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrStocktakeStatus.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/stocktakes/"+tt.paramID+"/counts", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				recordCountsFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.RecordStocktakeCounts(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ApproveStocktake(t *testing.T) {
+	errIncomplete := fmt.Errorf("%w: 3 lines left", repository.ErrStocktakeIncomplete)
+
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, approveStocktake dto.ApproveStocktake) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, approveStocktake dto.ApproveStocktake) error {
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully approved stocktake",
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			body:           `{"user_id":1}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid stocktake id or was not provided",
+			},
+		},
+		{
+			name:    "lines not counted",
+			paramID: "1",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, approveStocktake dto.ApproveStocktake) error {
+				return errIncomplete
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": errIncomplete.Error(),
+			},
+		},
+		{
+			name:    "not found",
+			paramID: "99",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, approveStocktake dto.ApproveStocktake) error {
+				return repository.ErrNoSuchStocktake
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchStocktake.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/stocktakes/"+tt.paramID+"/approve", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				approveStocktakeFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.ApproveStocktake(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
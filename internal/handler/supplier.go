@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Create a new supplier
+// @Description Create a new supplier, requires admin authentication
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Param supplier body dto.CreateSupplier true "Supplier creation data"
+// @Success 200 {object} model.Supplier
+// @Failure 400 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /suppliers [post]
+// @Security BearerAuth
+func (h *Handler) CreateSupplier(c *ginext.Context) {
+	var createSupplier dto.CreateSupplier
+
+	if err := c.BindJSON(&createSupplier); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createSupplier); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	supplier, err := h.service.CreateSupplier(h.ctx, createSupplier)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new supplier")
+	c.JSON(http.StatusOK, supplier)
+}
+
+// @Summary Get all suppliers
+// @Description Get all suppliers ordered by name, requires authentication
+// @Tags suppliers
+// @Produce json
+// @Success 200 {array} model.Supplier
+// @Failure 500 {object} gin.H
+// @Router /suppliers [get]
+// @Security BearerAuth
+func (h *Handler) GetSuppliers(c *ginext.Context) {
+	suppliers, err := h.service.GetSuppliers(h.ctx)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned all suppliers")
+	c.JSON(http.StatusOK, suppliers)
+}
+
+// @Summary Get supplier
+// @Description Get supplier by ID with its price list, requires authentication
+// @Tags suppliers
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} model.Supplier
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /suppliers/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetSupplierByID(c *ginext.Context) {
+	supplierID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid supplier id or was not provided"})
+		return
+	}
+
+	supplier, err := h.service.GetSupplierByID(h.ctx, supplierID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned supplier")
+	c.JSON(http.StatusOK, supplier)
+}
+
+// @Summary Update a supplier
+// @Description Update supplier contacts or lead time, requires admin or manager authentication
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Param supplier body dto.UpdateSupplier true "Update data"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /suppliers/{id} [put]
+// @Security BearerAuth
+func (h *Handler) UpdateSupplier(c *ginext.Context) {
+	supplierID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid supplier id or was not provided"})
+		return
+	}
+
+	var updateSupplier dto.UpdateSupplier
+	if err := c.BindJSON(&updateSupplier); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updateSupplier.ID = supplierID
+
+	if err := validator.Validate.Struct(updateSupplier); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	err = h.service.UpdateSupplier(h.ctx, &updateSupplier)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and updated supplier")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully updated supplier"})
+}
+
+// @Summary Delete a supplier
+// @Description Delete a supplier that has no purchase orders, requires admin authentication
+// @Tags suppliers
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /suppliers/{id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteSupplier(c *ginext.Context) {
+	supplierID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid supplier id or was not provided"})
+		return
+	}
+
+	err = h.service.DeleteSupplier(h.ctx, supplierID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted supplier")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted supplier"})
+}
+
+// @Summary Set supplier price for item
+// @Description Create or replace price list entry of supplier for item, requires admin or manager authentication
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Param item_id path int true "Item ID"
+// @Param price body dto.SetSupplierItem true "Price list entry"
+// @Success 200 {object} model.SupplierItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /suppliers/{id}/items/{item_id} [put]
+// @Security BearerAuth
+func (h *Handler) SetSupplierItem(c *ginext.Context) {
+	supplierID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid supplier id or was not provided"})
+		return
+	}
+
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id or was not provided"})
+		return
+	}
+
+	var setSupplierItem dto.SetSupplierItem
+	if err := c.BindJSON(&setSupplierItem); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setSupplierItem.SupplierID = supplierID
+	setSupplierItem.ItemID = itemID
+
+	if err := validator.Validate.Struct(setSupplierItem); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	supplierItem, err := h.service.SetSupplierItem(h.ctx, setSupplierItem)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and set supplier item")
+	c.JSON(http.StatusOK, supplierItem)
+}
+
+// @Summary Remove item from supplier price list
+// @Description Remove price list entry of supplier for item, requires admin authentication
+// @Tags suppliers
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Param item_id path int true "Item ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /suppliers/{id}/items/{item_id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteSupplierItem(c *ginext.Context) {
+	supplierID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid supplier id or was not provided"})
+		return
+	}
+
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id or was not provided"})
+		return
+	}
+
+	err = h.service.DeleteSupplierItem(h.ctx, supplierID, itemID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted supplier item")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted supplier item"})
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_CreateSupplier(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		mockFunc         func(ctx context.Context, createSupplier dto.CreateSupplier) (*model.Supplier, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "success",
+			body: `{"name":"Acme","email":"sales@acme.test","lead_time_days":5}`,
+			mockFunc: func(ctx context.Context, createSupplier dto.CreateSupplier) (*model.Supplier, error) {
+				return &model.Supplier{
+					ID:           1,
+					Name:         createSupplier.Name,
+					Email:        createSupplier.Email,
+					LeadTimeDays: createSupplier.LeadTimeDays,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"name":           "Acme",
+				"lead_time_days": float64(5),
+			},
+		},
+		{
+			name:           "invalid email",
+			body:           `{"name":"Acme","email":"acme"}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Email is not valid due to email",
+			},
+		},
+		{
+			name:           "negative lead time",
+			body:           `{"name":"Acme","lead_time_days":-1}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "LeadTimeDays is not valid due to gte",
+			},
+		},
+		{
+			name: "duplicate name",
+			body: `{"name":"Acme"}`,
+			mockFunc: func(ctx context.Context, createSupplier dto.CreateSupplier) (*model.Supplier, error) {
+				return nil, repository.ErrSupplierExists
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrSupplierExists.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/suppliers", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			mock := &mockService{
+				createSupplierFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.CreateSupplier(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
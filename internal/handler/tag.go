@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Set item tags
+// @Description Replace free-form tags of item, tags are lowercased with spaces collapsed and duplicates dropped, empty list removes all tags, requires admin or manager authentication
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param tags body dto.SetItemTags true "Tags of item"
+// @Success 200 {object} model.Item
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/tags [put]
+// @Security BearerAuth
+func (h *Handler) SetItemTags(c *ginext.Context) {
+	itemID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var setTags dto.SetItemTags
+	if err := c.BindJSON(&setTags); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setTags.ItemID = itemID
+
+	if err := validator.Validate.Struct(setTags); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	item, err := h.service.SetItemTags(h.ctx, &setTags)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and set item tags")
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Get tags
+// @Description Get tags in use with number of active items labeled with each, requires authentication
+// @Tags tags
+// @Produce json
+// @Success 200 {array} model.TagCount
+// @Failure 500 {object} gin.H
+// @Router /tags [get]
+// @Security BearerAuth
+func (h *Handler) GetTags(c *ginext.Context) {
+	tags, err := h.service.GetTags(h.ctx)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned tags")
+	c.JSON(http.StatusOK, tags)
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Create a transfer order
+// @Description Create draft order to move stock from source to destination warehouse, requires admin or manager authentication
+// @Tags transfer orders
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key to safely retry the request"
+// @Param order body dto.CreateTransferOrder true "Transfer order data"
+// @Success 200 {object} model.TransferOrder
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /transfer-orders [post]
+// @Security BearerAuth
+func (h *Handler) CreateTransferOrder(c *ginext.Context) {
+	var createOrder dto.CreateTransferOrder
+
+	if err := c.BindJSON(&createOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createOrder); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	order, err := h.service.CreateTransferOrder(h.ctx, createOrder)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new transfer order")
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Get transfer orders
+// @Description Get transfer orders without lines, requires authentication
+// @Tags transfer orders
+// @Produce json
+// @Param warehouse_id query int false "Only orders from or to this warehouse"
+// @Param status query string false "Only orders in this status: draft, in_transit, received or cancelled"
+// @Success 200 {array} model.TransferOrder
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /transfer-orders [get]
+// @Security BearerAuth
+func (h *Handler) GetTransferOrders(c *ginext.Context) {
+	var filter dto.TransferOrderFilter
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		warehouseID, err := strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+		filter.WarehouseID = &warehouseID
+	}
+
+	switch status := c.Query("status"); status {
+	case "", model.TransferOrderStatusDraft, model.TransferOrderStatusInTransit,
+		model.TransferOrderStatusReceived, model.TransferOrderStatusCancelled:
+		filter.Status = status
+	default:
+		zlog.Logger.Error().Msg("invalid transfer order status: " + status)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer order status"})
+		return
+	}
+
+	orders, err := h.service.GetTransferOrders(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned transfer orders")
+	c.JSON(http.StatusOK, orders)
+}
+
+// @Summary Get transfer order
+// @Description Get transfer order by ID with its lines and discrepancies, requires authentication
+// @Tags transfer orders
+// @Produce json
+// @Param id path int true "Transfer order ID"
+// @Success 200 {object} model.TransferOrder
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /transfer-orders/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetTransferOrderByID(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer order id or was not provided"})
+		return
+	}
+
+	order, err := h.service.GetTransferOrderByID(h.ctx, orderID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned transfer order")
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Dispatch a transfer order
+// @Description Take order lines out of source warehouse, the stock is in transit to destination until received, requires admin or manager authentication
+// @Tags transfer orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Transfer order ID"
+// @Param dispatch body dto.DispatchTransferOrder true "Dispatch data"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /transfer-orders/{id}/dispatch [post]
+// @Security BearerAuth
+func (h *Handler) DispatchTransferOrder(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer order id or was not provided"})
+		return
+	}
+
+	var dispatchOrder dto.DispatchTransferOrder
+	if err := c.BindJSON(&dispatchOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dispatchOrder.ID = orderID
+
+	if err := validator.Validate.Struct(dispatchOrder); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.service.DispatchTransferOrder(h.ctx, dispatchOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and dispatched transfer order")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully dispatched transfer order"})
+}
+
+// @Summary Receive a transfer order
+// @Description Put arrived quantities into destination warehouse, lines not listed arrived in full, shortage is recorded as discrepancy, requires admin or manager authentication
+// @Tags transfer orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Transfer order ID"
+// @Param receipt body dto.ReceiveTransferOrder true "Arrived quantities"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /transfer-orders/{id}/receive [post]
+// @Security BearerAuth
+func (h *Handler) ReceiveTransferOrder(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer order id or was not provided"})
+		return
+	}
+
+	var receiveOrder dto.ReceiveTransferOrder
+	if err := c.BindJSON(&receiveOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	receiveOrder.ID = orderID
+
+	if err := validator.Validate.Struct(receiveOrder); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.service.ReceiveTransferOrder(h.ctx, &receiveOrder); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and received transfer order")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully received transfer order"})
+}
+
+// @Summary Cancel a transfer order
+// @Description Cancel draft transfer order, dispatched order has to be received, requires admin or manager authentication
+// @Tags transfer orders
+// @Produce json
+// @Param id path int true "Transfer order ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /transfer-orders/{id}/cancel [post]
+// @Security BearerAuth
+func (h *Handler) CancelTransferOrder(c *ginext.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer order id or was not provided"})
+		return
+	}
+
+	if err := h.service.CancelTransferOrder(h.ctx, orderID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and cancelled transfer order")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully cancelled transfer order"})
+}
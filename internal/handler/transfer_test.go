@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_CreateTransferOrder(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		mockFunc         func(ctx context.Context, createOrder dto.CreateTransferOrder) (*model.TransferOrder, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "success",
+			body: `{"user_id":1,"source_warehouse_id":1,"destination_warehouse_id":2,"lines":[{"item_id":1,"quantity":5}]}`,
+			mockFunc: func(ctx context.Context, createOrder dto.CreateTransferOrder) (*model.TransferOrder, error) {
+				return &model.TransferOrder{
+					ID:                     1,
+					SourceWarehouseID:      createOrder.SourceWarehouseID,
+					DestinationWarehouseID: createOrder.DestinationWarehouseID,
+					Status:                 model.TransferOrderStatusDraft,
+					Lines:                  []model.TransferOrderLine{{ID: 1, OrderID: 1, ItemID: 1, Quantity: 5}},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"destination_warehouse_id": float64(2),
+				"status":                   "draft",
+			},
+		},
+		{
+			name:           "same warehouse",
+			body:           `{"user_id":1,"source_warehouse_id":1,"destination_warehouse_id":1,"lines":[{"item_id":1,"quantity":5}]}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "DestinationWarehouseID is not valid due to nefield",
+			},
+		},
+		{
+			name:           "no lines",
+			body:           `{"user_id":1,"source_warehouse_id":1,"destination_warehouse_id":2}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Lines is required",
+			},
+		},
+		{
+			name: "unknown warehouse",
+			body: `{"user_id":1,"source_warehouse_id":1,"destination_warehouse_id":9,"lines":[{"item_id":1,"quantity":5}]}`,
+			mockFunc: func(ctx context.Context, createOrder dto.CreateTransferOrder) (*model.TransferOrder, error) {
+				return nil, repository.ErrNoSuchWarehouse
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchWarehouse.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/transfer-orders", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			mock := &mockService{
+				createTransferOrderFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.CreateTransferOrder(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_DispatchTransferOrder(t *testing.T) {
+	errInsufficient := fmt.Errorf("%w: item 1", repository.ErrInsufficientAvailable)
+
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, dispatchOrder dto.DispatchTransferOrder) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, dispatchOrder dto.DispatchTransferOrder) error {
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully dispatched transfer order",
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			body:           `{"user_id":1}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid transfer order id or was not provided",
+			},
+		},
+		{
+			name:    "already dispatched",
+			paramID: "2",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, dispatchOrder dto.DispatchTransferOrder) error {
+				return repository.ErrTransferOrderStatus
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrTransferOrderStatus.Error(),
+			},
+		},
+		{
+			name:    "stock is reserved",
+			paramID: "3",
+			body:    `{"user_id":1}`,
+			mockFunc: func(ctx context.Context, dispatchOrder dto.DispatchTransferOrder) error {
+				return errInsufficient
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": errInsufficient.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/transfer-orders/"+tt.paramID+"/dispatch", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				dispatchTransferFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.DispatchTransferOrder(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ReceiveTransferOrder(t *testing.T) {
+	errOverReceipt := fmt.Errorf("%w: line 1", repository.ErrOverTransferReceipt)
+
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, receiveOrder *dto.ReceiveTransferOrder) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "shortage recorded",
+			paramID: "1",
+			body:    `{"user_id":1,"lines":[{"line_id":1,"quantity":4,"note":"one box missing"}]}`,
+			mockFunc: func(ctx context.Context, receiveOrder *dto.ReceiveTransferOrder) error {
+				if receiveOrder.ID != 1 || receiveOrder.Lines[0].Quantity != 4 {
+					return repository.ErrNoSuchTransferOrder
+				}
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully received transfer order",
+			},
+		},
+		{
+			name:           "negative quantity",
+			paramID:        "1",
+			body:           `{"user_id":1,"lines":[{"line_id":1,"quantity":-1}]}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Quantity is not valid due to gte",
+			},
+		},
+		{
+			name:    "more than dispatched",
+			paramID: "1",
+			body:    `{"user_id":1,"lines":[{"line_id":1,"quantity":6}]}`,
+			mockFunc: func(ctx context.Context, receiveOrder *dto.ReceiveTransferOrder) error {
+				return errOverReceipt
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": errOverReceipt.Error(),
+			},
+		},
+		{
+			name:    "line of another order",
+			paramID: "1",
+			body:    `{"user_id":1,"lines":[{"line_id":7,"quantity":1}]}`,
+			mockFunc: func(ctx context.Context, receiveOrder *dto.ReceiveTransferOrder) error {
+				return repository.ErrNoSuchTransferOrderLine
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchTransferOrderLine.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/transfer-orders/"+tt.paramID+"/receive", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				receiveTransferFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.ReceiveTransferOrder(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
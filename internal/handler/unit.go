@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Create a unit of measure
+// @Description Create a new unit of measure, requires admin authentication
+// @Tags units
+// @Accept json
+// @Produce json
+// @Param unit body dto.CreateUnit true "Unit creation data"
+// @Success 200 {object} model.Unit
+// @Failure 400 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /units [post]
+// @Security BearerAuth
+func (h *Handler) CreateUnit(c *ginext.Context) {
+	var createUnit dto.CreateUnit
+
+	if err := c.BindJSON(&createUnit); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createUnit); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	unit, err := h.service.CreateUnit(h.ctx, createUnit)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new unit")
+	c.JSON(http.StatusOK, unit)
+}
+
+// @Summary Get units of measure
+// @Description Get all units of measure, requires authentication
+// @Tags units
+// @Produce json
+// @Success 200 {array} model.Unit
+// @Failure 500 {object} gin.H
+// @Router /units [get]
+// @Security BearerAuth
+func (h *Handler) GetUnits(c *ginext.Context) {
+	units, err := h.service.GetUnits(h.ctx)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned all units")
+	c.JSON(http.StatusOK, units)
+}
+
+// @Summary Set item alternate unit
+// @Description Add or change alternate unit of item, factor is the number of base units in one alternate unit, requires admin or manager authentication
+// @Tags units
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param unit path string true "Unit code"
+// @Param factor body dto.SetItemUnit true "Conversion factor"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/units/{unit} [put]
+// @Security BearerAuth
+func (h *Handler) SetItemUnit(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	var setItemUnit dto.SetItemUnit
+	if err := c.BindJSON(&setItemUnit); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(setItemUnit); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	itemUnit := model.ItemUnit{
+		ItemID: itemID,
+		Unit:   c.Param("unit"),
+		Factor: setItemUnit.Factor,
+	}
+	if err := h.service.SetItemUnit(h.ctx, itemUnit); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and set item unit")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully set item unit"})
+}
+
+// @Summary Get item alternate units
+// @Description Get alternate units of item with conversion factors, requires authentication
+// @Tags units
+// @Produce json
+// @Param id path int true "Item ID"
+// @Success 200 {array} model.ItemUnit
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/units [get]
+// @Security BearerAuth
+func (h *Handler) GetItemUnits(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	units, err := h.service.GetItemUnits(h.ctx, itemID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned item units")
+	c.JSON(http.StatusOK, units)
+}
+
+// @Summary Delete item alternate unit
+// @Description Remove alternate unit from item, requires admin authentication
+// @Tags units
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param unit path string true "Unit code"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /items/{id}/units/{unit} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteItemUnit(c *ginext.Context) {
+	id := c.Param("id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item is or was not provided"})
+		return
+	}
+
+	if err := h.service.DeleteItemUnit(h.ctx, itemID, c.Param("unit")); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted item unit")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted item unit"})
+}
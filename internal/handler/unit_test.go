@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_SetItemUnit(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		paramUnit        string
+		body             string
+		mockFunc         func(ctx context.Context, itemUnit model.ItemUnit) error
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:      "success",
+			paramID:   "1",
+			paramUnit: "box",
+			body:      `{"factor":24}`,
+			mockFunc: func(ctx context.Context, itemUnit model.ItemUnit) error {
+				if itemUnit.ItemID != 1 || itemUnit.Unit != "box" || itemUnit.Factor != 24 {
+					return repository.ErrNoSuchItem
+				}
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"status": "successfully set item unit",
+			},
+		},
+		{
+			name:           "invalid factor",
+			paramID:        "1",
+			paramUnit:      "box",
+			body:           `{"factor":-2}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "Factor is not valid due to gt",
+			},
+		},
+		{
+			name:      "unknown unit",
+			paramID:   "1",
+			paramUnit: "crate",
+			body:      `{"factor":10}`,
+			mockFunc: func(ctx context.Context, itemUnit model.ItemUnit) error {
+				return repository.ErrNoSuchUnit
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"error": repository.ErrNoSuchUnit.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/items/"+tt.paramID+"/units/"+tt.paramUnit, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}, {Key: "unit", Value: tt.paramUnit}}
+
+			mock := &mockService{
+				setItemUnitFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.SetItemUnit(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
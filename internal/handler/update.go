@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"strconv"
 	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/middleware"
+	_ "wharehouse-control/internal/model"
 	"wharehouse-control/internal/validator"
 
 	"github.com/gin-gonic/gin"
@@ -12,14 +14,21 @@ import (
 )
 
 // @Summary Update an item
-// @Description Update an item by ID, requires admin or manager authentication
+// @Description Update an item by ID, item version read by client must be sent in If-Match header or version field, requires admin or manager authentication. Manager change that breaks approval rules is not applied but saved as pending change request and returned with 202
 // @Tags items
 // @Accept json
 // @Produce json
 // @Param id path int true "Item ID"
+// @Param If-Match header string false "Item version from ETag"
 // @Param item body dto.UpdateItem true "Update data"
 // @Success 200 {object} gin.H
+// @Header 200 {string} ETag "New item version"
+// @Success 202 {object} model.ChangeRequest
 // @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 412 {object} gin.H
+// @Failure 428 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /items/{id} [put]
 // @Security BearerAuth
@@ -47,13 +56,42 @@ func (h *Handler) UpdateItem(c *ginext.Context) {
 		return
 	}
 
+	version, fromHeader, err := ifMatchVersion(c)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if fromHeader {
+		updateItem.Version = &version
+	} else if updateItem.Version == nil {
+		zlog.Logger.Error().Msg(errVersionRequired.Error())
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": errVersionRequired.Error()})
+		return
+	}
+
+	if middleware.IsManager(c) {
+		changeRequest, err := h.service.RequestItemUpdate(h.ctx, &updateItem)
+		if err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+			c.JSON(versionStatus(err, fromHeader), errorBody(err))
+			return
+		}
+		if changeRequest != nil {
+			zlog.Logger.Info().Msg("successfully handled request and created change request for item update")
+			c.JSON(http.StatusAccepted, changeRequest)
+			return
+		}
+	}
+
 	err = h.service.UpdateItem(h.ctx, &updateItem)
 	if err != nil {
 		zlog.Logger.Error().Msg(err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(versionStatus(err, fromHeader), errorBody(err))
 		return
 	}
 
 	zlog.Logger.Info().Msg("successfully handled request and updated item")
-	c.JSON(http.StatusOK, gin.H{"status": "successfully updated item"})
+	c.Header("ETag", etag(*updateItem.Version))
+	c.JSON(http.StatusOK, gin.H{"status": "successfully updated item", "version": *updateItem.Version})
 }
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Get inventory valuation
+// @Description Get value of stock on hand at the end of the day per item and warehouse with totals per category and warehouse, requires authentication
+// @Tags reports
+// @Produce json
+// @Param as_of query string false "Date in YYYY-MM-DD format, today by default"
+// @Param warehouse_id query int false "Only stock in this warehouse"
+// @Param category_id query int false "Only items of this category and its subcategories"
+// @Param method query string false "Valuation method: fifo or average, configured method by default"
+// @Success 200 {object} model.Valuation
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /reports/valuation [get]
+// @Security BearerAuth
+func (h *Handler) GetValuation(c *ginext.Context) {
+	filter := dto.ValuationFilter{AsOf: time.Now().Truncate(24 * time.Hour)}
+	if asOfParam := c.Query("as_of"); asOfParam != "" {
+		asOf, err := time.Parse(time.DateOnly, asOfParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid as_of: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of, expected YYYY-MM-DD"})
+			return
+		}
+		filter.AsOf = asOf
+	}
+
+	if warehouseParam := c.Query("warehouse_id"); warehouseParam != "" {
+		warehouseID, err := strconv.Atoi(warehouseParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid warehouse id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id"})
+			return
+		}
+		filter.WarehouseID = &warehouseID
+	}
+
+	if categoryParam := c.Query("category_id"); categoryParam != "" {
+		categoryID, err := strconv.Atoi(categoryParam)
+		if err != nil {
+			zlog.Logger.Error().Msg("invalid category id: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+			return
+		}
+		filter.CategoryID = &categoryID
+	}
+
+	switch method := c.Query("method"); method {
+	case "", model.ValuationMethodFIFO, model.ValuationMethodAverage:
+		filter.Method = method
+	default:
+		zlog.Logger.Error().Msg("invalid valuation method: " + method)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid valuation method"})
+		return
+	}
+
+	valuation, err := h.service.GetValuation(h.ctx, filter)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned valuation")
+	c.JSON(http.StatusOK, valuation)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_GetValuation(t *testing.T) {
+	categoryID := 3
+	warehouseID := 1
+
+	tests := []struct {
+		name             string
+		query            string
+		mockFunc         func(ctx context.Context, filter dto.ValuationFilter) (*model.Valuation, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:  "success",
+			query: "?as_of=2025-10-31&method=average",
+			mockFunc: func(ctx context.Context, filter dto.ValuationFilter) (*model.Valuation, error) {
+				if !filter.AsOf.Equal(time.Date(2025, 10, 31, 0, 0, 0, 0, time.UTC)) {
+					return nil, errors.New("unexpected as_of")
+				}
+				return &model.Valuation{
+					AsOf:   filter.AsOf,
+					Method: filter.Method,
+					Total:  1250,
+					Items: []model.ItemValuation{
+						{ItemID: 1, CategoryID: &categoryID, WarehouseID: warehouseID, Quantity: 50, Value: 1250, UnitCost: 25},
+					},
+					Categories: []model.GroupValuation{{ID: &categoryID, Name: "Tools", Value: 1250}},
+					Warehouses: []model.GroupValuation{{ID: &warehouseID, Name: "Main", Value: 1250}},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"method": "average",
+				"total":  float64(1250),
+			},
+		},
+		{
+			name:  "configured method by default",
+			query: "",
+			mockFunc: func(ctx context.Context, filter dto.ValuationFilter) (*model.Valuation, error) {
+				return &model.Valuation{AsOf: filter.AsOf, Method: model.ValuationMethodFIFO}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"method": "fifo",
+			},
+		},
+		{
+			name:           "invalid date",
+			query:          "?as_of=31.10.2025",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid as_of, expected YYYY-MM-DD",
+			},
+		},
+		{
+			name:           "invalid method",
+			query:          "?method=lifo",
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid valuation method",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/reports/valuation"+tt.query, nil)
+
+			mock := &mockService{
+				getValuationFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.GetValuation(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"wharehouse-control/internal/repository"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+var errVersionRequired = errors.New("If-Match header or item version is required")
+
+// etag formats item version as strong entity tag
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ifMatchVersion reads item version from If-Match header,
+// ok is false when client has not sent the header
+func ifMatchVersion(c *ginext.Context) (version int, ok bool, err error) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return 0, false, nil
+	}
+
+	header = strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err = strconv.Atoi(header)
+	if err != nil {
+		return 0, false, errors.New("invalid If-Match header")
+	}
+
+	return version, true, nil
+}
+
+// versionStatus is statusFromError except stale If-Match that is
+// reported as failed precondition, stale body version stays 409
+func versionStatus(err error, fromHeader bool) int {
+	if fromHeader && errors.Is(err, repository.ErrVersionConflict) {
+		return http.StatusPreconditionFailed
+	}
+	return statusFromError(err)
+}
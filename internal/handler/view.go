@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+var errUserIDRequired = errors.New("user_id is required")
+
+// queryUserID reads owner of saved views from user_id query parameter
+func queryUserID(c *ginext.Context) (int, error) {
+	userParam := c.Query("user_id")
+	if userParam == "" {
+		return 0, errUserIDRequired
+	}
+
+	userID, err := strconv.Atoi(userParam)
+	if err != nil {
+		return 0, errors.New("invalid user id")
+	}
+
+	return userID, nil
+}
+
+// @Summary Create saved view
+// @Description Save item list filter of user under a name to list and apply it later with view_id of GET /items, requires authentication
+// @Tags views
+// @Accept json
+// @Produce json
+// @Param view body dto.SaveView true "Name and filter of view"
+// @Success 201 {object} model.SavedView
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /views [post]
+// @Security BearerAuth
+func (h *Handler) CreateSavedView(c *ginext.Context) {
+	var saveView dto.SaveView
+	if err := c.BindJSON(&saveView); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(saveView); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	view, err := h.service.CreateSavedView(h.ctx, saveView)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created saved view")
+	c.JSON(http.StatusCreated, view)
+}
+
+// @Summary Get saved views
+// @Description Get saved views of user ordered by name, requires authentication
+// @Tags views
+// @Produce json
+// @Param user_id query int true "Owner of views"
+// @Success 200 {array} model.SavedView
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /views [get]
+// @Security BearerAuth
+func (h *Handler) GetSavedViews(c *ginext.Context) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	views, err := h.service.GetSavedViews(h.ctx, userID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned saved views")
+	c.JSON(http.StatusOK, views)
+}
+
+// @Summary Get saved view
+// @Description Get saved view of user, views of other users are not found, requires authentication
+// @Tags views
+// @Produce json
+// @Param id path int true "Saved view ID"
+// @Param user_id query int true "Owner of view"
+// @Success 200 {object} model.SavedView
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /views/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetSavedViewByID(c *ginext.Context) {
+	viewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved view id or was not provided"})
+		return
+	}
+
+	userID, err := queryUserID(c)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	view, err := h.service.GetSavedViewByID(h.ctx, viewID, userID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned saved view")
+	c.JSON(http.StatusOK, view)
+}
+
+// @Summary Update saved view
+// @Description Replace name and filter of saved view, only owner given by user_id may change it, requires authentication
+// @Tags views
+// @Accept json
+// @Produce json
+// @Param id path int true "Saved view ID"
+// @Param view body dto.SaveView true "Name and filter of view"
+// @Success 200 {object} model.SavedView
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /views/{id} [put]
+// @Security BearerAuth
+func (h *Handler) UpdateSavedView(c *ginext.Context) {
+	viewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved view id or was not provided"})
+		return
+	}
+
+	var saveView dto.SaveView
+	if err := c.BindJSON(&saveView); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	saveView.ID = viewID
+
+	if err := validator.Validate.Struct(saveView); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	view, err := h.service.UpdateSavedView(h.ctx, &saveView)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and updated saved view")
+	c.JSON(http.StatusOK, view)
+}
+
+// @Summary Delete saved view
+// @Description Delete saved view, only owner given by user_id may delete it, requires authentication
+// @Tags views
+// @Produce json
+// @Param id path int true "Saved view ID"
+// @Param user_id query int true "Owner of view"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /views/{id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteSavedView(c *ginext.Context) {
+	viewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved view id or was not provided"})
+		return
+	}
+
+	userID, err := queryUserID(c)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.DeleteSavedView(h.ctx, viewID, userID); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted saved view")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted saved view"})
+}
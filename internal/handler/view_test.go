@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+	"wharehouse-control/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_SetItemTags(t *testing.T) {
+	tests := []struct {
+		name             string
+		paramID          string
+		body             string
+		mockFunc         func(ctx context.Context, setTags *dto.SetItemTags) (*model.Item, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:    "success",
+			paramID: "1",
+			body:    `{"tags": ["Fragile", "promo"]}`,
+			mockFunc: func(ctx context.Context, setTags *dto.SetItemTags) (*model.Item, error) {
+				if setTags.ItemID != 1 || len(setTags.Tags) != 2 {
+					return nil, errors.New("unexpected tags")
+				}
+				return &model.Item{ID: 1, Name: "vase", Tags: []string{"fragile", "promo"}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"id": float64(1),
+			},
+		},
+		{
+			name:           "tag too long",
+			paramID:        "1",
+			body:           fmt.Sprintf(`{"tags": ["%s"]}`, bytes.Repeat([]byte("a"), 51)),
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "blank tag",
+			paramID: "1",
+			body:    `{"tags": ["  "]}`,
+			mockFunc: func(ctx context.Context, setTags *dto.SetItemTags) (*model.Item, error) {
+				return nil, fmt.Errorf("%w: tag cannot be blank", service.ErrInvalidTag)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error": "invalid tag: tag cannot be blank",
+			},
+		},
+		{
+			name:    "archived item",
+			paramID: "2",
+			body:    `{"tags": ["promo"]}`,
+			mockFunc: func(ctx context.Context, setTags *dto.SetItemTags) (*model.Item, error) {
+				return nil, repository.ErrItemArchived
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": "item is archived",
+			},
+		},
+		{
+			name:           "invalid id",
+			paramID:        "abc",
+			body:           `{"tags": []}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/items/"+tt.paramID+"/tags", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.paramID}}
+
+			mock := &mockService{
+				setItemTagsFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.SetItemTags(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_CreateSavedView(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		mockFunc         func(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error)
+		expectedStatus   int
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name: "success",
+			body: `{"user_id": 1, "name": "fragile in main", "filter": {"warehouse_id": 1, "tags": ["fragile"]}}`,
+			mockFunc: func(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error) {
+				if saveView.Filter.WarehouseID == nil || *saveView.Filter.WarehouseID != 1 {
+					return nil, errors.New("unexpected filter")
+				}
+				filter, _ := json.Marshal(saveView.Filter)
+				return &model.SavedView{ID: 3, UserID: saveView.UserID, Name: saveView.Name, Filter: filter}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResponse: map[string]interface{}{
+				"id":   float64(3),
+				"name": "fragile in main",
+			},
+		},
+		{
+			name:           "missing name",
+			body:           `{"user_id": 1, "filter": {}}`,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "name taken",
+			body: `{"user_id": 1, "name": "promo"}`,
+			mockFunc: func(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error) {
+				return nil, repository.ErrSavedViewExists
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponse: map[string]interface{}{
+				"error": "saved view with such name already exists",
+			},
+		},
+		{
+			name: "unknown user",
+			body: `{"user_id": 99, "name": "promo"}`,
+			mockFunc: func(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error) {
+				return nil, repository.ErrNoSuchUser
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/views", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			mock := &mockService{
+				createSavedViewFunc: tt.mockFunc,
+			}
+			h := New(context.Background(), mock)
+			h.CreateSavedView(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			for k, v := range tt.expectedResponse {
+				if resp[k] != v {
+					t.Errorf("expected %v for %s, got %v", v, k, resp[k])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	_ "wharehouse-control/internal/model"
+	"wharehouse-control/internal/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// @Summary Create a new warehouse
+// @Description Create a new warehouse, requires admin authentication
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param warehouse body dto.CreateWarehouse true "Warehouse creation data"
+// @Success 200 {object} model.Warehouse
+// @Failure 400 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /warehouses [post]
+// @Security BearerAuth
+func (h *Handler) CreateWarehouse(c *ginext.Context) {
+	var createWarehouse dto.CreateWarehouse
+
+	if err := c.BindJSON(&createWarehouse); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.Validate.Struct(createWarehouse); err != nil {
+		errMsg := validator.CreateValidationErrorResponse(err)
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	warehouse, err := h.service.CreateWarehouse(h.ctx, createWarehouse)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and created new warehouse")
+	c.JSON(http.StatusOK, warehouse)
+}
+
+// @Summary Get all warehouses
+// @Description Get all warehouses, requires authentication
+// @Tags warehouses
+// @Produce json
+// @Success 200 {array} model.Warehouse
+// @Failure 500 {object} gin.H
+// @Router /warehouses [get]
+// @Security BearerAuth
+func (h *Handler) GetWarehouses(c *ginext.Context) {
+	warehouses, err := h.service.GetWarehouses(h.ctx)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned all warehouses")
+	c.JSON(http.StatusOK, warehouses)
+}
+
+// @Summary Get warehouse
+// @Description Get warehouse by ID, requires authentication
+// @Tags warehouses
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Success 200 {object} model.Warehouse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /warehouses/{id} [get]
+// @Security BearerAuth
+func (h *Handler) GetWarehouseByID(c *ginext.Context) {
+	id := c.Param("id")
+	warehouseID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id or was not provided"})
+		return
+	}
+
+	warehouse, err := h.service.GetWarehouseByID(h.ctx, warehouseID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and returned warehouse")
+	c.JSON(http.StatusOK, warehouse)
+}
+
+// @Summary Update a warehouse
+// @Description Update warehouse name or address, requires admin or manager authentication
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Param warehouse body dto.UpdateWarehouse true "Update data"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /warehouses/{id} [put]
+// @Security BearerAuth
+func (h *Handler) UpdateWarehouse(c *ginext.Context) {
+	id := c.Param("id")
+	warehouseID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id or was not provided"})
+		return
+	}
+
+	var updateWarehouse dto.UpdateWarehouse
+	if err := c.BindJSON(&updateWarehouse); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updateWarehouse.ID = warehouseID
+
+	err = h.service.UpdateWarehouse(h.ctx, &updateWarehouse)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and updated warehouse")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully updated warehouse"})
+}
+
+// @Summary Delete a warehouse
+// @Description Delete a warehouse that holds no stock, requires admin authentication
+// @Tags warehouses
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /warehouses/{id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteWarehouse(c *ginext.Context) {
+	id := c.Param("id")
+	warehouseID, err := strconv.Atoi(id)
+	if err != nil {
+		zlog.Logger.Error().Msg("invalid id: " + err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse id or was not provided"})
+		return
+	}
+
+	err = h.service.DeleteWarehouse(h.ctx, warehouseID)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	zlog.Logger.Info().Msg("successfully handled request and deleted warehouse")
+	c.JSON(http.StatusOK, gin.H{"status": "successfully deleted warehouse"})
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+	"wharehouse-control/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	replayedHeader       = "Idempotent-Replayed"
+	maxIdempotencyKeyLen = 255
+	// maxIdempotentBodySize is how much of request body is kept to be
+	// fingerprinted, it leaves room for the largest default attachment
+	maxIdempotentBodySize = 16 << 20
+	// defaultIdempotencyLease is used when lease is not set in config
+	defaultIdempotencyLease = 5 * time.Minute
+)
+
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, ttl, lease time.Duration) (*model.IdempotencyRecord, error)
+	SaveIdempotentResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+}
+
+// responseRecorder keeps copy of response body to store it for replays
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware makes POST, PUT and DELETE requests with
+// Idempotency-Key header safe to retry: the first response is stored
+// for ttl and replayed to retries, reuse of key for other payload is rejected.
+// Keys are scoped by caller token, so clients cannot collide on the
+// same key, and key of request that never finished is taken over
+// by retry after lease
+func IdempotencyMiddleware(store IdempotencyStore, ttl, lease time.Duration) gin.HandlerFunc {
+	if lease <= 0 {
+		lease = defaultIdempotencyLease
+	}
+
+	return func(c *gin.Context) {
+		clientKey := c.GetHeader(idempotencyKeyHeader)
+		method := c.Request.Method
+		if clientKey == "" || (method != http.MethodPost && method != http.MethodPut && method != http.MethodDelete) {
+			c.Next()
+			return
+		}
+
+		if len(clientKey) > maxIdempotencyKeyLen {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "idempotency key is too long"})
+			return
+		}
+		key := scopedIdempotencyKey(c.GetHeader("Authorization"), clientKey)
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "could not read request body"})
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body is too large for idempotency key"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := requestFingerprint(method, c.Request.URL.RequestURI(), body)
+
+		ctx := c.Request.Context()
+		record, err := store.ReserveIdempotencyKey(ctx, key, fingerprint, ttl, lease)
+		if err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if record != nil {
+			switch {
+			case record.Fingerprint != fingerprint:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "idempotency key was already used for another request"})
+			case record.StatusCode == nil:
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "request with this idempotency key is still in progress"})
+			default:
+				zlog.Logger.Info().Msg("replayed stored response for idempotency key " + clientKey)
+				c.Header(replayedHeader, "true")
+				c.Data(*record.StatusCode, record.ContentType, record.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// server side failures are not stored so that retry may succeed
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := store.ReleaseIdempotencyKey(ctx, key); err != nil {
+				zlog.Logger.Error().Msg(err.Error())
+			}
+			return
+		}
+
+		err = store.SaveIdempotentResponse(ctx, key, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		if err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}
+}
+
+// requestFingerprint tells apart requests sent with the same key
+func requestFingerprint(method, uri string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + uri + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// scopedIdempotencyKey binds key to caller, the token is hashed
+// together with key so that it is never stored as is
+func scopedIdempotencyKey(caller, key string) string {
+	hash := sha256.Sum256([]byte(caller + "\n" + key))
+	return hex.EncodeToString(hash[:])
+}
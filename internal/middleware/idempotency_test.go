@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wharehouse-control/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeIdempotencyStore keeps keys in memory the way repository does in db
+type fakeIdempotencyStore struct {
+	records   map[string]*model.IdempotencyRecord
+	createdAt map[string]time.Time
+	released  []string
+}
+
+func newFakeIdempotencyStore() *fakeIdempotencyStore {
+	return &fakeIdempotencyStore{
+		records:   make(map[string]*model.IdempotencyRecord),
+		createdAt: make(map[string]time.Time),
+	}
+}
+
+func (s *fakeIdempotencyStore) ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, ttl, lease time.Duration) (*model.IdempotencyRecord, error) {
+	record, ok := s.records[key]
+	if ok && (record.StatusCode != nil || time.Since(s.createdAt[key]) < lease) {
+		return record, nil
+	}
+
+	s.records[key] = &model.IdempotencyRecord{Fingerprint: fingerprint}
+	s.createdAt[key] = time.Now()
+	return nil, nil
+}
+
+func (s *fakeIdempotencyStore) SaveIdempotentResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	record := s.records[key]
+	record.StatusCode = &statusCode
+	record.ContentType = contentType
+	record.Body = body
+	return nil
+}
+
+func (s *fakeIdempotencyStore) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	delete(s.records, key)
+	s.released = append(s.released, key)
+	return nil
+}
+
+type idempotentRequest struct {
+	token string
+	key   string
+	body  string
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	tests := []struct {
+		name            string
+		handlerStatus   int
+		requests        []idempotentRequest
+		expectedStatus  []int
+		expectedCalls   int
+		expectedReplays int
+		released        bool
+	}{
+		{
+			name:          "replay of the same request",
+			handlerStatus: http.StatusCreated,
+			requests: []idempotentRequest{
+				{key: "key-1", body: `{"name":"bolt"}`},
+				{key: "key-1", body: `{"name":"bolt"}`},
+			},
+			expectedStatus:  []int{http.StatusCreated, http.StatusCreated},
+			expectedCalls:   1,
+			expectedReplays: 1,
+		},
+		{
+			name:          "payload mismatch",
+			handlerStatus: http.StatusCreated,
+			requests: []idempotentRequest{
+				{key: "key-1", body: `{"name":"bolt"}`},
+				{key: "key-1", body: `{"name":"nut"}`},
+			},
+			expectedStatus: []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			expectedCalls:  1,
+		},
+		{
+			name:          "requests without key are not stored",
+			handlerStatus: http.StatusCreated,
+			requests: []idempotentRequest{
+				{body: `{"name":"bolt"}`},
+				{body: `{"name":"bolt"}`},
+			},
+			expectedStatus: []int{http.StatusCreated, http.StatusCreated},
+			expectedCalls:  2,
+		},
+		{
+			name:          "release after server error",
+			handlerStatus: http.StatusInternalServerError,
+			requests: []idempotentRequest{
+				{key: "key-1", body: `{"name":"bolt"}`},
+				{key: "key-1", body: `{"name":"bolt"}`},
+			},
+			expectedStatus: []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			expectedCalls:  2,
+			released:       true,
+		},
+		{
+			name:          "same key of other callers",
+			handlerStatus: http.StatusCreated,
+			requests: []idempotentRequest{
+				{token: "Bearer first", key: "key-1", body: `{"name":"bolt"}`},
+				{token: "Bearer second", key: "key-1", body: `{"name":"bolt"}`},
+			},
+			expectedStatus: []int{http.StatusCreated, http.StatusCreated},
+			expectedCalls:  2,
+		},
+		{
+			name:          "body too large",
+			handlerStatus: http.StatusCreated,
+			requests: []idempotentRequest{
+				{key: "key-1", body: strings.Repeat("a", maxIdempotentBodySize+1)},
+			},
+			expectedStatus: []int{http.StatusRequestEntityTooLarge},
+			expectedCalls:  0,
+		},
+		{
+			name:          "key too long",
+			handlerStatus: http.StatusCreated,
+			requests: []idempotentRequest{
+				{key: strings.Repeat("k", maxIdempotencyKeyLen+1), body: `{"name":"bolt"}`},
+			},
+			expectedStatus: []int{http.StatusBadRequest},
+			expectedCalls:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			store := newFakeIdempotencyStore()
+
+			calls := 0
+			router := gin.New()
+			router.Use(IdempotencyMiddleware(store, time.Hour, time.Minute))
+			router.POST("/items", func(c *gin.Context) {
+				calls++
+				c.JSON(tt.handlerStatus, gin.H{"calls": calls})
+			})
+
+			replays := 0
+			for i, request := range tt.requests {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(request.body))
+				if request.token != "" {
+					req.Header.Set("Authorization", request.token)
+				}
+				if request.key != "" {
+					req.Header.Set(idempotencyKeyHeader, request.key)
+				}
+				router.ServeHTTP(w, req)
+
+				if w.Code != tt.expectedStatus[i] {
+					t.Errorf("request %d: expected status %d, got %d", i, tt.expectedStatus[i], w.Code)
+				}
+				if w.Header().Get(replayedHeader) == "true" {
+					replays++
+				}
+			}
+
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d handler calls, got %d", tt.expectedCalls, calls)
+			}
+			if replays != tt.expectedReplays {
+				t.Errorf("expected %d replays, got %d", tt.expectedReplays, replays)
+			}
+			if released := len(store.released) > 0; released != tt.released {
+				t.Errorf("expected key released %v, got %v", tt.released, released)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_InProgress(t *testing.T) {
+	body := `{"name":"bolt"}`
+	tests := []struct {
+		name           string
+		startedAgo     time.Duration
+		expectedStatus int
+		expectedCalls  int
+	}{
+		{
+			name:           "within lease",
+			startedAgo:     time.Second,
+			expectedStatus: http.StatusConflict,
+			expectedCalls:  0,
+		},
+		{
+			name:           "reclaimed after lease",
+			startedAgo:     2 * time.Minute,
+			expectedStatus: http.StatusCreated,
+			expectedCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			key := scopedIdempotencyKey("Bearer token", "key-1")
+			store := newFakeIdempotencyStore()
+			store.records[key] = &model.IdempotencyRecord{
+				Fingerprint: requestFingerprint(http.MethodPost, "/items", []byte(body)),
+			}
+			store.createdAt[key] = time.Now().Add(-tt.startedAgo)
+
+			calls := 0
+			router := gin.New()
+			router.Use(IdempotencyMiddleware(store, time.Hour, time.Minute))
+			router.POST("/items", func(c *gin.Context) {
+				calls++
+				c.JSON(http.StatusCreated, gin.H{"calls": calls})
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set(idempotencyKeyHeader, "key-1")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d handler calls, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
@@ -14,6 +14,64 @@ const (
 	viewer  string = "viewer"
 )
 
+// roleKey is gin context key user role is stored under
+const roleKey = "role"
+
+// managerPostRoutes are day to day stock operations that
+// managers may create, other POST requests are admin only
+var managerPostRoutes = map[string]bool{
+	"/items/:id/adjust":         true,
+	"/items/:id/restore":        true,
+	"/items/:id/assemble":       true,
+	"/items/:id/disassemble":    true,
+	"/items/:id/attachments":    true,
+	"/reservations":             true,
+	"/reservations/:id/confirm": true,
+	"/reservations/:id/release": true,
+	"/alerts/:id/acknowledge":   true,
+
+	"/purchase-orders":                true,
+	"/purchase-orders/from-low-stock": true,
+	"/purchase-orders/:id/lines":      true,
+	"/purchase-orders/:id/send":       true,
+	"/purchase-orders/:id/close":      true,
+	"/receipts":                       true,
+
+	"/outbound-orders":               true,
+	"/outbound-orders/:id/pick-list": true,
+	"/outbound-orders/:id/pack":      true,
+	"/outbound-orders/:id/ship":      true,
+	"/outbound-orders/:id/cancel":    true,
+
+	"/transfer-orders":              true,
+	"/transfer-orders/:id/dispatch": true,
+	"/transfer-orders/:id/receive":  true,
+	"/transfer-orders/:id/cancel":   true,
+
+	"/stocktakes":            true,
+	"/stocktakes/:id/counts": true,
+}
+
+// managerDeleteRoutes may be called by managers, item deletion of
+// manager is only ever saved as change request for admin approval
+var managerDeleteRoutes = map[string]bool{
+	"/items/:id":       true,
+	"/attachments/:id": true,
+}
+
+// personalRoutes manage data of user's own, like saved views,
+// and are open to every role whatever the method is
+var personalRoutes = map[string]bool{
+	"/views":     true,
+	"/views/:id": true,
+}
+
+// IsManager tells handler that request is made by manager,
+// whose item changes are checked against approval rules
+func IsManager(c *gin.Context) bool {
+	return c.GetString(roleKey) == manager
+}
+
 func AuthMiddleware(secret []byte) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -52,8 +110,17 @@ func AuthMiddleware(secret []byte) gin.HandlerFunc {
 			return
 		}
 
+		c.Set(roleKey, userRole)
+
 		requestMethod := c.Request.Method
 		switch {
+		case personalRoutes[c.FullPath()]:
+		case requestMethod == http.MethodPost && managerPostRoutes[c.FullPath()],
+			requestMethod == http.MethodDelete && managerDeleteRoutes[c.FullPath()]:
+			if userRole != admin && userRole != manager {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "not allowed"})
+				return
+			}
 		case requestMethod == http.MethodPost || requestMethod == http.MethodDelete:
 			if userRole != admin {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "not allowed"})
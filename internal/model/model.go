@@ -1,12 +1,30 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Item struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Count     int       `json:"count"`
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Count      float64        `json:"count"`
+	Unit       string         `json:"unit"`
+	CategoryID *int           `json:"category_id"`
+	Attributes map[string]any `json:"attributes"`
+	Stock      []StockLevel   `json:"stock"`
+	// Tags are free-form labels in lowercase, sorted by name
+	Tags []string `json:"tags"`
+	// Serialized items have every unit tracked by serial number
+	Serialized bool `json:"serialized"`
+	// Version grows on every change of item and is used as its ETag
+	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
+	// DeletedAt is set while item is archived
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy *int       `json:"deleted_by,omitempty"`
+	// MergedInto is the item archived item was merged into
+	MergedInto *int `json:"merged_into,omitempty"`
 }
 
 type User struct {
@@ -28,3 +46,643 @@ type UserHistory struct {
 	User
 	History []Change `json:"history"`
 }
+
+type Category struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	ParentID  *int       `json:"parent_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Children  []Category `json:"children,omitempty"`
+}
+
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+)
+
+type AttributeDefinition struct {
+	ID            int       `json:"id"`
+	CategoryID    int       `json:"category_id"`
+	Name          string    `json:"name"`
+	Type          string    `json:"type"`
+	Required      bool      `json:"required"`
+	AllowedValues []string  `json:"allowed_values"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+const DefaultUnit = "pcs"
+
+type Unit struct {
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	AllowDecimal bool   `json:"allow_decimal"`
+}
+
+// ItemUnit is an alternate unit of item, one ItemUnit equals Factor base units
+type ItemUnit struct {
+	ItemID int     `json:"item_id"`
+	Unit   string  `json:"unit"`
+	Factor float64 `json:"factor"`
+}
+
+type Warehouse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StockLevel is item quantity in a single warehouse
+type StockLevel struct {
+	WarehouseID   int     `json:"warehouse_id"`
+	WarehouseName string  `json:"warehouse_name"`
+	Quantity      float64 `json:"quantity"`
+	// Reserved is held by active reservations, Available is
+	// what is left to promise, on-hand Quantity includes both
+	Reserved  float64 `json:"reserved"`
+	Available float64 `json:"available"`
+	// InTransit is dispatched to this warehouse by transfer
+	// orders but not received yet, it is not part of Quantity
+	InTransit float64 `json:"in_transit"`
+}
+
+const (
+	LocationTypeZone  = "zone"
+	LocationTypeAisle = "aisle"
+	LocationTypeRack  = "rack"
+	LocationTypeBin   = "bin"
+)
+
+// LocationLevels orders location types from the outermost to the innermost
+var LocationLevels = []string{LocationTypeZone, LocationTypeAisle, LocationTypeRack, LocationTypeBin}
+
+type Location struct {
+	ID          int       `json:"id"`
+	WarehouseID int       `json:"warehouse_id"`
+	ParentID    *int      `json:"parent_id"`
+	Type        string    `json:"type"`
+	Code        string    `json:"code"`
+	Path        string    `json:"path"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ItemLocation is item quantity at a single location, entries
+// without location hold the part of warehouse stock not put away yet
+type ItemLocation struct {
+	WarehouseID   int     `json:"warehouse_id"`
+	WarehouseName string  `json:"warehouse_name"`
+	LocationID    *int    `json:"location_id"`
+	Path          string  `json:"path"`
+	Quantity      float64 `json:"quantity"`
+}
+
+const (
+	MovementReasonOpeningBalance = "opening_balance"
+	MovementReasonInitial        = "initial"
+	MovementReasonCorrection     = "correction"
+	MovementReasonDamage         = "damage"
+	MovementReasonTheft          = "theft"
+	MovementReasonFound          = "found"
+	MovementReasonSample         = "sample"
+	MovementReasonReservation    = "reservation"
+	MovementReasonReceipt        = "receipt"
+	MovementReasonShipment       = "shipment"
+	MovementReasonTransferOut    = "transfer_out"
+	MovementReasonTransferIn     = "transfer_in"
+	MovementReasonStocktake      = "stocktake"
+	MovementReasonKitAssembly    = "kit_assembly"
+	MovementReasonKitDisassembly = "kit_disassembly"
+)
+
+// Movement is an entry of append-only stock ledger, on-hand
+// quantity of item is always the sum of its movements
+type Movement struct {
+	ID          int64     `json:"id"`
+	ItemID      int       `json:"item_id"`
+	WarehouseID int       `json:"warehouse_id"`
+	LocationID  *int      `json:"location_id"`
+	LotID       *int      `json:"lot_id"`
+	Quantity    float64   `json:"quantity"`
+	Reason      string    `json:"reason"`
+	Reference   string    `json:"reference"`
+	UserID      *int      `json:"user_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Serials are serial numbers of units moved, only for serialized items
+	Serials []string `json:"serials,omitempty"`
+	// UnitCost is cost of base unit of inbound stock, stock that
+	// comes in without cost is valued at current average cost
+	UnitCost *float64 `json:"unit_cost,omitempty"`
+	// FIFOCost and AverageCost are values movement adds to stock by
+	// each valuation method, negative for outbound movements
+	FIFOCost    *float64 `json:"fifo_cost,omitempty"`
+	AverageCost *float64 `json:"average_cost,omitempty"`
+}
+
+const (
+	ReservationStatusActive    = "active"
+	ReservationStatusConfirmed = "confirmed"
+	ReservationStatusReleased  = "released"
+	ReservationStatusExpired   = "expired"
+)
+
+// Reservation holds stock for an order, it lowers available
+// quantity until confirmed, released or expired but never on-hand
+type Reservation struct {
+	ID          int       `json:"id"`
+	ItemID      int       `json:"item_id"`
+	WarehouseID int       `json:"warehouse_id"`
+	Quantity    float64   `json:"quantity"`
+	Reference   string    `json:"reference"`
+	Status      string    `json:"status"`
+	UserID      *int      `json:"user_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Lot is a production batch of item kept for traceability,
+// Quantity is its total stock over all warehouses
+type Lot struct {
+	ID             int        `json:"id"`
+	ItemID         int        `json:"item_id"`
+	LotNumber      string     `json:"lot_number"`
+	ManufacturedAt *time.Time `json:"manufactured_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	Quantity       float64    `json:"quantity"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// LotPick is quantity to take from a lot when picking
+type LotPick struct {
+	LotID     int        `json:"lot_id"`
+	LotNumber string     `json:"lot_number"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Available float64    `json:"available"`
+	Quantity  float64    `json:"quantity"`
+}
+
+// PickSuggestion lists lots to pick first-expired-first-out,
+// Shortage is requested quantity that no unexpired lot covers
+type PickSuggestion struct {
+	ItemID      int       `json:"item_id"`
+	WarehouseID int       `json:"warehouse_id"`
+	Requested   float64   `json:"requested"`
+	Shortage    float64   `json:"shortage"`
+	Picks       []LotPick `json:"picks"`
+}
+
+type ExpiringLot struct {
+	LotID         int       `json:"lot_id"`
+	LotNumber     string    `json:"lot_number"`
+	ItemID        int       `json:"item_id"`
+	ItemName      string    `json:"item_name"`
+	WarehouseID   int       `json:"warehouse_id"`
+	WarehouseName string    `json:"warehouse_name"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Quantity      float64   `json:"quantity"`
+}
+
+const (
+	SerialStatusInStock  = "in_stock"
+	SerialStatusReserved = "reserved"
+	SerialStatusShipped  = "shipped"
+	SerialStatusReturned = "returned"
+	// SerialStatusInTransit units are on the way to another warehouse
+	SerialStatusInTransit = "in_transit"
+)
+
+// Serial is a single unit of serialized item, WarehouseID is
+// where it was seen last and ReservationID holds it for an order
+type Serial struct {
+	ID            int       `json:"id"`
+	ItemID        int       `json:"item_id"`
+	SerialNumber  string    `json:"serial_number"`
+	Status        string    `json:"status"`
+	WarehouseID   *int      `json:"warehouse_id"`
+	ReservationID *int      `json:"reservation_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SerialHistory is path of a unit from receipt to shipment
+type SerialHistory struct {
+	Serial
+	Movements []Movement `json:"movements"`
+}
+
+// ReorderRule sets stock levels of item in the warehouse or, without
+// warehouse, of its total stock: falling below MinLevel calls for order
+type ReorderRule struct {
+	ID              int      `json:"id"`
+	ItemID          int      `json:"item_id"`
+	WarehouseID     *int     `json:"warehouse_id"`
+	MinLevel        float64  `json:"min_level"`
+	MaxLevel        *float64 `json:"max_level"`
+	ReorderQuantity *float64 `json:"reorder_quantity"`
+}
+
+// LowStockItem is stock below its reorder point with quantity to order
+type LowStockItem struct {
+	ReorderRule
+	ItemName          string  `json:"item_name"`
+	WarehouseName     *string `json:"warehouse_name"`
+	Quantity          float64 `json:"quantity"`
+	SuggestedQuantity float64 `json:"suggested_quantity"`
+}
+
+const (
+	StockAlertLowStock  = "low_stock"
+	StockAlertRestocked = "restocked"
+)
+
+// StockAlert is raised by movement that crosses reorder point
+type StockAlert struct {
+	ID             int64      `json:"id"`
+	ItemID         int        `json:"item_id"`
+	WarehouseID    *int       `json:"warehouse_id"`
+	Type           string     `json:"type"`
+	Quantity       float64    `json:"quantity"`
+	MinLevel       float64    `json:"min_level"`
+	MovementID     *int64     `json:"movement_id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+}
+
+type Supplier struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ContactName string `json:"contact_name"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	// LeadTimeDays is usual time from sending order to delivery
+	LeadTimeDays int            `json:"lead_time_days"`
+	CreatedAt    time.Time      `json:"created_at"`
+	Items        []SupplierItem `json:"items,omitempty"`
+}
+
+// SupplierItem is price list entry of supplier, MinOrderQuantity
+// is in item base unit
+type SupplierItem struct {
+	SupplierID       int     `json:"supplier_id"`
+	ItemID           int     `json:"item_id"`
+	SupplierSKU      string  `json:"supplier_sku"`
+	Price            float64 `json:"price"`
+	MinOrderQuantity float64 `json:"min_order_quantity"`
+}
+
+const (
+	PurchaseOrderStatusDraft             = "draft"
+	PurchaseOrderStatusSent              = "sent"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusClosed            = "closed"
+)
+
+// PurchaseOrder is order of items from supplier delivered to the
+// warehouse, its lines can be changed only while it is a draft
+type PurchaseOrder struct {
+	ID          int    `json:"id"`
+	SupplierID  int    `json:"supplier_id"`
+	WarehouseID int    `json:"warehouse_id"`
+	Status      string `json:"status"`
+	Reference   string `json:"reference"`
+	UserID      *int   `json:"user_id"`
+	// ExpectedAt is set from supplier lead time when order is sent
+	ExpectedAt *time.Time          `json:"expected_at"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+	Lines      []PurchaseOrderLine `json:"lines,omitempty"`
+}
+
+// PurchaseOrderLine quantities are in item base unit
+type PurchaseOrderLine struct {
+	ID               int     `json:"id"`
+	PurchaseOrderID  int     `json:"purchase_order_id"`
+	ItemID           int     `json:"item_id"`
+	Quantity         float64 `json:"quantity"`
+	ReceivedQuantity float64 `json:"received_quantity"`
+	Price            float64 `json:"price"`
+}
+
+// LowStockOrders are draft orders made from low stock list,
+// Unsourced is low stock no supplier has in its price list
+type LowStockOrders struct {
+	Orders    []PurchaseOrder `json:"orders"`
+	Unsourced []LowStockItem  `json:"unsourced"`
+}
+
+// Receipt is delivery received against purchase order
+type Receipt struct {
+	ID              int       `json:"id"`
+	PurchaseOrderID int       `json:"purchase_order_id"`
+	WarehouseID     int       `json:"warehouse_id"`
+	LocationID      *int      `json:"location_id"`
+	Reference       string    `json:"reference"`
+	UserID          *int      `json:"user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	// PurchaseOrderStatus is status of the order right after receipt
+	PurchaseOrderStatus string        `json:"purchase_order_status,omitempty"`
+	Lines               []ReceiptLine `json:"lines,omitempty"`
+}
+
+// ReceiptLine is delivered quantity of order line in base unit, only
+// Quantity is put into stock while DamagedQuantity is noted and rejected
+type ReceiptLine struct {
+	ID                  int     `json:"id"`
+	ReceiptID           int     `json:"receipt_id"`
+	PurchaseOrderLineID int     `json:"line_id"`
+	ItemID              int     `json:"item_id"`
+	Quantity            float64 `json:"quantity"`
+	DamagedQuantity     float64 `json:"damaged_quantity"`
+	Note                string  `json:"note"`
+	LotID               *int    `json:"lot_id"`
+	MovementID          *int64  `json:"movement_id"`
+}
+
+const (
+	OutboundOrderStatusNew       = "new"
+	OutboundOrderStatusPicking   = "picking"
+	OutboundOrderStatusPacked    = "packed"
+	OutboundOrderStatusShipped   = "shipped"
+	OutboundOrderStatusCancelled = "cancelled"
+)
+
+// OutboundOrder is shipment to customer, its lines hold stock
+// like reservations until it is shipped or cancelled
+type OutboundOrder struct {
+	ID             int        `json:"id"`
+	WarehouseID    int        `json:"warehouse_id"`
+	Customer       string     `json:"customer"`
+	Reference      string     `json:"reference"`
+	Status         string     `json:"status"`
+	UserID         *int       `json:"user_id"`
+	PackageCount   *int       `json:"package_count"`
+	Carrier        string     `json:"carrier"`
+	TrackingNumber string     `json:"tracking_number"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	ShippedAt      *time.Time `json:"shipped_at"`
+	// Picks are pick list of the order in route order
+	Lines []OutboundOrderLine `json:"lines,omitempty"`
+	Picks []Pick              `json:"picks,omitempty"`
+}
+
+// OutboundOrderLine quantity is in item base unit
+type OutboundOrderLine struct {
+	ID       int     `json:"id"`
+	OrderID  int     `json:"order_id"`
+	ItemID   int     `json:"item_id"`
+	Quantity float64 `json:"quantity"`
+}
+
+// Pick is a step of pick list: take Quantity of item either from a
+// location or from a lot, lot stock is not kept per location so lot
+// entries get location when packed, other entries without location
+// take stock not put away
+type Pick struct {
+	ID         int      `json:"id"`
+	OrderID    int      `json:"order_id"`
+	LineID     int      `json:"line_id"`
+	Sequence   int      `json:"sequence"`
+	ItemID     int      `json:"item_id"`
+	LocationID *int     `json:"location_id"`
+	Path       string   `json:"path"`
+	LotID      *int     `json:"lot_id"`
+	LotNumber  *string  `json:"lot_number"`
+	Quantity   float64  `json:"quantity"`
+	Serials    []string `json:"serials,omitempty"`
+	MovementID *int64   `json:"movement_id"`
+}
+
+const (
+	TransferOrderStatusDraft     = "draft"
+	TransferOrderStatusInTransit = "in_transit"
+	TransferOrderStatusReceived  = "received"
+	TransferOrderStatusCancelled = "cancelled"
+)
+
+// TransferOrder moves stock between warehouses, dispatched
+// quantity leaves source at once and is in transit until received
+type TransferOrder struct {
+	ID                     int                 `json:"id"`
+	SourceWarehouseID      int                 `json:"source_warehouse_id"`
+	DestinationWarehouseID int                 `json:"destination_warehouse_id"`
+	Reference              string              `json:"reference"`
+	Status                 string              `json:"status"`
+	UserID                 *int                `json:"user_id"`
+	CreatedAt              time.Time           `json:"created_at"`
+	UpdatedAt              time.Time           `json:"updated_at"`
+	DispatchedAt           *time.Time          `json:"dispatched_at"`
+	ReceivedAt             *time.Time          `json:"received_at"`
+	Lines                  []TransferOrderLine `json:"lines,omitempty"`
+}
+
+// TransferOrderLine quantities are in item base unit, Discrepancy
+// is dispatched quantity that did not arrive
+type TransferOrderLine struct {
+	ID                 int      `json:"id"`
+	OrderID            int      `json:"order_id"`
+	ItemID             int      `json:"item_id"`
+	LotID              *int     `json:"lot_id"`
+	Quantity           float64  `json:"quantity"`
+	ReceivedQuantity   *float64 `json:"received_quantity"`
+	Discrepancy        float64  `json:"discrepancy"`
+	Serials            []string `json:"serials,omitempty"`
+	Note               string   `json:"note"`
+	DispatchMovementID *int64   `json:"dispatch_movement_id"`
+	ReceiptMovementID  *int64   `json:"receipt_movement_id"`
+}
+
+const (
+	StocktakeStatusOpen      = "open"
+	StocktakeStatusApproved  = "approved"
+	StocktakeStatusCancelled = "cancelled"
+)
+
+// Stocktake is a physical count of warehouse, location subtree or
+// category, expected quantities are frozen when it is started
+type Stocktake struct {
+	ID          int             `json:"id"`
+	WarehouseID int             `json:"warehouse_id"`
+	LocationID  *int            `json:"location_id"`
+	CategoryID  *int            `json:"category_id"`
+	Note        string          `json:"note"`
+	Status      string          `json:"status"`
+	UserID      *int            `json:"user_id"`
+	ApprovedBy  *int            `json:"approved_by"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	ApprovedAt  *time.Time      `json:"approved_at"`
+	Lines       []StocktakeLine `json:"lines,omitempty"`
+}
+
+// StocktakeLine is item at a location to count, Variance is
+// counted minus expected and is nil until the line is counted
+type StocktakeLine struct {
+	ID               int        `json:"id"`
+	StocktakeID      int        `json:"stocktake_id"`
+	ItemID           int        `json:"item_id"`
+	ItemName         string     `json:"item_name"`
+	LocationID       *int       `json:"location_id"`
+	Path             string     `json:"path"`
+	ExpectedQuantity float64    `json:"expected_quantity"`
+	CountedQuantity  *float64   `json:"counted_quantity"`
+	Variance         *float64   `json:"variance"`
+	CountedBy        *int       `json:"counted_by"`
+	CountedAt        *time.Time `json:"counted_at"`
+	MovementID       *int64     `json:"movement_id"`
+}
+
+// KitComponent is quantity of component item in base unit that goes
+// into one kit, Kits is how many kits its available stock is enough for
+type KitComponent struct {
+	ComponentID int     `json:"component_id"`
+	Name        string  `json:"name"`
+	Unit        string  `json:"unit"`
+	Quantity    float64 `json:"quantity"`
+	Available   float64 `json:"available"`
+	Kits        float64 `json:"kits"`
+}
+
+// Kit is item assembled from components by its bill of materials,
+// Available is the number of kits that can be assembled in warehouse
+type Kit struct {
+	ItemID      int            `json:"item_id"`
+	WarehouseID int            `json:"warehouse_id"`
+	Components  []KitComponent `json:"components"`
+	Available   float64        `json:"available"`
+}
+
+const (
+	ChangeRequestKindUpdate = "update"
+	ChangeRequestKindDelete = "delete"
+	// ChangeRequestKindAdjust is relative stock change, it is applied
+	// to stock of the moment it is approved
+	ChangeRequestKindAdjust = "adjust"
+)
+
+const (
+	ChangeRequestStatusPending  = "pending"
+	ChangeRequestStatusApproved = "approved"
+	ChangeRequestStatusRejected = "rejected"
+)
+
+// ChangeRequest is item change made by manager that broke approval
+// rules, item stays untouched until admin approves it. Reasons name
+// the rules that were broken
+type ChangeRequest struct {
+	ID         int             `json:"id"`
+	ItemID     int             `json:"item_id"`
+	Kind       string          `json:"kind"`
+	Changes    json.RawMessage `json:"changes"`
+	Original   json.RawMessage `json:"original"`
+	Version    int             `json:"version"`
+	Reasons    []string        `json:"reasons"`
+	Status     string          `json:"status"`
+	UserID     *int            `json:"user_id"`
+	ReviewedBy *int            `json:"reviewed_by"`
+	Comment    string          `json:"comment"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ReviewedAt *time.Time      `json:"reviewed_at"`
+}
+
+// IdempotencyRecord is stored outcome of request made with Idempotency-Key,
+// StatusCode is nil while the first request is still being handled
+type IdempotencyRecord struct {
+	Fingerprint string
+	StatusCode  *int
+	ContentType string
+	Body        []byte
+}
+
+const (
+	ValuationMethodFIFO    = "fifo"
+	ValuationMethodAverage = "average"
+)
+
+// ItemValuation is on-hand quantity of item in warehouse and its value
+type ItemValuation struct {
+	ItemID        int     `json:"item_id"`
+	Name          string  `json:"name"`
+	CategoryID    *int    `json:"category_id"`
+	CategoryName  string  `json:"category_name"`
+	WarehouseID   int     `json:"warehouse_id"`
+	WarehouseName string  `json:"warehouse_name"`
+	Quantity      float64 `json:"quantity"`
+	Value         float64 `json:"value"`
+	UnitCost      float64 `json:"unit_cost"`
+}
+
+// GroupValuation is total value of category or warehouse,
+// ID of category group is null for items without category
+type GroupValuation struct {
+	ID    *int    `json:"id"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// Valuation is value of stock on hand at the end of AsOf
+// by valuation method, built from valued stock ledger
+type Valuation struct {
+	AsOf       time.Time        `json:"as_of"`
+	Method     string           `json:"method"`
+	Total      float64          `json:"total"`
+	Items      []ItemValuation  `json:"items"`
+	Categories []GroupValuation `json:"categories"`
+	Warehouses []GroupValuation `json:"warehouses"`
+}
+
+const (
+	AttachmentKindImage    = "image"
+	AttachmentKindDocument = "document"
+)
+
+// Attachment is image or document of item kept in blob storage,
+// HasThumbnail is set for images a thumbnail was made for
+type Attachment struct {
+	ID           int       `json:"id"`
+	ItemID       int       `json:"item_id"`
+	Kind         string    `json:"kind"`
+	FileName     string    `json:"file_name"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	HasThumbnail bool      `json:"has_thumbnail"`
+	UserID       *int      `json:"user_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	StorageKey   string    `json:"-"`
+	ThumbnailKey *string   `json:"-"`
+}
+
+// TagCount is tag in use with number of items labeled with it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Items int    `json:"items"`
+}
+
+// SavedView is item list filter saved by user under a name,
+// Filter has the shape of dto.ViewFilter
+type SavedView struct {
+	ID        int             `json:"id"`
+	UserID    int             `json:"user_id"`
+	Name      string          `json:"name"`
+	Filter    json.RawMessage `json:"filter"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+const (
+	NameScopeNone      = "none"
+	NameScopeGlobal    = "global"
+	NameScopeCategory  = "category"
+	NameScopeWarehouse = "warehouse"
+)
+
+// DuplicatePair is two active items with similar names,
+// Similarity is trigram similarity of normalized names
+type DuplicatePair struct {
+	ItemID        int     `json:"item_id"`
+	ItemName      string  `json:"item_name"`
+	DuplicateID   int     `json:"duplicate_id"`
+	DuplicateName string  `json:"duplicate_name"`
+	Similarity    float64 `json:"similarity"`
+}
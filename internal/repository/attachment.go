@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+const attachmentColumns = `id, item_id, kind, file_name, content_type, size,
+	storage_key, thumbnail_key, user_id, created_at`
+
+func scanAttachment(row rowScanner) (model.Attachment, error) {
+	var attachment model.Attachment
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.ItemID,
+		&attachment.Kind,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.StorageKey,
+		&attachment.ThumbnailKey,
+		&attachment.UserID,
+		&attachment.CreatedAt,
+	)
+	attachment.HasThumbnail = attachment.ThumbnailKey != nil
+	return attachment, err
+}
+
+func (r *Repository) CreateAttachment(ctx context.Context, createAttachment dto.CreateAttachment) (*model.Attachment, error) {
+	query := `INSERT INTO item_attachments(item_id, kind, file_name, content_type, size, storage_key, thumbnail_key, user_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING ` + attachmentColumns
+
+	attachment, err := scanAttachment(r.db.Master.QueryRowContext(
+		ctx,
+		query,
+		createAttachment.ItemID,
+		createAttachment.Kind,
+		createAttachment.FileName,
+		createAttachment.ContentType,
+		createAttachment.Size,
+		createAttachment.StorageKey,
+		createAttachment.ThumbnailKey,
+		createAttachment.UserID,
+	))
+	if err != nil {
+		return nil, mapItemForeignKeyError(err, "could not create attachment in db: %w")
+	}
+
+	return &attachment, nil
+}
+
+func (r *Repository) GetAttachments(ctx context.Context, itemID int) ([]model.Attachment, error) {
+	query := "SELECT " + attachmentColumns + " FROM item_attachments WHERE item_id = $1 ORDER BY id"
+
+	rows, err := r.db.Master.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get attachments from db: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []model.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, nil
+}
+
+func (r *Repository) GetAttachmentByID(ctx context.Context, id int) (*model.Attachment, error) {
+	query := "SELECT " + attachmentColumns + " FROM item_attachments WHERE id = $1"
+
+	attachment, err := scanAttachment(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchAttachment
+		}
+		return nil, fmt.Errorf("could not get attachment from db: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// DeleteAttachment removes attachment record and returns it
+// so that its files can be removed from blob storage
+func (r *Repository) DeleteAttachment(ctx context.Context, id int) (*model.Attachment, error) {
+	query := "DELETE FROM item_attachments WHERE id = $1 RETURNING " + attachmentColumns
+
+	attachment, err := scanAttachment(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchAttachment
+		}
+		return nil, fmt.Errorf("could not delete attachment: %w", err)
+	}
+
+	return &attachment, nil
+}
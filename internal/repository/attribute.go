@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+
+	"github.com/lib/pq"
+)
+
+func (r *Repository) CreateAttributeDefinition(ctx context.Context, createDefinition dto.CreateAttributeDefinition) (*model.AttributeDefinition, error) {
+	query := `INSERT INTO attribute_definitions(category_id, name, type, required, allowed_values)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, created_at`
+
+	if createDefinition.AllowedValues == nil {
+		createDefinition.AllowedValues = []string{}
+	}
+
+	var definition model.AttributeDefinition
+	err := r.db.Master.QueryRowContext(
+		ctx,
+		query,
+		createDefinition.CategoryID,
+		createDefinition.Name,
+		createDefinition.Type,
+		createDefinition.Required,
+		pq.Array(createDefinition.AllowedValues),
+	).Scan(&definition.ID, &definition.CreatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrNoSuchCategory
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrAttributeExists
+		}
+		return nil, fmt.Errorf("could not create attribute definition: %w", err)
+	}
+
+	definition.CategoryID = createDefinition.CategoryID
+	definition.Name = createDefinition.Name
+	definition.Type = createDefinition.Type
+	definition.Required = createDefinition.Required
+	definition.AllowedValues = createDefinition.AllowedValues
+
+	return &definition, nil
+}
+
+// GetAttributeDefinitions returns definitions of the category
+// together with the ones inherited from all of its ancestors
+func (r *Repository) GetAttributeDefinitions(ctx context.Context, categoryID int) ([]model.AttributeDefinition, error) {
+	query := `WITH RECURSIVE ancestors AS (
+		SELECT id, parent_id FROM categories WHERE id = $1
+		UNION ALL
+		SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+	)
+	SELECT d.id, d.category_id, d.name, d.type, d.required, d.allowed_values, d.created_at
+	FROM attribute_definitions d
+	JOIN ancestors a ON a.id = d.category_id
+	ORDER BY d.name`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get attribute definitions from db: %w", err)
+	}
+	defer rows.Close()
+
+	var definitions []model.AttributeDefinition
+	for rows.Next() {
+		var definition model.AttributeDefinition
+		err := rows.Scan(
+			&definition.ID,
+			&definition.CategoryID,
+			&definition.Name,
+			&definition.Type,
+			&definition.Required,
+			pq.Array(&definition.AllowedValues),
+			&definition.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
+
+func (r *Repository) DeleteAttributeDefinition(ctx context.Context, categoryID, id int) error {
+	query := "DELETE FROM attribute_definitions WHERE id = $1 AND category_id = $2"
+
+	result, err := r.db.Master.ExecContext(ctx, query, id, categoryID)
+	if err != nil {
+		return fmt.Errorf("could not delete attribute definition by id: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchAttribute
+	}
+
+	return nil
+}
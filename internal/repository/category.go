@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (r *Repository) CreateCategory(ctx context.Context, createCategory dto.CreateCategory) (*model.Category, error) {
+	query := `INSERT INTO categories(name, parent_id) VALUES ($1, $2)
+	RETURNING id, created_at`
+
+	var category model.Category
+	err := r.db.Master.QueryRowContext(
+		ctx,
+		query,
+		createCategory.Name,
+		createCategory.ParentID,
+	).Scan(&category.ID, &category.CreatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrNoSuchCategory
+		}
+		return nil, fmt.Errorf("could not create category: %w", err)
+	}
+
+	category.Name = createCategory.Name
+	category.ParentID = createCategory.ParentID
+
+	return &category, nil
+}
+
+func (r *Repository) GetCategories(ctx context.Context) ([]model.Category, error) {
+	query := "SELECT id, name, parent_id, created_at FROM categories ORDER BY name"
+
+	rows, err := r.db.Master.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not get categories from db: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []model.Category
+	for rows.Next() {
+		var category model.Category
+		err := rows.Scan(
+			&category.ID,
+			&category.Name,
+			&category.ParentID,
+			&category.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
+
+func (r *Repository) GetCategoryByID(ctx context.Context, id int) (*model.Category, error) {
+	query := "SELECT id, name, parent_id, created_at FROM categories WHERE id = $1"
+
+	var category model.Category
+	err := r.db.Master.QueryRowContext(ctx, query, id).Scan(
+		&category.ID,
+		&category.Name,
+		&category.ParentID,
+		&category.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchCategory
+		}
+		return nil, fmt.Errorf("could not get category from db: %w", err)
+	}
+
+	return &category, nil
+}
+
+func (r *Repository) UpdateCategory(ctx context.Context, updateCategory *dto.UpdateCategory) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// moving a category under one of its own descendants would
+	// detach the whole subtree from the root, so check it first
+	if updateCategory.ParentID != nil && *updateCategory.ParentID != 0 {
+		query := `WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = $1
+			UNION ALL
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)`
+
+		var isDescendant bool
+		err := tx.QueryRowContext(ctx, query, updateCategory.ID, *updateCategory.ParentID).Scan(&isDescendant)
+		if err != nil {
+			return fmt.Errorf("could not check category tree: %w", err)
+		}
+		if isDescendant {
+			return ErrCategoryCycle
+		}
+	}
+
+	query := `UPDATE categories SET
+		name = COALESCE($1, name),
+		parent_id = CASE
+			WHEN $2::INT IS NULL THEN parent_id
+			WHEN $2::INT = 0 THEN NULL
+			ELSE $2::INT
+		END
+		WHERE id = $3;`
+	result, err := tx.ExecContext(
+		ctx,
+		query,
+		updateCategory.Name,
+		updateCategory.ParentID,
+		updateCategory.ID,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrNoSuchCategory
+		}
+		return fmt.Errorf("could not update category in db: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchCategory
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteCategory(ctx context.Context, id int) error {
+	query := "DELETE FROM categories WHERE id = $1"
+
+	result, err := r.db.Master.ExecContext(ctx, query, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrCategoryHasChildren
+		}
+		return fmt.Errorf("could not delete category by id: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchCategory
+	}
+
+	return nil
+}
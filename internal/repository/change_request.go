@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+
+	"github.com/lib/pq"
+)
+
+const changeRequestColumns = `id, item_id, kind, changes, original, version, reasons, status, user_id,
+	reviewed_by, comment, created_at, reviewed_at`
+
+func scanChangeRequest(row rowScanner) (model.ChangeRequest, error) {
+	var changeRequest model.ChangeRequest
+	var changes, original []byte
+	err := row.Scan(
+		&changeRequest.ID,
+		&changeRequest.ItemID,
+		&changeRequest.Kind,
+		&changes,
+		&original,
+		&changeRequest.Version,
+		pq.Array(&changeRequest.Reasons),
+		&changeRequest.Status,
+		&changeRequest.UserID,
+		&changeRequest.ReviewedBy,
+		&changeRequest.Comment,
+		&changeRequest.CreatedAt,
+		&changeRequest.ReviewedAt,
+	)
+	changeRequest.Changes = changes
+	changeRequest.Original = original
+	return changeRequest, err
+}
+
+func (r *Repository) CreateChangeRequest(ctx context.Context, createRequest dto.CreateChangeRequest) (*model.ChangeRequest, error) {
+	original := createRequest.Original
+	if original == nil {
+		original = []byte("{}")
+	}
+
+	query := `INSERT INTO change_requests(item_id, kind, changes, original, version, reasons, user_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING ` + changeRequestColumns
+
+	changeRequest, err := scanChangeRequest(r.db.Master.QueryRowContext(
+		ctx,
+		query,
+		createRequest.ItemID,
+		createRequest.Kind,
+		createRequest.Changes,
+		original,
+		createRequest.Version,
+		pq.Array(createRequest.Reasons),
+		createRequest.UserID,
+	))
+	if err != nil {
+		return nil, mapItemForeignKeyError(err, "could not create change request in db: %w")
+	}
+
+	return &changeRequest, nil
+}
+
+func (r *Repository) GetChangeRequests(ctx context.Context, filter dto.ChangeRequestFilter) ([]model.ChangeRequest, error) {
+	query := "SELECT " + changeRequestColumns + " FROM change_requests"
+
+	var args []any
+	var conditions []string
+	if filter.ItemID != nil {
+		args = append(args, *filter.ItemID)
+		conditions = append(conditions, fmt.Sprintf("item_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Master.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not get change requests from db: %w", err)
+	}
+	defer rows.Close()
+
+	var changeRequests []model.ChangeRequest
+	for rows.Next() {
+		changeRequest, err := scanChangeRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		changeRequests = append(changeRequests, changeRequest)
+	}
+
+	return changeRequests, nil
+}
+
+func (r *Repository) GetChangeRequestByID(ctx context.Context, id int) (*model.ChangeRequest, error) {
+	query := "SELECT " + changeRequestColumns + " FROM change_requests WHERE id = $1"
+
+	changeRequest, err := scanChangeRequest(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchChangeRequest
+		}
+		return nil, fmt.Errorf("could not get change request from db: %w", err)
+	}
+
+	return &changeRequest, nil
+}
+
+// ReviewChangeRequest closes pending change request with status,
+// request reviewed by someone else in the meantime is not touched
+func (r *Repository) ReviewChangeRequest(ctx context.Context, review dto.ReviewChangeRequest, status string) error {
+	query := `UPDATE change_requests SET status = $2, reviewed_by = $3, comment = $4, reviewed_at = NOW()
+	WHERE id = $1 AND status = 'pending'`
+
+	result, err := r.db.Master.ExecContext(ctx, query, review.ID, status, review.UserID, review.Comment)
+	if err != nil {
+		return mapItemForeignKeyError(err, "could not review change request: %w")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get affected rows: %w", err)
+	}
+	if rows == 0 {
+		if _, err := r.GetChangeRequestByID(ctx, review.ID); err != nil {
+			return err
+		}
+		return ErrChangeRequestStatus
+	}
+
+	return nil
+}
+
+// ApproveChangeRequest applies change of pending request and closes it
+// in one transaction, request row stays locked meanwhile so concurrent
+// review of the same request waits and then finds it closed
+func (r *Repository) ApproveChangeRequest(ctx context.Context, approve dto.ApproveChangeRequest) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	query := "SELECT status FROM change_requests WHERE id = $1 FOR UPDATE"
+	if err := tx.QueryRowContext(ctx, query, approve.Review.ID).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoSuchChangeRequest
+		}
+		return fmt.Errorf("could not lock change request: %w", err)
+	}
+	if status != model.ChangeRequestStatusPending {
+		return ErrChangeRequestStatus
+	}
+
+	switch {
+	case approve.UpdateItem != nil:
+		if err := checkItemOriginal(ctx, tx, approve.UpdateItem, approve.Original); err != nil {
+			return err
+		}
+		err = applyItemUpdate(ctx, tx, approve.UpdateItem)
+	case approve.AdjustItem != nil:
+		_, err = applyItemAdjustment(ctx, tx, approve.AdjustItem)
+	case approve.DeleteItem != nil:
+		err = deleteItem(ctx, tx, approve.DeleteItem.ID, approve.DeleteItem.Version, approve.DeleteItem.UserID)
+	}
+	if err != nil {
+		return err
+	}
+
+	query = `UPDATE change_requests SET status = $2, reviewed_by = $3, comment = $4, reviewed_at = NOW()
+	WHERE id = $1`
+
+	_, err = tx.ExecContext(ctx, query, approve.Review.ID, model.ChangeRequestStatusApproved, approve.Review.UserID, approve.Review.Comment)
+	if err != nil {
+		return mapItemForeignKeyError(err, "could not review change request: %w")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// checkItemOriginal makes sure fields changed by update still hold values
+// they had when request was made, item row stays locked until tx ends
+// so nothing changes them before update is applied
+func checkItemOriginal(ctx context.Context, tx *sql.Tx, updateItem *dto.UpdateItem, original *dto.ItemOriginal) error {
+	if original == nil {
+		original = &dto.ItemOriginal{}
+	}
+
+	var name string
+	var categoryID *int
+	var attributes []byte
+	var serialized, archived bool
+	query := "SELECT name, category_id, attributes, serialized, deleted_at IS NOT NULL FROM items WHERE id = $1 FOR UPDATE"
+	err := tx.QueryRowContext(ctx, query, updateItem.ID).Scan(&name, &categoryID, &attributes, &serialized, &archived)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoSuchItem
+		}
+		return fmt.Errorf("could not lock item: %w", err)
+	}
+	if archived {
+		return ErrItemArchived
+	}
+
+	var changed []string
+	if updateItem.Name != nil && original.Name != nil && name != *original.Name {
+		changed = append(changed, "name")
+	}
+	if updateItem.CategoryID != nil && !equalIntPointers(categoryID, original.CategoryID) {
+		changed = append(changed, "category")
+	}
+	if updateItem.Attributes != nil {
+		var current map[string]any
+		if err := json.Unmarshal(attributes, &current); err != nil {
+			return fmt.Errorf("could not unmarshal item attributes: %w", err)
+		}
+		if (len(current) != 0 || len(original.Attributes) != 0) && !reflect.DeepEqual(current, original.Attributes) {
+			changed = append(changed, "attributes")
+		}
+	}
+	if updateItem.Serialized != nil && original.Serialized != nil && serialized != *original.Serialized {
+		changed = append(changed, "serial tracking")
+	}
+	if updateItem.Count != nil && original.Quantity != nil {
+		current, err := currentItemStock(ctx, tx, updateItem)
+		if err != nil {
+			return err
+		}
+		if current != *original.Quantity {
+			changed = append(changed, "quantity")
+		}
+	}
+
+	if len(changed) > 0 {
+		return fmt.Errorf("%w: %s changed after request was made", ErrVersionConflict, strings.Join(changed, ", "))
+	}
+
+	return nil
+}
+
+func equalIntPointers(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -2,28 +2,117 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"wharehouse-control/internal/dto"
 	"wharehouse-control/internal/model"
 )
 
 func (r *Repository) CreateItem(ctx context.Context, createItem dto.CreateItem) (*model.Item, error) {
-	query := `INSERT INTO items(name, count) VALUES ($1, $2)
-	RETURNING id, created_at`
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO items(name, count, unit, category_id, attributes, serialized) VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, version, created_at`
+
+	if createItem.Attributes == nil {
+		createItem.Attributes = map[string]any{}
+	}
+	attributes, err := json.Marshal(createItem.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal item attributes: %w", err)
+	}
 
 	var item model.Item
-	err := r.db.Master.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		createItem.Name,
 		createItem.Count,
-	).Scan(&item.ID, &item.CreatedAt)
+		createItem.Unit,
+		createItem.CategoryID,
+		attributes,
+		createItem.Serialized,
+	).Scan(&item.ID, &item.Version, &item.CreatedAt)
 	if err != nil {
-		return nil, fmt.Errorf("could not create user: %w", err)
+		return nil, mapItemForeignKeyError(err, "could not create item: %w")
+	}
+
+	query = "INSERT INTO item_units(item_id, unit, factor) VALUES ($1, $2, $3)"
+	for _, unit := range createItem.Units {
+		_, err := tx.ExecContext(ctx, query, item.ID, unit.Unit, unit.Factor)
+		if err != nil {
+			return nil, mapItemForeignKeyError(err, "could not create item unit: %w")
+		}
+	}
+
+	query = "INSERT INTO stock(item_id, warehouse_id, quantity) VALUES ($1, $2, $3)"
+	_, err = tx.ExecContext(ctx, query, item.ID, createItem.WarehouseID, createItem.Count)
+	if err != nil {
+		return nil, mapItemForeignKeyError(err, "could not create item stock: %w")
+	}
+
+	if err := checkItemName(ctx, tx, item.ID, createItem.NameScope); err != nil {
+		return nil, err
+	}
+
+	if createItem.LocationID != nil {
+		if err := checkLocationWarehouse(ctx, tx, *createItem.LocationID, createItem.WarehouseID); err != nil {
+			return nil, err
+		}
+
+		query = "INSERT INTO location_stock(item_id, location_id, quantity) VALUES ($1, $2, $3)"
+		_, err := tx.ExecContext(ctx, query, item.ID, *createItem.LocationID, createItem.Count)
+		if err != nil {
+			return nil, fmt.Errorf("could not create item location stock: %w", err)
+		}
+	}
+
+	// initial quantity is already in place, only the ledger entry is missing
+	movement := &model.Movement{
+		ItemID:      item.ID,
+		WarehouseID: createItem.WarehouseID,
+		LocationID:  createItem.LocationID,
+		Quantity:    createItem.Count,
+		Reason:      model.MovementReasonInitial,
+		Serials:     createItem.Serials,
+		UnitCost:    createItem.UnitCost,
+	}
+	if createItem.UserID != 0 {
+		movement.UserID = &createItem.UserID
+	}
+
+	serialIDs, err := moveSerials(ctx, tx, movement)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertMovement(ctx, tx, movement); err != nil {
+		return nil, err
+	}
+
+	if err := costMovement(ctx, tx, movement, 0); err != nil {
+		return nil, err
+	}
+
+	if err := linkSerials(ctx, tx, movement.ID, serialIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
 	}
 
 	item.Name = createItem.Name
 	item.Count = createItem.Count
+	item.Unit = createItem.Unit
+	item.CategoryID = createItem.CategoryID
+	item.Attributes = createItem.Attributes
+	item.Serialized = createItem.Serialized
+	item.Stock = []model.StockLevel{{WarehouseID: createItem.WarehouseID, Quantity: createItem.Count, Available: createItem.Count}}
 
 	return &item, nil
 }
@@ -2,16 +2,214 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"wharehouse-control/internal/model"
+
+	"github.com/lib/pq"
 )
 
-func (r *Repository) DeleteItem(ctx context.Context, id int) error {
-	query := "DELETE FROM items WHERE id = $1"
+// DeleteItem archives item, its stock, movements and history are kept
+// and it can be brought back by RestoreItem
+func (r *Repository) DeleteItem(ctx context.Context, id, version int, userID *int) error {
+	return deleteItem(ctx, r.db.Master, id, &version, userID)
+}
+
+type execQueryRower interface {
+	queryRower
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// deleteItem archives item through db or within tx, nil version
+// archives item whatever its version is
+func deleteItem(ctx context.Context, q execQueryRower, id int, version *int, userID *int) error {
+	query := `UPDATE items SET deleted_at = NOW(), deleted_by = $3
+	WHERE id = $1 AND ($2::INT IS NULL OR version = $2) AND deleted_at IS NULL`
+
+	result, err := q.ExecContext(ctx, query, id, version, userID)
+	if err != nil {
+		return mapItemForeignKeyError(err, "could not delete item by id: %w")
+	}
 
-	_, err := r.db.Master.ExecContext(ctx, query, id)
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("could not delete item by id: %w", err)
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return itemVersionError(ctx, q, id)
 	}
 
 	return nil
 }
+
+// RestoreItem brings archived item back and returns its new version,
+// item must not clash by name with active items in nameScope
+func (r *Repository) RestoreItem(ctx context.Context, id int, nameScope string) (int, error) {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE items SET deleted_at = NULL, deleted_by = NULL
+	WHERE id = $1 AND deleted_at IS NOT NULL AND merged_into IS NULL
+	RETURNING version`
+
+	var version int
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, archivedItemError(ctx, tx, id)
+		}
+		return 0, fmt.Errorf("could not restore item: %w", err)
+	}
+
+	if err := checkItemName(ctx, tx, id, nameScope); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return version, nil
+}
+
+// purgeBlockers are records that must outlive the item, purge would
+// otherwise cascade into lines of business documents or lose stock
+var purgeBlockers = []struct {
+	query  string
+	reason string
+}{
+	{
+		query:  "SELECT EXISTS(SELECT 1 FROM kit_components WHERE kit_id = $1 OR component_id = $1)",
+		reason: "item is a kit or a component of kit",
+	},
+	{
+		query:  "SELECT EXISTS(SELECT 1 FROM stock WHERE item_id = $1 AND quantity <> 0)",
+		reason: "item has stock left",
+	},
+	{
+		query:  "SELECT EXISTS(SELECT 1 FROM reservations WHERE item_id = $1)",
+		reason: "item has reservations",
+	},
+	{
+		query:  "SELECT EXISTS(SELECT 1 FROM purchase_order_lines WHERE item_id = $1)",
+		reason: "item is ordered in purchase orders",
+	},
+	{
+		query:  "SELECT EXISTS(SELECT 1 FROM receipt_lines WHERE item_id = $1)",
+		reason: "item is received in receipts",
+	},
+	{
+		query:  "SELECT EXISTS(SELECT 1 FROM outbound_order_lines WHERE item_id = $1)",
+		reason: "item is in outbound orders",
+	},
+	{
+		query:  "SELECT EXISTS(SELECT 1 FROM transfer_order_lines WHERE item_id = $1)",
+		reason: "item is in transfer orders",
+	},
+	{
+		query:  "SELECT EXISTS(SELECT 1 FROM stocktake_lines WHERE item_id = $1)",
+		reason: "item is counted in stocktakes",
+	},
+}
+
+// PurgeItem removes archived item for good together with its own
+// records like movements and history, item referenced by documents
+// is refused. Attachments of item are returned so that their files
+// can be removed after commit
+func (r *Repository) PurgeItem(ctx context.Context, id int) ([]model.Attachment, error) {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var archived bool
+	query := "SELECT deleted_at IS NOT NULL FROM items WHERE id = $1 FOR UPDATE"
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&archived); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchItem
+		}
+		return nil, fmt.Errorf("could not lock item: %w", err)
+	}
+	if !archived {
+		return nil, archivedItemError(ctx, tx, id)
+	}
+
+	for _, blocker := range purgeBlockers {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, blocker.query, id).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("could not check item references: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("%w: %s", ErrItemInUse, blocker.reason)
+		}
+	}
+
+	query = "SELECT " + attachmentColumns + " FROM item_attachments WHERE item_id = $1 FOR UPDATE"
+
+	rows, err := tx.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not get attachments from db: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []model.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		attachments = append(attachments, attachment)
+	}
+	rows.Close()
+
+	query = "DELETE FROM items WHERE id = $1 AND deleted_at IS NOT NULL"
+
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return nil, fmt.Errorf("%w: item is referenced by %s", ErrItemInUse, pqErr.Table)
+		}
+		return nil, fmt.Errorf("could not purge item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, archivedItemError(ctx, tx, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// archivedItemError tells why write limited to archived item
+// affected no rows: item is gone, it is not archived or it is
+// a leftover of merge that cannot come back
+func archivedItemError(ctx context.Context, q queryRower, id int) error {
+	var merged bool
+	query := "SELECT merged_into IS NOT NULL FROM items WHERE id = $1"
+	if err := q.QueryRowContext(ctx, query, id).Scan(&merged); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoSuchItem
+		}
+		return fmt.Errorf("could not check item existence in db: %w", err)
+	}
+
+	if merged {
+		return ErrItemMerged
+	}
+	return ErrItemNotArchived
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"wharehouse-control/internal/dto"
 	"wharehouse-control/internal/model"
+
+	"github.com/lib/pq"
 )
 
 func (r *Repository) GetUsersWithChanges(ctx context.Context) ([]model.UserHistory, error) {
@@ -59,23 +64,116 @@ func (r *Repository) GetUsersWithChanges(ctx context.Context) ([]model.UserHisto
 	return history, nil
 }
 
-func (r *Repository) GetAllItems(ctx context.Context) ([]model.Item, error) {
-	query := "SELECT * FROM items"
+const itemColumns = `id, name, count, unit, category_id, attributes,
+	COALESCE((
+		SELECT JSON_AGG(JSON_BUILD_OBJECT(
+			'warehouse_id', s.warehouse_id,
+			'warehouse_name', w.name,
+			'quantity', s.quantity,
+			'reserved', r.reserved,
+			'available', s.quantity - r.reserved,
+			'in_transit', t.in_transit
+		) ORDER BY s.warehouse_id)
+		FROM stock s JOIN warehouses w ON w.id = s.warehouse_id
+		CROSS JOIN LATERAL (` + reservedQuantity + `) r
+		CROSS JOIN LATERAL (` + inTransitQuantity + `) t
+		WHERE s.item_id = items.id
+	), '[]') AS stock,
+	ARRAY(SELECT tag FROM item_tags t WHERE t.item_id = items.id ORDER BY tag) AS tags,
+	serialized, version, created_at, deleted_at, deleted_by, merged_into`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
-	rows, err := r.db.Master.QueryContext(ctx, query)
+func scanItem(row rowScanner) (model.Item, error) {
+	var item model.Item
+	var attributes, stock []byte
+	err := row.Scan(
+		&item.ID,
+		&item.Name,
+		&item.Count,
+		&item.Unit,
+		&item.CategoryID,
+		&attributes,
+		&stock,
+		pq.Array(&item.Tags),
+		&item.Serialized,
+		&item.Version,
+		&item.CreatedAt,
+		&item.DeletedAt,
+		&item.DeletedBy,
+		&item.MergedInto,
+	)
+	if err != nil {
+		return item, err
+	}
+
+	if err := json.Unmarshal(attributes, &item.Attributes); err != nil {
+		return item, fmt.Errorf("could not unmarshal item attributes: %w", err)
+	}
+
+	if err := json.Unmarshal(stock, &item.Stock); err != nil {
+		return item, fmt.Errorf("could not unmarshal item stock: %w", err)
+	}
+
+	return item, nil
+}
+
+func (r *Repository) GetAllItems(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
+	query := "SELECT " + itemColumns + " FROM items"
+
+	var args []any
+	var conditions []string
+	if filter.CategoryID != nil {
+		args = append(args, *filter.CategoryID)
+		conditions = append(conditions, fmt.Sprintf(`category_id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM categories WHERE id = $%d
+				UNION ALL
+				SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+			)
+			SELECT id FROM subtree)`, len(args)))
+	}
+
+	if filter.WarehouseID != nil {
+		args = append(args, *filter.WarehouseID)
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM stock s WHERE s.item_id = items.id AND s.warehouse_id = $%d)",
+			len(args),
+		))
+	}
+
+	if !filter.IncludeArchived {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	for name, value := range filter.Attributes {
+		args = append(args, name, value)
+		conditions = append(conditions, fmt.Sprintf("attributes ->> $%d = $%d", len(args)-1, len(args)))
+	}
+
+	if len(filter.Tags) > 0 {
+		args = append(args, pq.Array(filter.Tags), len(filter.Tags))
+		conditions = append(conditions, fmt.Sprintf(`id IN (
+			SELECT item_id FROM item_tags WHERE tag = ANY($%d)
+			GROUP BY item_id HAVING COUNT(*) = $%d)`, len(args)-1, len(args)))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Master.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("could not get items from db: %w", err)
 	}
+	defer rows.Close()
 
 	var items []model.Item
 	for rows.Next() {
-		var item model.Item
-		err := rows.Scan(
-			&item.ID,
-			&item.Name,
-			&item.Count,
-			&item.CreatedAt,
-		)
+		item, err := scanItem(rows)
 		if err != nil {
 			return nil, fmt.Errorf("could not scan row result to model: %w", err)
 		}
@@ -86,6 +184,20 @@ func (r *Repository) GetAllItems(ctx context.Context) ([]model.Item, error) {
 	return items, nil
 }
 
+func (r *Repository) GetItemByID(ctx context.Context, id int) (*model.Item, error) {
+	query := "SELECT " + itemColumns + " FROM items WHERE id = $1"
+
+	item, err := scanItem(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchItem
+		}
+		return nil, fmt.Errorf("could not get item from db: %w", err)
+	}
+
+	return &item, nil
+}
+
 func (r *Repository) GetUserRole(ctx context.Context, id int) (string, error) {
 	query := "SELECT role FROM users WHERE id = $1"
 
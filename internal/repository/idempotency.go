@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"wharehouse-control/internal/model"
+)
+
+// ReserveIdempotencyKey claims key for request with given fingerprint,
+// nil record means the caller owns the key and must handle request,
+// otherwise stored record of earlier request with this key is returned
+func (r *Repository) ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, ttl, lease time.Duration) (*model.IdempotencyRecord, error) {
+	// expired key is taken over as if it has never been used, so is
+	// key of request that has not answered within lease, it is
+	// left by crashed instance and would block retries until expiry
+	query := `INSERT INTO idempotency_keys(key, fingerprint, expires_at)
+	VALUES ($1, $2, NOW() + $3 * INTERVAL '1 second')
+	ON CONFLICT (key) DO UPDATE SET
+		fingerprint = EXCLUDED.fingerprint,
+		status_code = NULL,
+		content_type = '',
+		response = NULL,
+		created_at = NOW(),
+		expires_at = EXCLUDED.expires_at
+	WHERE idempotency_keys.expires_at < NOW()
+		OR (idempotency_keys.status_code IS NULL
+			AND idempotency_keys.created_at < NOW() - $4 * INTERVAL '1 second')
+	RETURNING key`
+
+	var reserved string
+	err := r.db.Master.QueryRowContext(ctx, query, key, fingerprint, ttl.Seconds(), lease.Seconds()).Scan(&reserved)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("could not reserve idempotency key: %w", err)
+	}
+
+	query = `SELECT fingerprint, status_code, content_type, COALESCE(response, '')
+	FROM idempotency_keys WHERE key = $1`
+
+	var record model.IdempotencyRecord
+	err = r.db.Master.QueryRowContext(ctx, query, key).Scan(
+		&record.Fingerprint,
+		&record.StatusCode,
+		&record.ContentType,
+		&record.Body,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// key was released by failed request in the meantime,
+			// report it as still in progress so client retries
+			return &model.IdempotencyRecord{Fingerprint: fingerprint}, nil
+		}
+		return nil, fmt.Errorf("could not get idempotency key from db: %w", err)
+	}
+
+	return &record, nil
+}
+
+func (r *Repository) SaveIdempotentResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	query := `UPDATE idempotency_keys SET status_code = $1, content_type = $2, response = $3 WHERE key = $4`
+
+	if _, err := r.db.Master.ExecContext(ctx, query, statusCode, contentType, body, key); err != nil {
+		return fmt.Errorf("could not save idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets key of request that has failed
+// on server side, so its retry is handled again
+func (r *Repository) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	query := "DELETE FROM idempotency_keys WHERE key = $1"
+
+	if _, err := r.db.Master.ExecContext(ctx, query, key); err != nil {
+		return fmt.Errorf("could not release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	query := "DELETE FROM idempotency_keys WHERE expires_at < NOW()"
+
+	result, err := r.db.Master.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("could not purge expired idempotency keys: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+// SetKitComponents replaces bill of materials of kit, kits are not nested
+// so neither kit may be a component nor component may be a kit
+func (r *Repository) SetKitComponents(ctx context.Context, setComponents *dto.SetKitComponents) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM kit_components WHERE kit_id = $1"
+	if _, err := tx.ExecContext(ctx, query, setComponents.KitID); err != nil {
+		return fmt.Errorf("could not delete kit components: %w", err)
+	}
+
+	if len(setComponents.Components) > 0 {
+		var nested bool
+		query = "SELECT EXISTS(SELECT 1 FROM kit_components WHERE component_id = $1)"
+		if err := tx.QueryRowContext(ctx, query, setComponents.KitID).Scan(&nested); err != nil {
+			return fmt.Errorf("could not check kit components: %w", err)
+		}
+		if nested {
+			return ErrKitNesting
+		}
+	}
+
+	for _, component := range setComponents.Components {
+		query = `INSERT INTO kit_components(kit_id, component_id, quantity)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (SELECT 1 FROM kit_components WHERE kit_id = $2)`
+
+		result, err := tx.ExecContext(ctx, query, setComponents.KitID, component.ItemID, component.Quantity)
+		if err != nil {
+			return mapItemForeignKeyError(err, "could not add kit component: %w")
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("could not get affected rows: %w", err)
+		}
+		if rows == 0 {
+			return ErrKitNesting
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// GetKitComponents returns bill of materials of kit with
+// available stock of every component in the warehouse
+func (r *Repository) GetKitComponents(ctx context.Context, kitID, warehouseID int) ([]model.KitComponent, error) {
+	query := `SELECT kc.component_id, i.name, i.unit, kc.quantity, COALESCE(s.quantity - r.reserved, 0)
+	FROM kit_components kc
+	JOIN items i ON i.id = kc.component_id
+	LEFT JOIN stock s ON s.item_id = kc.component_id AND s.warehouse_id = $2
+	CROSS JOIN LATERAL (` + reservedQuantity + `) r
+	WHERE kc.kit_id = $1
+	ORDER BY kc.component_id`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, kitID, warehouseID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get kit components from db: %w", err)
+	}
+	defer rows.Close()
+
+	var components []model.KitComponent
+	for rows.Next() {
+		var component model.KitComponent
+		err := rows.Scan(
+			&component.ComponentID,
+			&component.Name,
+			&component.Unit,
+			&component.Quantity,
+			&component.Available,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		components = append(components, component)
+	}
+
+	return components, nil
+}
+
+// AssembleKit moves component stock into kit stock or back in one
+// transaction, stock is locked in component order to avoid deadlocks
+func (r *Repository) AssembleKit(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error) {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setCurrentUser(ctx, tx, assembleKit.UserID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT component_id, quantity * $2 FROM kit_components
+	WHERE kit_id = $1
+	ORDER BY component_id`
+
+	rows, err := tx.QueryContext(ctx, query, assembleKit.KitID, assembleKit.Quantity)
+	if err != nil {
+		return nil, fmt.Errorf("could not get kit components from db: %w", err)
+	}
+
+	var components []model.Movement
+	for rows.Next() {
+		var component model.Movement
+		if err := rows.Scan(&component.ItemID, &component.Quantity); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+		components = append(components, component)
+	}
+	rows.Close()
+	if len(components) == 0 {
+		return nil, ErrNoSuchKit
+	}
+
+	kit := model.Movement{ItemID: assembleKit.KitID, Quantity: assembleKit.Quantity}
+	reason := model.MovementReasonKitAssembly
+	sign := 1.0
+	consumed := components
+	if assembleKit.Disassemble {
+		reason = model.MovementReasonKitDisassembly
+		sign = -1
+		consumed = []model.Movement{kit}
+	}
+
+	for _, movement := range consumed {
+		available, err := lockAvailableStock(ctx, tx, movement.ItemID, assembleKit.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		if available < movement.Quantity {
+			return nil, fmt.Errorf("%w: item %d has %g available", ErrInsufficientAvailable, movement.ItemID, available)
+		}
+	}
+
+	reference := assembleKit.Reference
+	if reference == "" {
+		reference = "kit " + strconv.Itoa(assembleKit.KitID)
+	}
+
+	movements := make([]model.Movement, 0, len(components)+1)
+	for _, component := range components {
+		movements = append(movements, model.Movement{ItemID: component.ItemID, Quantity: -sign * component.Quantity})
+	}
+	movements = append(movements, model.Movement{ItemID: kit.ItemID, Quantity: sign * kit.Quantity})
+
+	for i := range movements {
+		movement := &movements[i]
+		movement.WarehouseID = assembleKit.WarehouseID
+		movement.LocationID = assembleKit.LocationID
+		movement.Reason = reason
+		movement.Reference = reference
+		movement.UserID = &assembleKit.UserID
+	}
+
+	// stock is taken out first so that its cost is known
+	// when stock it is turned into comes in
+	componentMovements, kitMovement := movements[:len(components)], &movements[len(components)]
+	if assembleKit.Disassemble {
+		if err := applyMovement(ctx, tx, kitMovement, false); err != nil {
+			return nil, err
+		}
+		if err := allocateKitCost(ctx, tx, kitMovement, componentMovements); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range componentMovements {
+		if err := applyMovement(ctx, tx, &componentMovements[i], false); err != nil {
+			return nil, err
+		}
+	}
+
+	if !assembleKit.Disassemble {
+		var fifoCost, averageCost float64
+		for _, component := range componentMovements {
+			fifoCost -= *component.FIFOCost
+			averageCost -= *component.AverageCost
+		}
+		kitMovement.FIFOCost = &fifoCost
+		kitMovement.AverageCost = &averageCost
+
+		if err := applyMovement(ctx, tx, kitMovement, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return movements, nil
+}
+
+// allocateKitCost splits cost of disassembled kits between components
+// in proportion to their current cost, or to quantity if they have none,
+// the last component takes rounding rest so that no value is lost
+func allocateKitCost(ctx context.Context, tx *sql.Tx, kit *model.Movement, components []model.Movement) error {
+	weights := make([]float64, len(components))
+	var total float64
+	for i, component := range components {
+		unitCost, err := stockUnitCost(ctx, tx, component.ItemID, component.WarehouseID)
+		if err != nil {
+			return err
+		}
+		weights[i] = unitCost * component.Quantity
+		total += weights[i]
+	}
+	if total == 0 {
+		for i, component := range components {
+			weights[i] = component.Quantity
+			total += weights[i]
+		}
+	}
+
+	fifoRest, averageRest := -*kit.FIFOCost, -*kit.AverageCost
+	for i := range components {
+		fifoCost, averageCost := fifoRest, averageRest
+		if i < len(components)-1 {
+			fifoCost = roundCost(-*kit.FIFOCost * weights[i] / total)
+			averageCost = roundCost(-*kit.AverageCost * weights[i] / total)
+		}
+		fifoRest -= fifoCost
+		averageRest -= averageCost
+
+		components[i].FIFOCost = &fifoCost
+		components[i].AverageCost = &averageCost
+	}
+
+	return nil
+}
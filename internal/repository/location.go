@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+// locationPaths resolves full path of every location like "A-03-B-2"
+const locationPaths = `WITH RECURSIVE paths AS (
+	SELECT id, code::TEXT AS path FROM locations WHERE parent_id IS NULL
+	UNION ALL
+	SELECT l.id, p.path || '-' || l.code FROM locations l JOIN paths p ON l.parent_id = p.id
+)`
+
+func (r *Repository) CreateLocation(ctx context.Context, createLocation dto.CreateLocation) (*model.Location, error) {
+	query := `INSERT INTO locations(warehouse_id, parent_id, type, code) VALUES ($1, $2, $3, $4)
+	RETURNING id`
+
+	var id int
+	err := r.db.Master.QueryRowContext(
+		ctx,
+		query,
+		createLocation.WarehouseID,
+		createLocation.ParentID,
+		createLocation.Type,
+		createLocation.Code,
+	).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrLocationExists
+		}
+		if isForeignKeyViolation(err) {
+			return nil, ErrNoSuchWarehouse
+		}
+		return nil, fmt.Errorf("could not create location: %w", err)
+	}
+
+	return r.GetLocationByID(ctx, id)
+}
+
+func (r *Repository) GetLocationByID(ctx context.Context, id int) (*model.Location, error) {
+	query := locationPaths + `
+	SELECT l.id, l.warehouse_id, l.parent_id, l.type, l.code, p.path, l.created_at
+	FROM locations l JOIN paths p ON p.id = l.id
+	WHERE l.id = $1`
+
+	location, err := scanLocation(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchLocation
+		}
+		return nil, fmt.Errorf("could not get location from db: %w", err)
+	}
+
+	return &location, nil
+}
+
+func (r *Repository) GetLocations(ctx context.Context, warehouseID int) ([]model.Location, error) {
+	query := locationPaths + `
+	SELECT l.id, l.warehouse_id, l.parent_id, l.type, l.code, p.path, l.created_at
+	FROM locations l JOIN paths p ON p.id = l.id
+	WHERE l.warehouse_id = $1
+	ORDER BY p.path`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, warehouseID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get locations from db: %w", err)
+	}
+	defer rows.Close()
+
+	var locations []model.Location
+	for rows.Next() {
+		location, err := scanLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		locations = append(locations, location)
+	}
+
+	return locations, nil
+}
+
+func (r *Repository) DeleteLocation(ctx context.Context, warehouseID, id int) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM location_stock WHERE location_id = $1 AND quantity = 0"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("could not delete empty location stock: %w", err)
+	}
+
+	query = "DELETE FROM locations WHERE id = $1 AND warehouse_id = $2"
+	result, err := tx.ExecContext(ctx, query, id, warehouseID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrLocationNotEmpty
+		}
+		return fmt.Errorf("could not delete location by id: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchLocation
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// GetItemLocations returns where item stock physically sits, part
+// of warehouse stock not assigned to any location is reported separately
+func (r *Repository) GetItemLocations(ctx context.Context, itemID int) ([]model.ItemLocation, error) {
+	query := locationPaths + `
+	SELECT warehouse_id, warehouse_name, location_id, path, quantity FROM (
+		SELECT w.id AS warehouse_id, w.name AS warehouse_name, ls.location_id, p.path, ls.quantity
+		FROM location_stock ls
+		JOIN locations l ON l.id = ls.location_id
+		JOIN paths p ON p.id = l.id
+		JOIN warehouses w ON w.id = l.warehouse_id
+		WHERE ls.item_id = $1
+		UNION ALL
+		SELECT w.id, w.name, NULL, '', s.quantity - COALESCE((
+			SELECT SUM(ls.quantity) FROM location_stock ls
+			JOIN locations l ON l.id = ls.location_id
+			WHERE ls.item_id = s.item_id AND l.warehouse_id = s.warehouse_id
+		), 0)
+		FROM stock s JOIN warehouses w ON w.id = s.warehouse_id
+		WHERE s.item_id = $1
+	) item_locations
+	WHERE quantity <> 0
+	ORDER BY warehouse_id, path`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get item locations from db: %w", err)
+	}
+	defer rows.Close()
+
+	var locations []model.ItemLocation
+	for rows.Next() {
+		var location model.ItemLocation
+		err := rows.Scan(
+			&location.WarehouseID,
+			&location.WarehouseName,
+			&location.LocationID,
+			&location.Path,
+			&location.Quantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		locations = append(locations, location)
+	}
+
+	return locations, nil
+}
+
+func scanLocation(row rowScanner) (model.Location, error) {
+	var location model.Location
+	err := row.Scan(
+		&location.ID,
+		&location.WarehouseID,
+		&location.ParentID,
+		&location.Type,
+		&location.Code,
+		&location.Path,
+		&location.CreatedAt,
+	)
+	return location, err
+}
+
+// checkLocationWarehouse makes sure location exists and belongs to warehouse
+func checkLocationWarehouse(ctx context.Context, tx *sql.Tx, locationID, warehouseID int) error {
+	query := "SELECT warehouse_id FROM locations WHERE id = $1"
+
+	var locationWarehouseID int
+	err := tx.QueryRowContext(ctx, query, locationID).Scan(&locationWarehouseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoSuchLocation
+		}
+		return fmt.Errorf("could not get location from db: %w", err)
+	}
+
+	if locationWarehouseID != warehouseID {
+		return ErrLocationWarehouse
+	}
+
+	return nil
+}
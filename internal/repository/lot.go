@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (r *Repository) CreateLot(ctx context.Context, createLot dto.CreateLot) (*model.Lot, error) {
+	query := `INSERT INTO lots(item_id, lot_number, manufactured_at, expires_at) VALUES ($1, $2, $3, $4)
+	RETURNING id, item_id, lot_number, manufactured_at, expires_at, created_at`
+
+	var lot model.Lot
+	err := r.db.Master.QueryRowContext(
+		ctx,
+		query,
+		createLot.ItemID,
+		createLot.LotNumber,
+		createLot.ManufacturedAt,
+		createLot.ExpiresAt,
+	).Scan(
+		&lot.ID,
+		&lot.ItemID,
+		&lot.LotNumber,
+		&lot.ManufacturedAt,
+		&lot.ExpiresAt,
+		&lot.CreatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrLotExists
+		}
+		return nil, mapItemForeignKeyError(err, "could not create lot in db: %w")
+	}
+
+	return &lot, nil
+}
+
+func (r *Repository) GetLots(ctx context.Context, itemID int) ([]model.Lot, error) {
+	query := `SELECT l.id, l.item_id, l.lot_number, l.manufactured_at, l.expires_at,
+	COALESCE((SELECT SUM(quantity) FROM lot_stock WHERE lot_id = l.id), 0), l.created_at
+	FROM lots l
+	WHERE l.item_id = $1
+	ORDER BY l.expires_at NULLS LAST, l.id`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get lots from db: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []model.Lot
+	for rows.Next() {
+		var lot model.Lot
+		err := rows.Scan(
+			&lot.ID,
+			&lot.ItemID,
+			&lot.LotNumber,
+			&lot.ManufacturedAt,
+			&lot.ExpiresAt,
+			&lot.Quantity,
+			&lot.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		lots = append(lots, lot)
+	}
+
+	return lots, nil
+}
+
+// GetPickableLots returns unexpired lots of item that have stock in
+// the warehouse in first-expired-first-out order, lots without expiry
+// date go last and ties are broken by manufacture date
+func (r *Repository) GetPickableLots(ctx context.Context, itemID, warehouseID int) ([]model.LotPick, error) {
+	query := `SELECT l.id, l.lot_number, l.expires_at, ls.quantity
+	FROM lots l JOIN lot_stock ls ON ls.lot_id = l.id
+	WHERE l.item_id = $1 AND ls.warehouse_id = $2 AND ls.quantity > 0
+	AND (l.expires_at IS NULL OR l.expires_at >= CURRENT_DATE)
+	ORDER BY l.expires_at NULLS LAST, l.manufactured_at NULLS LAST, l.id`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, itemID, warehouseID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get pickable lots from db: %w", err)
+	}
+	defer rows.Close()
+
+	var picks []model.LotPick
+	for rows.Next() {
+		var pick model.LotPick
+		if err := rows.Scan(&pick.LotID, &pick.LotNumber, &pick.ExpiresAt, &pick.Available); err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		picks = append(picks, pick)
+	}
+
+	return picks, nil
+}
+
+// GetExpiringLots returns lots in stock expiring within days,
+// already expired lots are included as they need attention most
+func (r *Repository) GetExpiringLots(ctx context.Context, days int, warehouseID *int) ([]model.ExpiringLot, error) {
+	query := `SELECT l.id, l.lot_number, i.id, i.name, w.id, w.name, l.expires_at, ls.quantity
+	FROM lots l
+	JOIN items i ON i.id = l.item_id
+	JOIN lot_stock ls ON ls.lot_id = l.id
+	JOIN warehouses w ON w.id = ls.warehouse_id
+	WHERE ls.quantity > 0 AND l.expires_at <= CURRENT_DATE + $1::INT
+	AND ($2::INT IS NULL OR ls.warehouse_id = $2)
+	ORDER BY l.expires_at, l.id, w.id`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, days, warehouseID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get expiring lots from db: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []model.ExpiringLot
+	for rows.Next() {
+		var lot model.ExpiringLot
+		err := rows.Scan(
+			&lot.LotID,
+			&lot.LotNumber,
+			&lot.ItemID,
+			&lot.ItemName,
+			&lot.WarehouseID,
+			&lot.WarehouseName,
+			&lot.ExpiresAt,
+			&lot.Quantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		lots = append(lots, lot)
+	}
+
+	return lots, nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"wharehouse-control/internal/dto"
+)
+
+// mergeConflicts are records of two items that cannot live under one item
+var mergeConflicts = []struct {
+	query  string
+	reason string
+}{
+	{
+		query: `SELECT EXISTS(SELECT 1 FROM serials s JOIN serials t ON t.serial_number = s.serial_number
+		WHERE s.item_id = $1 AND t.item_id = $2)`,
+		reason: "items have the same serial numbers",
+	},
+	{
+		query: `SELECT EXISTS(SELECT 1 FROM lots s JOIN lots t ON t.lot_number = s.lot_number
+		WHERE s.item_id = $1 AND t.item_id = $2)`,
+		reason: "items have lots with the same number",
+	},
+	{
+		query: `SELECT EXISTS(SELECT 1 FROM purchase_order_lines s JOIN purchase_order_lines t ON t.purchase_order_id = s.purchase_order_id
+		WHERE s.item_id = $1 AND t.item_id = $2)`,
+		reason: "items are ordered in the same purchase order",
+	},
+	{
+		query: `SELECT EXISTS(SELECT 1 FROM outbound_order_lines s JOIN outbound_order_lines t ON t.order_id = s.order_id
+		WHERE s.item_id = $1 AND t.item_id = $2)`,
+		reason: "items are in the same outbound order",
+	},
+	{
+		query: `SELECT EXISTS(SELECT 1 FROM stocktake_lines s JOIN stocktake_lines t ON t.stocktake_id = s.stocktake_id
+			AND t.location_id IS NOT DISTINCT FROM s.location_id
+		WHERE s.item_id = $1 AND t.item_id = $2)`,
+		reason: "items are counted at the same place in the same stocktake",
+	},
+}
+
+// mergeSummedRows move rows keyed by item to target, quantities
+// of rows target already has are added up, other settings of
+// target win over the ones of source
+var mergeSummedRows = []string{
+	`WITH moved AS (DELETE FROM stock WHERE item_id = $1 RETURNING warehouse_id, quantity, value)
+	INSERT INTO stock(item_id, warehouse_id, quantity, value)
+	SELECT $2, warehouse_id, quantity, value FROM moved
+	ON CONFLICT (item_id, warehouse_id) DO UPDATE SET
+		quantity = stock.quantity + EXCLUDED.quantity,
+		value = stock.value + EXCLUDED.value`,
+	`WITH moved AS (DELETE FROM location_stock WHERE item_id = $1 RETURNING location_id, quantity)
+	INSERT INTO location_stock(item_id, location_id, quantity)
+	SELECT $2, location_id, quantity FROM moved
+	ON CONFLICT (item_id, location_id) DO UPDATE SET quantity = location_stock.quantity + EXCLUDED.quantity`,
+	`WITH moved AS (DELETE FROM item_units WHERE item_id = $1 RETURNING unit, factor)
+	INSERT INTO item_units(item_id, unit, factor)
+	SELECT $2, unit, factor FROM moved
+	ON CONFLICT DO NOTHING`,
+	`WITH moved AS (DELETE FROM reorder_rules WHERE item_id = $1
+		RETURNING warehouse_id, min_level, max_level, reorder_quantity)
+	INSERT INTO reorder_rules(item_id, warehouse_id, min_level, max_level, reorder_quantity)
+	SELECT $2, warehouse_id, min_level, max_level, reorder_quantity FROM moved
+	ON CONFLICT DO NOTHING`,
+	`WITH moved AS (DELETE FROM supplier_items WHERE item_id = $1
+		RETURNING supplier_id, supplier_sku, price, min_order_quantity)
+	INSERT INTO supplier_items(supplier_id, item_id, supplier_sku, price, min_order_quantity)
+	SELECT supplier_id, $2, supplier_sku, price, min_order_quantity FROM moved
+	ON CONFLICT DO NOTHING`,
+	`WITH moved AS (DELETE FROM item_tags WHERE item_id = $1 RETURNING tag)
+	INSERT INTO item_tags(item_id, tag)
+	SELECT $2, tag FROM moved
+	ON CONFLICT DO NOTHING`,
+}
+
+// mergeMovedTables have rows that simply change their item,
+// stock ledger and change history among them
+var mergeMovedTables = []string{
+	"stock_movements",
+	"cost_layers",
+	"items_history",
+	"lots",
+	"serials",
+	"reservations",
+	"stock_alerts",
+	"purchase_order_lines",
+	"receipt_lines",
+	"outbound_order_lines",
+	"outbound_picks",
+	"transfer_order_lines",
+	"stocktake_lines",
+	"item_attachments",
+}
+
+// MergeItems moves everything of source item to target item and
+// archives source pointing to target, so that clients holding id
+// of source can find where it went. Counts of items are updated
+// through the items table so the merge shows up in their history
+func (r *Repository) MergeItems(ctx context.Context, mergeItems dto.MergeItems) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setCurrentUser(ctx, tx, mergeItems.UserID); err != nil {
+		return err
+	}
+
+	if err := checkMergedItems(ctx, tx, mergeItems.SourceID, mergeItems.TargetID); err != nil {
+		return err
+	}
+
+	for _, query := range mergeSummedRows {
+		if _, err := tx.ExecContext(ctx, query, mergeItems.SourceID, mergeItems.TargetID); err != nil {
+			return fmt.Errorf("could not merge item records: %w", err)
+		}
+	}
+
+	for _, table := range mergeMovedTables {
+		query := fmt.Sprintf("UPDATE %s SET item_id = $2 WHERE item_id = $1", table)
+		if _, err := tx.ExecContext(ctx, query, mergeItems.SourceID, mergeItems.TargetID); err != nil {
+			return fmt.Errorf("could not move %s of item: %w", table, err)
+		}
+	}
+
+	// pending requests were made against version of source item, which
+	// is gone after the merge, so they could never be approved
+	query := `UPDATE change_requests SET status = 'rejected', reviewed_by = $3, reviewed_at = NOW(),
+		comment = 'item was merged into ' || $2::text
+	WHERE item_id = $1 AND status = 'pending'`
+	if _, err := tx.ExecContext(ctx, query, mergeItems.SourceID, mergeItems.TargetID, mergeItems.UserID); err != nil {
+		return fmt.Errorf("could not reject change requests of merged item: %w", err)
+	}
+
+	query = "UPDATE items SET count = count + (SELECT count FROM items WHERE id = $1) WHERE id = $2"
+	if _, err := tx.ExecContext(ctx, query, mergeItems.SourceID, mergeItems.TargetID); err != nil {
+		return fmt.Errorf("could not update count of target item: %w", err)
+	}
+
+	query = `UPDATE items SET count = 0, deleted_at = NOW(), deleted_by = $3, merged_into = $2
+	WHERE id = $1`
+	_, err = tx.ExecContext(ctx, query, mergeItems.SourceID, mergeItems.TargetID, mergeItems.UserID)
+	if err != nil {
+		return mapItemForeignKeyError(err, "could not archive merged item: %w")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// checkMergedItems locks both items and makes sure their stock
+// is counted the same way and nothing of them would collide
+func checkMergedItems(ctx context.Context, tx *sql.Tx, sourceID, targetID int) error {
+	query := `SELECT id, deleted_at IS NOT NULL, unit, serialized FROM items
+	WHERE id IN ($1, $2)
+	ORDER BY id
+	FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("could not lock merged items: %w", err)
+	}
+	defer rows.Close()
+
+	type mergedItem struct {
+		archived   bool
+		unit       string
+		serialized bool
+	}
+	items := make(map[int]mergedItem, 2)
+	for rows.Next() {
+		var id int
+		var item mergedItem
+		if err := rows.Scan(&id, &item.archived, &item.unit, &item.serialized); err != nil {
+			return fmt.Errorf("could not scan row result to model: %w", err)
+		}
+		items[id] = item
+	}
+	rows.Close()
+
+	source, ok := items[sourceID]
+	if !ok {
+		return ErrNoSuchItem
+	}
+	target, ok := items[targetID]
+	if !ok {
+		return ErrNoSuchItem
+	}
+
+	switch {
+	case source.archived || target.archived:
+		return ErrItemArchived
+	case source.unit != target.unit:
+		return fmt.Errorf("%w: items have different base units", ErrMergeConflict)
+	case source.serialized != target.serialized:
+		return fmt.Errorf("%w: only one of items is tracked by serial numbers", ErrMergeConflict)
+	}
+
+	var inKit bool
+	query = "SELECT EXISTS(SELECT 1 FROM kit_components WHERE kit_id = $1 OR component_id = $1)"
+	if err := tx.QueryRowContext(ctx, query, sourceID).Scan(&inKit); err != nil {
+		return fmt.Errorf("could not check kit components: %w", err)
+	}
+	if inKit {
+		return fmt.Errorf("%w: merged item is a kit or a component of kit", ErrMergeConflict)
+	}
+
+	for _, conflict := range mergeConflicts {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, conflict.query, sourceID, targetID).Scan(&exists); err != nil {
+			return fmt.Errorf("could not check merged items: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("%w: %s", ErrMergeConflict, conflict.reason)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+
+	"github.com/lib/pq"
+)
+
+// setCurrentUser makes user visible to items history trigger for the rest of tx
+func setCurrentUser(ctx context.Context, tx *sql.Tx, userID int) error {
+	// I know about risk of sql injection here but it is not possible
+	// to use placehlder because of dirver and userID is int also
+	// it guarantees than sql injection string will not be placed
+	query := fmt.Sprintf("SET LOCAL app.current_user_id = %d;", userID)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("could not set current user: %w", err)
+	}
+
+	return nil
+}
+
+// lockActiveItem makes sure item is not archived and keeps it from being
+// archived until tx ends, lockMode is FOR UPDATE when tx changes item row
+// later and FOR SHARE otherwise
+func lockActiveItem(ctx context.Context, tx *sql.Tx, itemID int, lockMode string) error {
+	query := "SELECT deleted_at IS NOT NULL FROM items WHERE id = $1 " + lockMode
+
+	var archived bool
+	if err := tx.QueryRowContext(ctx, query, itemID).Scan(&archived); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoSuchItem
+		}
+		return fmt.Errorf("could not lock item: %w", err)
+	}
+	if archived {
+		return fmt.Errorf("%w: item %d", ErrItemArchived, itemID)
+	}
+
+	return nil
+}
+
+// holdConsumingReasons are movements that take stock held for them by
+// reservation or outbound order, hold is released in the same tx
+var holdConsumingReasons = map[string]bool{
+	model.MovementReasonReservation: true,
+	model.MovementReasonShipment:    true,
+}
+
+// applyMovement records movement in the ledger and applies its quantity
+// to warehouse, location and item totals with relative updates, so
+// concurrent movements of the same item never overwrite each other.
+// Stock is only taken from the part nobody holds, unless movement
+// consumes its own hold, allowNegative disables checks of resulting
+// quantities
+func applyMovement(ctx context.Context, tx *sql.Tx, movement *model.Movement, allowNegative bool) error {
+	if err := lockActiveItem(ctx, tx, movement.ItemID, "FOR UPDATE"); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO stock(item_id, warehouse_id, quantity) VALUES ($1, $2, $3)
+	ON CONFLICT (item_id, warehouse_id) DO UPDATE SET quantity = stock.quantity + EXCLUDED.quantity
+	RETURNING quantity`
+
+	var warehouseQuantity float64
+	err := tx.QueryRowContext(ctx, query, movement.ItemID, movement.WarehouseID, movement.Quantity).Scan(&warehouseQuantity)
+	if err != nil {
+		return mapItemForeignKeyError(err, "could not update item stock in db: %w")
+	}
+	if warehouseQuantity < 0 && !allowNegative {
+		return ErrInsufficientStock
+	}
+	if movement.Quantity < 0 && !allowNegative && !holdConsumingReasons[movement.Reason] {
+		available, err := lockAvailableStock(ctx, tx, movement.ItemID, movement.WarehouseID)
+		if err != nil {
+			return err
+		}
+		if available < 0 {
+			return ErrInsufficientAvailable
+		}
+	}
+
+	if movement.LocationID != nil {
+		if err := checkLocationWarehouse(ctx, tx, *movement.LocationID, movement.WarehouseID); err != nil {
+			return err
+		}
+
+		query = `INSERT INTO location_stock(item_id, location_id, quantity) VALUES ($1, $2, $3)
+		ON CONFLICT (item_id, location_id) DO UPDATE SET quantity = location_stock.quantity + EXCLUDED.quantity
+		RETURNING quantity`
+
+		var locationQuantity float64
+		err := tx.QueryRowContext(ctx, query, movement.ItemID, *movement.LocationID, movement.Quantity).Scan(&locationQuantity)
+		if err != nil {
+			return fmt.Errorf("could not update item location stock in db: %w", err)
+		}
+		if locationQuantity < 0 && !allowNegative {
+			return ErrInsufficientStock
+		}
+	} else if movement.Quantity < 0 && !allowNegative {
+		// stock taken without location must come from the part
+		// of warehouse stock that is not put away to locations
+		query = `SELECT COALESCE(SUM(ls.quantity), 0) FROM location_stock ls
+		JOIN locations l ON l.id = ls.location_id
+		WHERE ls.item_id = $1 AND l.warehouse_id = $2`
+
+		var located float64
+		if err := tx.QueryRowContext(ctx, query, movement.ItemID, movement.WarehouseID).Scan(&located); err != nil {
+			return fmt.Errorf("could not get located item stock from db: %w", err)
+		}
+		if warehouseQuantity < located {
+			return ErrBelowLocatedStock
+		}
+	}
+
+	if err := applyLotMovement(ctx, tx, movement, warehouseQuantity, allowNegative); err != nil {
+		return err
+	}
+
+	serialIDs, err := moveSerials(ctx, tx, movement)
+	if err != nil {
+		return err
+	}
+
+	var itemCount float64
+	query = "UPDATE items SET count = count + $1 WHERE id = $2 RETURNING count"
+	if err := tx.QueryRowContext(ctx, query, movement.Quantity, movement.ItemID).Scan(&itemCount); err != nil {
+		return fmt.Errorf("could not update item count in db: %w", err)
+	}
+
+	if err := insertMovement(ctx, tx, movement); err != nil {
+		return err
+	}
+
+	if err := costMovement(ctx, tx, movement, quantityDelta(warehouseQuantity, movement.Quantity)); err != nil {
+		return err
+	}
+
+	if err := linkSerials(ctx, tx, movement.ID, serialIDs); err != nil {
+		return err
+	}
+
+	return raiseStockAlerts(ctx, tx, movement, warehouseQuantity, itemCount)
+}
+
+func (r *Repository) AdjustItem(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error) {
+	tx, err := r.db.Master.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	movement, err := applyItemAdjustment(ctx, tx, adjustItem)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return movement, nil
+}
+
+// applyItemAdjustment records relative stock change of item within tx
+func applyItemAdjustment(ctx context.Context, tx *sql.Tx, adjustItem *dto.AdjustItem) (*model.Movement, error) {
+	if err := setCurrentUser(ctx, tx, adjustItem.UserID); err != nil {
+		return nil, err
+	}
+
+	movement := &model.Movement{
+		ItemID:      adjustItem.ID,
+		WarehouseID: adjustItem.WarehouseID,
+		LocationID:  adjustItem.LocationID,
+		LotID:       adjustItem.LotID,
+		Quantity:    adjustItem.Delta,
+		Serials:     adjustItem.Serials,
+		Reason:      adjustItem.Reason,
+		Reference:   adjustItem.Reference,
+		UserID:      &adjustItem.UserID,
+		UnitCost:    adjustItem.UnitCost,
+	}
+	if err := applyMovement(ctx, tx, movement, adjustItem.AllowNegative); err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+// applyLotMovement keeps lot stock in step with warehouse stock,
+// stock taken without lot must come from the part not tracked in lots
+func applyLotMovement(ctx context.Context, tx *sql.Tx, movement *model.Movement, warehouseQuantity float64, allowNegative bool) error {
+	if movement.LotID == nil {
+		if movement.Quantity > 0 || allowNegative {
+			return nil
+		}
+
+		query := `SELECT COALESCE(SUM(ls.quantity), 0) FROM lot_stock ls
+		JOIN lots l ON l.id = ls.lot_id
+		WHERE l.item_id = $1 AND ls.warehouse_id = $2`
+
+		var lotted float64
+		if err := tx.QueryRowContext(ctx, query, movement.ItemID, movement.WarehouseID).Scan(&lotted); err != nil {
+			return fmt.Errorf("could not get lot stock from db: %w", err)
+		}
+		if warehouseQuantity < lotted {
+			return ErrBelowLottedStock
+		}
+		return nil
+	}
+
+	var itemID int
+	query := "SELECT item_id FROM lots WHERE id = $1"
+	if err := tx.QueryRowContext(ctx, query, *movement.LotID).Scan(&itemID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoSuchLot
+		}
+		return fmt.Errorf("could not get lot from db: %w", err)
+	}
+	if itemID != movement.ItemID {
+		return ErrLotItem
+	}
+
+	query = `INSERT INTO lot_stock(lot_id, warehouse_id, quantity) VALUES ($1, $2, $3)
+	ON CONFLICT (lot_id, warehouse_id) DO UPDATE SET quantity = lot_stock.quantity + EXCLUDED.quantity
+	RETURNING quantity`
+
+	var lotQuantity float64
+	err := tx.QueryRowContext(ctx, query, *movement.LotID, movement.WarehouseID, movement.Quantity).Scan(&lotQuantity)
+	if err != nil {
+		return fmt.Errorf("could not update lot stock in db: %w", err)
+	}
+	if lotQuantity < 0 && !allowNegative {
+		return ErrInsufficientStock
+	}
+
+	return nil
+}
+
+func insertMovement(ctx context.Context, tx *sql.Tx, movement *model.Movement) error {
+	query := `INSERT INTO stock_movements(item_id, warehouse_id, location_id, lot_id, quantity, reason, reference, user_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING id, created_at`
+
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		movement.ItemID,
+		movement.WarehouseID,
+		movement.LocationID,
+		movement.LotID,
+		movement.Quantity,
+		movement.Reason,
+		movement.Reference,
+		movement.UserID,
+	).Scan(&movement.ID, &movement.CreatedAt)
+	if err != nil {
+		return mapItemForeignKeyError(err, "could not record stock movement: %w")
+	}
+
+	return nil
+}
+
+// movementColumns selects movement aliased as m with
+// serial numbers of units it has moved
+const movementColumns = `m.id, m.item_id, m.warehouse_id, m.location_id, m.lot_id, m.quantity,
+	m.reason, m.reference, m.user_id, m.created_at, m.unit_cost, m.fifo_cost, m.average_cost,
+	ARRAY(
+		SELECT s.serial_number FROM movement_serials ms JOIN serials s ON s.id = ms.serial_id
+		WHERE ms.movement_id = m.id ORDER BY s.serial_number
+	)`
+
+func scanMovement(row rowScanner) (model.Movement, error) {
+	var movement model.Movement
+	err := row.Scan(
+		&movement.ID,
+		&movement.ItemID,
+		&movement.WarehouseID,
+		&movement.LocationID,
+		&movement.LotID,
+		&movement.Quantity,
+		&movement.Reason,
+		&movement.Reference,
+		&movement.UserID,
+		&movement.CreatedAt,
+		&movement.UnitCost,
+		&movement.FIFOCost,
+		&movement.AverageCost,
+		pq.Array(&movement.Serials),
+	)
+	return movement, err
+}
+
+func (r *Repository) GetMovements(ctx context.Context, filter dto.MovementFilter) ([]model.Movement, error) {
+	query := "SELECT " + movementColumns + ` FROM stock_movements m
+	WHERE m.item_id = $1 AND ($2::INT IS NULL OR m.warehouse_id = $2)
+	ORDER BY m.created_at DESC, m.id DESC
+	LIMIT $3`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, filter.ItemID, filter.WarehouseID, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not get stock movements from db: %w", err)
+	}
+	defer rows.Close()
+
+	var movements []model.Movement
+	for rows.Next() {
+		movement, err := scanMovement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		movements = append(movements, movement)
+	}
+
+	return movements, nil
+}
+
+// quantityDelta rounds difference of two quantities to storage precision
+func quantityDelta(to, from float64) float64 {
+	return math.Round((to-from)*1000) / 1000
+}
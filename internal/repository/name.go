@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+// DuplicateNameError points to active item that already
+// has the same normalized name in uniqueness scope
+type DuplicateNameError struct {
+	ItemID int
+	Name   string
+}
+
+func (e *DuplicateNameError) Error() string {
+	return fmt.Sprintf("%s: item %d %q", ErrItemNameTaken, e.ItemID, e.Name)
+}
+
+func (e *DuplicateNameError) Unwrap() error {
+	return ErrItemNameTaken
+}
+
+// checkItemName looks for another active item with the same normalized
+// name as item in scope, item has to be written already so that its
+// category and warehouses are known. Writers of the same name wait for
+// each other on advisory lock, so the check holds until commit
+func checkItemName(ctx context.Context, tx *sql.Tx, itemID int, scope string) error {
+	if scope == "" || scope == model.NameScopeNone {
+		return nil
+	}
+
+	query := "SELECT pg_advisory_xact_lock(hashtext(normalized_name)) FROM items WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, itemID); err != nil {
+		return fmt.Errorf("could not lock item name: %w", err)
+	}
+
+	query = `SELECT o.id, o.name FROM items i
+	JOIN items o ON o.normalized_name = i.normalized_name AND o.id <> i.id AND o.deleted_at IS NULL
+	WHERE i.id = $1 AND (
+		$2 = 'global'
+		OR ($2 = 'category' AND o.category_id IS NOT DISTINCT FROM i.category_id)
+		OR ($2 = 'warehouse' AND EXISTS (
+			SELECT 1 FROM stock a JOIN stock b ON b.warehouse_id = a.warehouse_id
+			WHERE a.item_id = i.id AND b.item_id = o.id
+		))
+	)
+	ORDER BY o.id
+	LIMIT 1`
+
+	var existing DuplicateNameError
+	err := tx.QueryRowContext(ctx, query, itemID, scope).Scan(&existing.ItemID, &existing.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("could not check item name: %w", err)
+	}
+
+	return &existing
+}
+
+// GetDuplicateItems returns pairs of active items whose normalized
+// names are at least as similar as threshold, most similar first
+func (r *Repository) GetDuplicateItems(ctx context.Context, filter dto.DuplicateFilter) ([]model.DuplicatePair, error) {
+	tx, err := r.db.Master.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// % operator uses the trigram index and takes its threshold from setting
+	query := "SELECT set_config('pg_trgm.similarity_threshold', $1, true)"
+	threshold := strconv.FormatFloat(filter.Threshold, 'f', -1, 64)
+	if _, err := tx.ExecContext(ctx, query, threshold); err != nil {
+		return nil, fmt.Errorf("could not set similarity threshold: %w", err)
+	}
+
+	query = `SELECT a.id, a.name, b.id, b.name, similarity(a.normalized_name, b.normalized_name) AS score
+	FROM items a
+	JOIN items b ON b.id > a.id AND b.normalized_name % a.normalized_name
+	WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+		AND ($1::INT IS NULL OR a.id = $1 OR b.id = $1)
+	ORDER BY score DESC, a.id, b.id
+	LIMIT $2`
+
+	rows, err := tx.QueryContext(ctx, query, filter.ItemID, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not get duplicate items from db: %w", err)
+	}
+	defer rows.Close()
+
+	var pairs []model.DuplicatePair
+	for rows.Next() {
+		var pair model.DuplicatePair
+		err := rows.Scan(&pair.ItemID, &pair.ItemName, &pair.DuplicateID, &pair.DuplicateName, &pair.Similarity)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, nil
+}
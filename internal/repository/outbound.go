@@ -0,0 +1,445 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+
+	"github.com/lib/pq"
+)
+
+const outboundOrderColumns = `id, warehouse_id, customer, reference, status, user_id,
+	package_count, carrier, tracking_number, created_at, updated_at, shipped_at`
+
+func scanOutboundOrder(row rowScanner) (model.OutboundOrder, error) {
+	var order model.OutboundOrder
+	err := row.Scan(
+		&order.ID,
+		&order.WarehouseID,
+		&order.Customer,
+		&order.Reference,
+		&order.Status,
+		&order.UserID,
+		&order.PackageCount,
+		&order.Carrier,
+		&order.TrackingNumber,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+		&order.ShippedAt,
+	)
+	return order, err
+}
+
+// CreateOutboundOrder holds stock for every line, so quantity
+// promised to the order cannot be reserved or ordered again
+func (r *Repository) CreateOutboundOrder(ctx context.Context, createOrder dto.CreateOutboundOrder) (*model.OutboundOrder, error) {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO outbound_orders(warehouse_id, customer, reference, user_id)
+	VALUES ($1, $2, $3, $4)
+	RETURNING ` + outboundOrderColumns
+
+	order, err := scanOutboundOrder(tx.QueryRowContext(
+		ctx,
+		query,
+		createOrder.WarehouseID,
+		createOrder.Customer,
+		createOrder.Reference,
+		createOrder.UserID,
+	))
+	if err != nil {
+		return nil, mapItemForeignKeyError(err, "could not create outbound order in db: %w")
+	}
+
+	query = `INSERT INTO outbound_order_lines(order_id, item_id, quantity) VALUES ($1, $2, $3)
+	RETURNING id`
+	for _, line := range createOrder.Lines {
+		if err := lockActiveItem(ctx, tx, line.ItemID, "FOR SHARE"); err != nil {
+			return nil, err
+		}
+
+		available, err := lockAvailableStock(ctx, tx, line.ItemID, createOrder.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		if available < line.Quantity {
+			return nil, fmt.Errorf("%w: item %d", ErrInsufficientAvailable, line.ItemID)
+		}
+
+		orderLine := model.OutboundOrderLine{OrderID: order.ID, ItemID: line.ItemID, Quantity: line.Quantity}
+		if err := tx.QueryRowContext(ctx, query, order.ID, line.ItemID, line.Quantity).Scan(&orderLine.ID); err != nil {
+			return nil, mapItemForeignKeyError(err, "could not create outbound order line in db: %w")
+		}
+
+		order.Lines = append(order.Lines, orderLine)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return &order, nil
+}
+
+func (r *Repository) GetOutboundOrders(ctx context.Context, filter dto.OutboundOrderFilter) ([]model.OutboundOrder, error) {
+	query := "SELECT " + outboundOrderColumns + " FROM outbound_orders"
+
+	var args []any
+	var conditions []string
+	if filter.WarehouseID != nil {
+		args = append(args, *filter.WarehouseID)
+		conditions = append(conditions, fmt.Sprintf("warehouse_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Master.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not get outbound orders from db: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []model.OutboundOrder
+	for rows.Next() {
+		order, err := scanOutboundOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// GetOutboundOrderByID returns order with its lines and pick list
+func (r *Repository) GetOutboundOrderByID(ctx context.Context, id int) (*model.OutboundOrder, error) {
+	query := "SELECT " + outboundOrderColumns + " FROM outbound_orders WHERE id = $1"
+
+	order, err := scanOutboundOrder(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchOutboundOrder
+		}
+		return nil, fmt.Errorf("could not get outbound order from db: %w", err)
+	}
+
+	query = "SELECT id, order_id, item_id, quantity FROM outbound_order_lines WHERE order_id = $1 ORDER BY id"
+	rows, err := r.db.Master.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not get outbound order lines from db: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line model.OutboundOrderLine
+		if err := rows.Scan(&line.ID, &line.OrderID, &line.ItemID, &line.Quantity); err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		order.Lines = append(order.Lines, line)
+	}
+
+	query = locationPaths + `
+	SELECT pk.id, pk.order_id, pk.line_id, pk.sequence, pk.item_id, pk.location_id, COALESCE(p.path, ''),
+		pk.lot_id, l.lot_number, pk.quantity, pk.serials, pk.movement_id
+	FROM outbound_picks pk
+	LEFT JOIN paths p ON p.id = pk.location_id
+	LEFT JOIN lots l ON l.id = pk.lot_id
+	WHERE pk.order_id = $1
+	ORDER BY pk.sequence`
+
+	pickRows, err := r.db.Master.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not get pick list from db: %w", err)
+	}
+	defer pickRows.Close()
+
+	for pickRows.Next() {
+		var pick model.Pick
+		err := pickRows.Scan(
+			&pick.ID,
+			&pick.OrderID,
+			&pick.LineID,
+			&pick.Sequence,
+			&pick.ItemID,
+			&pick.LocationID,
+			&pick.Path,
+			&pick.LotID,
+			&pick.LotNumber,
+			&pick.Quantity,
+			pq.Array(&pick.Serials),
+			&pick.MovementID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		order.Picks = append(order.Picks, pick)
+	}
+
+	return &order, nil
+}
+
+// lockOutboundOrder locks order for change and returns its status
+func lockOutboundOrder(ctx context.Context, tx *sql.Tx, id int) (string, error) {
+	query := "SELECT status FROM outbound_orders WHERE id = $1 FOR UPDATE"
+
+	var status string
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNoSuchOutboundOrder
+		}
+		return "", fmt.Errorf("could not lock outbound order: %w", err)
+	}
+
+	return status, nil
+}
+
+// SavePickList replaces pick list of new or picking order
+// and moves it to picking
+func (r *Repository) SavePickList(ctx context.Context, orderID int, picks []model.Pick) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockOutboundOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if status != model.OutboundOrderStatusNew && status != model.OutboundOrderStatusPicking {
+		return ErrOutboundOrderStatus
+	}
+
+	query := "DELETE FROM outbound_picks WHERE order_id = $1"
+	if _, err := tx.ExecContext(ctx, query, orderID); err != nil {
+		return fmt.Errorf("could not delete previous pick list: %w", err)
+	}
+
+	query = `INSERT INTO outbound_picks(order_id, line_id, sequence, item_id, location_id, lot_id, quantity)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, pick := range picks {
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			orderID,
+			pick.LineID,
+			pick.Sequence,
+			pick.ItemID,
+			pick.LocationID,
+			pick.LotID,
+			pick.Quantity,
+		)
+		if err != nil {
+			return fmt.Errorf("could not create pick in db: %w", err)
+		}
+	}
+
+	query = "UPDATE outbound_orders SET status = 'picking', updated_at = NOW() WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, orderID); err != nil {
+		return fmt.Errorf("could not update outbound order status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// PackOutboundOrder confirms picked goods are packed and
+// stores serial numbers scanned while packing
+func (r *Repository) PackOutboundOrder(ctx context.Context, packOrder *dto.PackOutboundOrder) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockOutboundOrder(ctx, tx, packOrder.ID)
+	if err != nil {
+		return err
+	}
+	if status != model.OutboundOrderStatusPicking {
+		return ErrOutboundOrderStatus
+	}
+
+	var warehouseID int
+	query := "SELECT warehouse_id FROM outbound_orders WHERE id = $1"
+	if err := tx.QueryRowContext(ctx, query, packOrder.ID).Scan(&warehouseID); err != nil {
+		return fmt.Errorf("could not get outbound order warehouse: %w", err)
+	}
+
+	// location is only filled in for picks that have none, picks
+	// of located stock keep location they were allocated from
+	query = `UPDATE outbound_picks SET serials = $1, location_id = COALESCE(location_id, $4)
+	WHERE order_id = $2 AND id = $3`
+	for _, pick := range packOrder.Picks {
+		if pick.LocationID != nil {
+			if err := checkLocationWarehouse(ctx, tx, *pick.LocationID, warehouseID); err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.ExecContext(ctx, query, pq.Array(pick.Serials), packOrder.ID, pick.PickID, pick.LocationID)
+		if err != nil {
+			return fmt.Errorf("could not save packed serials in db: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("could not get number of affected rows in db: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return ErrNoSuchPick
+		}
+	}
+
+	query = `UPDATE outbound_orders SET status = 'packed', package_count = $1, updated_at = NOW()
+	WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, packOrder.PackageCount, packOrder.ID); err != nil {
+		return fmt.Errorf("could not update outbound order status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// ShipOutboundOrder posts a shipment movement for every pick,
+// stock leaves warehouse and the hold of order is gone at once
+func (r *Repository) ShipOutboundOrder(ctx context.Context, shipOrder *dto.ShipOutboundOrder) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setCurrentUser(ctx, tx, shipOrder.UserID); err != nil {
+		return err
+	}
+
+	status, err := lockOutboundOrder(ctx, tx, shipOrder.ID)
+	if err != nil {
+		return err
+	}
+	if status != model.OutboundOrderStatusPacked {
+		return ErrOutboundOrderStatus
+	}
+
+	query := `SELECT pk.id, pk.item_id, o.warehouse_id, pk.location_id, pk.lot_id, pk.quantity, pk.serials
+	FROM outbound_picks pk JOIN outbound_orders o ON o.id = pk.order_id
+	WHERE pk.order_id = $1
+	ORDER BY pk.sequence`
+
+	rows, err := tx.QueryContext(ctx, query, shipOrder.ID)
+	if err != nil {
+		return fmt.Errorf("could not get pick list from db: %w", err)
+	}
+
+	type pickMovement struct {
+		pickID   int
+		movement model.Movement
+	}
+	var movements []pickMovement
+	for rows.Next() {
+		var pick pickMovement
+		err := rows.Scan(
+			&pick.pickID,
+			&pick.movement.ItemID,
+			&pick.movement.WarehouseID,
+			&pick.movement.LocationID,
+			&pick.movement.LotID,
+			&pick.movement.Quantity,
+			pq.Array(&pick.movement.Serials),
+		)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		movements = append(movements, pick)
+	}
+	rows.Close()
+
+	reference := fmt.Sprintf("outbound order %d", shipOrder.ID)
+	query = "UPDATE outbound_picks SET movement_id = $1 WHERE id = $2"
+	for _, pick := range movements {
+		movement := pick.movement
+		movement.Quantity = -movement.Quantity
+		movement.Reason = model.MovementReasonShipment
+		movement.Reference = reference
+		movement.UserID = &shipOrder.UserID
+		if len(movement.Serials) == 0 {
+			movement.Serials = nil
+		}
+
+		if err := applyMovement(ctx, tx, &movement, false); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, query, movement.ID, pick.pickID); err != nil {
+			return fmt.Errorf("could not link pick to movement: %w", err)
+		}
+	}
+
+	query = `UPDATE outbound_orders SET status = 'shipped', carrier = $1, tracking_number = $2,
+		shipped_at = NOW(), updated_at = NOW()
+	WHERE id = $3`
+	if _, err := tx.ExecContext(ctx, query, shipOrder.Carrier, shipOrder.TrackingNumber, shipOrder.ID); err != nil {
+		return fmt.Errorf("could not update outbound order status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// CancelOutboundOrder releases stock held by order that is not shipped yet
+func (r *Repository) CancelOutboundOrder(ctx context.Context, id int) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockOutboundOrder(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if status == model.OutboundOrderStatusShipped || status == model.OutboundOrderStatusCancelled {
+		return ErrOutboundOrderStatus
+	}
+
+	query := "UPDATE outbound_orders SET status = 'cancelled', updated_at = NOW() WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("could not cancel outbound order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,385 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+const purchaseOrderColumns = `id, supplier_id, warehouse_id, status, reference, user_id, expected_at, created_at, updated_at`
+
+func scanPurchaseOrder(row rowScanner) (model.PurchaseOrder, error) {
+	var order model.PurchaseOrder
+	err := row.Scan(
+		&order.ID,
+		&order.SupplierID,
+		&order.WarehouseID,
+		&order.Status,
+		&order.Reference,
+		&order.UserID,
+		&order.ExpectedAt,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	return order, err
+}
+
+func (r *Repository) CreatePurchaseOrder(ctx context.Context, createOrder dto.CreatePurchaseOrder) (*model.PurchaseOrder, error) {
+	orders, err := r.CreatePurchaseOrders(ctx, []dto.CreatePurchaseOrder{createOrder})
+	if err != nil {
+		return nil, err
+	}
+
+	return &orders[0], nil
+}
+
+// CreatePurchaseOrders creates draft orders in one transaction, line
+// prices and quantities must be already resolved by the caller
+func (r *Repository) CreatePurchaseOrders(ctx context.Context, createOrders []dto.CreatePurchaseOrder) ([]model.PurchaseOrder, error) {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	orders := make([]model.PurchaseOrder, 0, len(createOrders))
+	for _, createOrder := range createOrders {
+		order, err := insertPurchaseOrder(ctx, tx, createOrder)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, order)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return orders, nil
+}
+
+func insertPurchaseOrder(ctx context.Context, tx *sql.Tx, createOrder dto.CreatePurchaseOrder) (model.PurchaseOrder, error) {
+	query := `INSERT INTO purchase_orders(supplier_id, warehouse_id, reference, user_id)
+	VALUES ($1, $2, $3, $4)
+	RETURNING ` + purchaseOrderColumns
+
+	order, err := scanPurchaseOrder(tx.QueryRowContext(
+		ctx,
+		query,
+		createOrder.SupplierID,
+		createOrder.WarehouseID,
+		createOrder.Reference,
+		createOrder.UserID,
+	))
+	if err != nil {
+		return order, mapItemForeignKeyError(err, "could not create purchase order in db: %w")
+	}
+
+	for _, line := range createOrder.Lines {
+		orderLine, err := insertPurchaseOrderLine(ctx, tx, order.ID, line)
+		if err != nil {
+			return order, err
+		}
+
+		order.Lines = append(order.Lines, *orderLine)
+	}
+
+	return order, nil
+}
+
+func insertPurchaseOrderLine(ctx context.Context, tx *sql.Tx, orderID int, line dto.PurchaseOrderLine) (*model.PurchaseOrderLine, error) {
+	if err := lockActiveItem(ctx, tx, line.ItemID, "FOR SHARE"); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO purchase_order_lines(purchase_order_id, item_id, quantity, price)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id`
+
+	orderLine := model.PurchaseOrderLine{
+		PurchaseOrderID: orderID,
+		ItemID:          line.ItemID,
+		Quantity:        line.Quantity,
+		Price:           *line.Price,
+	}
+	err := tx.QueryRowContext(ctx, query, orderID, line.ItemID, line.Quantity, *line.Price).Scan(&orderLine.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrPurchaseOrderLineExists
+		}
+		return nil, mapItemForeignKeyError(err, "could not create purchase order line in db: %w")
+	}
+
+	return &orderLine, nil
+}
+
+func (r *Repository) GetPurchaseOrders(ctx context.Context, filter dto.PurchaseOrderFilter) ([]model.PurchaseOrder, error) {
+	query := "SELECT " + purchaseOrderColumns + " FROM purchase_orders"
+
+	var args []any
+	var conditions []string
+	if filter.SupplierID != nil {
+		args = append(args, *filter.SupplierID)
+		conditions = append(conditions, fmt.Sprintf("supplier_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Master.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not get purchase orders from db: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []model.PurchaseOrder
+	for rows.Next() {
+		order, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+func (r *Repository) GetPurchaseOrderByID(ctx context.Context, id int) (*model.PurchaseOrder, error) {
+	query := "SELECT " + purchaseOrderColumns + " FROM purchase_orders WHERE id = $1"
+
+	order, err := scanPurchaseOrder(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchPurchaseOrder
+		}
+		return nil, fmt.Errorf("could not get purchase order from db: %w", err)
+	}
+
+	query = `SELECT id, purchase_order_id, item_id, quantity, received_quantity, price
+	FROM purchase_order_lines WHERE purchase_order_id = $1
+	ORDER BY id`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not get purchase order lines from db: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line model.PurchaseOrderLine
+		err := rows.Scan(
+			&line.ID,
+			&line.PurchaseOrderID,
+			&line.ItemID,
+			&line.Quantity,
+			&line.ReceivedQuantity,
+			&line.Price,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		order.Lines = append(order.Lines, line)
+	}
+
+	return &order, nil
+}
+
+// lockPurchaseOrder locks order for change and returns its status
+func lockPurchaseOrder(ctx context.Context, tx *sql.Tx, id int) (string, error) {
+	query := "SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE"
+
+	var status string
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNoSuchPurchaseOrder
+		}
+		return "", fmt.Errorf("could not lock purchase order: %w", err)
+	}
+
+	return status, nil
+}
+
+func touchPurchaseOrder(ctx context.Context, tx *sql.Tx, id int) error {
+	query := "UPDATE purchase_orders SET updated_at = NOW() WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("could not update purchase order: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) AddPurchaseOrderLine(ctx context.Context, addLine dto.AddPurchaseOrderLine) (*model.PurchaseOrderLine, error) {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(ctx, tx, addLine.PurchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if status != model.PurchaseOrderStatusDraft {
+		return nil, ErrPurchaseOrderStatus
+	}
+
+	line, err := insertPurchaseOrderLine(ctx, tx, addLine.PurchaseOrderID, addLine.PurchaseOrderLine)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := touchPurchaseOrder(ctx, tx, addLine.PurchaseOrderID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return line, nil
+}
+
+func (r *Repository) DeletePurchaseOrderLine(ctx context.Context, orderID, lineID int) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if status != model.PurchaseOrderStatusDraft {
+		return ErrPurchaseOrderStatus
+	}
+
+	query := "DELETE FROM purchase_order_lines WHERE purchase_order_id = $1 AND id = $2"
+	result, err := tx.ExecContext(ctx, query, orderID, lineID)
+	if err != nil {
+		return fmt.Errorf("could not delete purchase order line: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchPurchaseOrderLine
+	}
+
+	if err := touchPurchaseOrder(ctx, tx, orderID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// SendPurchaseOrder moves draft to sent and expects
+// delivery after lead time of the supplier
+func (r *Repository) SendPurchaseOrder(ctx context.Context, id int) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if status != model.PurchaseOrderStatusDraft {
+		return ErrPurchaseOrderStatus
+	}
+
+	query := `UPDATE purchase_orders o SET
+		status = 'sent',
+		expected_at = CURRENT_DATE + s.lead_time_days,
+		updated_at = NOW()
+	FROM suppliers s
+	WHERE o.id = $1 AND s.id = o.supplier_id
+	AND EXISTS (SELECT 1 FROM purchase_order_lines WHERE purchase_order_id = o.id)`
+
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("could not send purchase order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrPurchaseOrderEmpty
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// ClosePurchaseOrder closes order in any open status,
+// closing a draft abandons it
+func (r *Repository) ClosePurchaseOrder(ctx context.Context, id int) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if status == model.PurchaseOrderStatusClosed {
+		return ErrPurchaseOrderStatus
+	}
+
+	query := "UPDATE purchase_orders SET status = 'closed', updated_at = NOW() WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("could not close purchase order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// GetOpenOrderedQuantity returns quantity of item ordered but not
+// received yet, in the warehouse or in all of them when it is nil
+func (r *Repository) GetOpenOrderedQuantity(ctx context.Context, itemID int, warehouseID *int) (float64, error) {
+	query := `SELECT COALESCE(SUM(GREATEST(l.quantity - l.received_quantity, 0)), 0)
+	FROM purchase_order_lines l
+	JOIN purchase_orders o ON o.id = l.purchase_order_id
+	WHERE l.item_id = $1 AND o.status <> 'closed'
+	AND ($2::INT IS NULL OR o.warehouse_id = $2)`
+
+	var quantity float64
+	if err := r.db.Master.QueryRowContext(ctx, query, itemID, warehouseID).Scan(&quantity); err != nil {
+		return 0, fmt.Errorf("could not get ordered quantity from db: %w", err)
+	}
+
+	return quantity, nil
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+const receiptColumns = `id, purchase_order_id, warehouse_id, location_id, reference, user_id, created_at`
+
+func scanReceipt(row rowScanner) (model.Receipt, error) {
+	var receipt model.Receipt
+	err := row.Scan(
+		&receipt.ID,
+		&receipt.PurchaseOrderID,
+		&receipt.WarehouseID,
+		&receipt.LocationID,
+		&receipt.Reference,
+		&receipt.UserID,
+		&receipt.CreatedAt,
+	)
+	return receipt, err
+}
+
+// orderLineState is order line locked while delivery is received
+type orderLineState struct {
+	ordered  float64
+	received float64
+	price    float64
+}
+
+// CreateReceipt puts accepted quantities into stock, adds them to
+// received quantities of order lines and moves order to partially
+// received or closed, all in one transaction under order lock
+func (r *Repository) CreateReceipt(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error) {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setCurrentUser(ctx, tx, createReceipt.UserID); err != nil {
+		return nil, err
+	}
+
+	status, err := lockPurchaseOrder(ctx, tx, createReceipt.PurchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if status != model.PurchaseOrderStatusSent && status != model.PurchaseOrderStatusPartiallyReceived {
+		return nil, ErrPurchaseOrderStatus
+	}
+
+	lines, err := lockPurchaseOrderLines(ctx, tx, createReceipt.PurchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO receipts(purchase_order_id, warehouse_id, location_id, reference, user_id)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING ` + receiptColumns
+
+	receipt, err := scanReceipt(tx.QueryRowContext(
+		ctx,
+		query,
+		createReceipt.PurchaseOrderID,
+		createReceipt.WarehouseID,
+		createReceipt.LocationID,
+		createReceipt.Reference,
+		createReceipt.UserID,
+	))
+	if err != nil {
+		return nil, mapItemForeignKeyError(err, "could not create receipt in db: %w")
+	}
+
+	reference := fmt.Sprintf("purchase order %d", createReceipt.PurchaseOrderID)
+	for _, receiptLine := range createReceipt.Lines {
+		line, ok := lines[receiptLine.PurchaseOrderLineID]
+		if !ok {
+			return nil, ErrNoSuchPurchaseOrderLine
+		}
+
+		var movementID *int64
+		if receiptLine.Quantity > 0 {
+			received := line.received + receiptLine.Quantity
+			if received > line.ordered*(1+createReceipt.OverDeliveryTolerance) {
+				return nil, fmt.Errorf("%w: line %d", ErrOverDelivery, receiptLine.PurchaseOrderLineID)
+			}
+			line.received = received
+			lines[receiptLine.PurchaseOrderLineID] = line
+
+			movement := &model.Movement{
+				ItemID:      receiptLine.ItemID,
+				WarehouseID: createReceipt.WarehouseID,
+				LocationID:  createReceipt.LocationID,
+				LotID:       receiptLine.LotID,
+				Quantity:    receiptLine.Quantity,
+				Serials:     receiptLine.Serials,
+				Reason:      model.MovementReasonReceipt,
+				Reference:   reference,
+				UserID:      &createReceipt.UserID,
+				UnitCost:    &line.price,
+			}
+			if err := applyMovement(ctx, tx, movement, false); err != nil {
+				return nil, err
+			}
+			movementID = &movement.ID
+
+			query = "UPDATE purchase_order_lines SET received_quantity = received_quantity + $1 WHERE id = $2"
+			if _, err := tx.ExecContext(ctx, query, receiptLine.Quantity, receiptLine.PurchaseOrderLineID); err != nil {
+				return nil, fmt.Errorf("could not update received quantity in db: %w", err)
+			}
+		}
+
+		query = `INSERT INTO receipt_lines(receipt_id, purchase_order_line_id, item_id, quantity, damaged_quantity, note, lot_id, movement_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+
+		savedLine := model.ReceiptLine{
+			ReceiptID:           receipt.ID,
+			PurchaseOrderLineID: receiptLine.PurchaseOrderLineID,
+			ItemID:              receiptLine.ItemID,
+			Quantity:            receiptLine.Quantity,
+			DamagedQuantity:     receiptLine.DamagedQuantity,
+			Note:                receiptLine.Note,
+			LotID:               receiptLine.LotID,
+			MovementID:          movementID,
+		}
+		err := tx.QueryRowContext(
+			ctx,
+			query,
+			receipt.ID,
+			receiptLine.PurchaseOrderLineID,
+			receiptLine.ItemID,
+			receiptLine.Quantity,
+			receiptLine.DamagedQuantity,
+			receiptLine.Note,
+			receiptLine.LotID,
+			movementID,
+		).Scan(&savedLine.ID)
+		if err != nil {
+			return nil, mapItemForeignKeyError(err, "could not create receipt line in db: %w")
+		}
+
+		receipt.Lines = append(receipt.Lines, savedLine)
+	}
+
+	receipt.PurchaseOrderStatus = model.PurchaseOrderStatusClosed
+	if !createReceipt.Close {
+		for _, line := range lines {
+			if line.received < line.ordered {
+				receipt.PurchaseOrderStatus = model.PurchaseOrderStatusPartiallyReceived
+				break
+			}
+		}
+	}
+
+	query = "UPDATE purchase_orders SET status = $1, updated_at = NOW() WHERE id = $2"
+	if _, err := tx.ExecContext(ctx, query, receipt.PurchaseOrderStatus, createReceipt.PurchaseOrderID); err != nil {
+		return nil, fmt.Errorf("could not update purchase order status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return &receipt, nil
+}
+
+func lockPurchaseOrderLines(ctx context.Context, tx *sql.Tx, orderID int) (map[int]orderLineState, error) {
+	query := `SELECT id, quantity, received_quantity, price FROM purchase_order_lines
+	WHERE purchase_order_id = $1 FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("could not lock purchase order lines: %w", err)
+	}
+	defer rows.Close()
+
+	lines := make(map[int]orderLineState)
+	for rows.Next() {
+		var id int
+		var line orderLineState
+		if err := rows.Scan(&id, &line.ordered, &line.received, &line.price); err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		lines[id] = line
+	}
+
+	return lines, rows.Err()
+}
+
+func (r *Repository) GetReceipts(ctx context.Context, filter dto.ReceiptFilter) ([]model.Receipt, error) {
+	query := `SELECT ` + receiptColumns + ` FROM receipts
+	WHERE $1::INT IS NULL OR purchase_order_id = $1
+	ORDER BY id`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, filter.PurchaseOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get receipts from db: %w", err)
+	}
+	defer rows.Close()
+
+	var receipts []model.Receipt
+	for rows.Next() {
+		receipt, err := scanReceipt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, nil
+}
+
+func (r *Repository) GetReceiptByID(ctx context.Context, id int) (*model.Receipt, error) {
+	query := "SELECT " + receiptColumns + " FROM receipts WHERE id = $1"
+
+	receipt, err := scanReceipt(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchReceipt
+		}
+		return nil, fmt.Errorf("could not get receipt from db: %w", err)
+	}
+
+	query = `SELECT id, receipt_id, purchase_order_line_id, item_id, quantity, damaged_quantity, note, lot_id, movement_id
+	FROM receipt_lines WHERE receipt_id = $1
+	ORDER BY id`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not get receipt lines from db: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line model.ReceiptLine
+		err := rows.Scan(
+			&line.ID,
+			&line.ReceiptID,
+			&line.PurchaseOrderLineID,
+			&line.ItemID,
+			&line.Quantity,
+			&line.DamagedQuantity,
+			&line.Note,
+			&line.LotID,
+			&line.MovementID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		receipt.Lines = append(receipt.Lines, line)
+	}
+
+	return &receipt, nil
+}
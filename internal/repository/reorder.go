@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+// raiseStockAlerts records alert for every reorder rule of item whose
+// reorder point lies between quantities before and after the movement
+func raiseStockAlerts(ctx context.Context, tx *sql.Tx, movement *model.Movement, warehouseQuantity, itemCount float64) error {
+	query := `INSERT INTO stock_alerts(item_id, warehouse_id, type, quantity, min_level, movement_id)
+	SELECT r.item_id, r.warehouse_id,
+		CASE WHEN q.after < r.min_level THEN 'low_stock' ELSE 'restocked' END,
+		q.after, r.min_level, $5
+	FROM reorder_rules r
+	CROSS JOIN LATERAL (
+		SELECT CASE WHEN r.warehouse_id IS NULL THEN $3::NUMERIC ELSE $4::NUMERIC END AS after
+	) q
+	WHERE r.item_id = $1 AND (r.warehouse_id IS NULL OR r.warehouse_id = $2)
+	AND (q.after < r.min_level) <> (q.after - $6::NUMERIC < r.min_level)`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		movement.ItemID,
+		movement.WarehouseID,
+		itemCount,
+		warehouseQuantity,
+		movement.ID,
+		movement.Quantity,
+	)
+	if err != nil {
+		return fmt.Errorf("could not raise stock alerts: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) SetReorderRule(ctx context.Context, setReorderRule dto.SetReorderRule) (*model.ReorderRule, error) {
+	query := `INSERT INTO reorder_rules(item_id, warehouse_id, min_level, max_level, reorder_quantity)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (item_id, COALESCE(warehouse_id, 0)) DO UPDATE SET
+		min_level = EXCLUDED.min_level,
+		max_level = EXCLUDED.max_level,
+		reorder_quantity = EXCLUDED.reorder_quantity
+	RETURNING id`
+
+	rule := model.ReorderRule{
+		ItemID:          setReorderRule.ItemID,
+		WarehouseID:     setReorderRule.WarehouseID,
+		MinLevel:        setReorderRule.MinLevel,
+		MaxLevel:        setReorderRule.MaxLevel,
+		ReorderQuantity: setReorderRule.ReorderQuantity,
+	}
+	err := r.db.Master.QueryRowContext(
+		ctx,
+		query,
+		setReorderRule.ItemID,
+		setReorderRule.WarehouseID,
+		setReorderRule.MinLevel,
+		setReorderRule.MaxLevel,
+		setReorderRule.ReorderQuantity,
+	).Scan(&rule.ID)
+	if err != nil {
+		return nil, mapItemForeignKeyError(err, "could not set reorder rule in db: %w")
+	}
+
+	return &rule, nil
+}
+
+func (r *Repository) GetReorderRules(ctx context.Context, itemID int) ([]model.ReorderRule, error) {
+	query := `SELECT id, item_id, warehouse_id, min_level, max_level, reorder_quantity
+	FROM reorder_rules WHERE item_id = $1
+	ORDER BY warehouse_id NULLS FIRST`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get reorder rules from db: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []model.ReorderRule
+	for rows.Next() {
+		var rule model.ReorderRule
+		err := rows.Scan(
+			&rule.ID,
+			&rule.ItemID,
+			&rule.WarehouseID,
+			&rule.MinLevel,
+			&rule.MaxLevel,
+			&rule.ReorderQuantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *Repository) DeleteReorderRule(ctx context.Context, itemID int, warehouseID *int) error {
+	query := "DELETE FROM reorder_rules WHERE item_id = $1 AND warehouse_id IS NOT DISTINCT FROM $2"
+
+	result, err := r.db.Master.ExecContext(ctx, query, itemID, warehouseID)
+	if err != nil {
+		return fmt.Errorf("could not delete reorder rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchReorderRule
+	}
+
+	return nil
+}
+
+// GetLowStock returns stock below reorder point of items that are
+// not archived, rules of other warehouses are skipped when warehouseID is given
+func (r *Repository) GetLowStock(ctx context.Context, warehouseID *int) ([]model.LowStockItem, error) {
+	query := `SELECT r.id, r.item_id, r.warehouse_id, r.min_level, r.max_level, r.reorder_quantity,
+		i.name, w.name, q.quantity
+	FROM reorder_rules r
+	JOIN items i ON i.id = r.item_id
+	LEFT JOIN warehouses w ON w.id = r.warehouse_id
+	CROSS JOIN LATERAL (
+		SELECT CASE WHEN r.warehouse_id IS NULL THEN i.count ELSE COALESCE((
+			SELECT quantity FROM stock WHERE item_id = r.item_id AND warehouse_id = r.warehouse_id
+		), 0) END AS quantity
+	) q
+	WHERE q.quantity < r.min_level AND i.deleted_at IS NULL AND ($1::INT IS NULL OR r.warehouse_id = $1)
+	ORDER BY i.name, r.warehouse_id NULLS FIRST`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, warehouseID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get low stock from db: %w", err)
+	}
+	defer rows.Close()
+
+	var items []model.LowStockItem
+	for rows.Next() {
+		var item model.LowStockItem
+		err := rows.Scan(
+			&item.ID,
+			&item.ItemID,
+			&item.WarehouseID,
+			&item.MinLevel,
+			&item.MaxLevel,
+			&item.ReorderQuantity,
+			&item.ItemName,
+			&item.WarehouseName,
+			&item.Quantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (r *Repository) GetStockAlerts(ctx context.Context, onlyActive bool) ([]model.StockAlert, error) {
+	query := `SELECT id, item_id, warehouse_id, type, quantity, min_level, movement_id, created_at, acknowledged_at
+	FROM stock_alerts
+	WHERE NOT $1 OR acknowledged_at IS NULL
+	ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, onlyActive)
+	if err != nil {
+		return nil, fmt.Errorf("could not get stock alerts from db: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []model.StockAlert
+	for rows.Next() {
+		var alert model.StockAlert
+		err := rows.Scan(
+			&alert.ID,
+			&alert.ItemID,
+			&alert.WarehouseID,
+			&alert.Type,
+			&alert.Quantity,
+			&alert.MinLevel,
+			&alert.MovementID,
+			&alert.CreatedAt,
+			&alert.AcknowledgedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (r *Repository) AcknowledgeStockAlert(ctx context.Context, id int64) error {
+	query := "UPDATE stock_alerts SET acknowledged_at = NOW() WHERE id = $1 AND acknowledged_at IS NULL"
+
+	result, err := r.db.Master.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("could not acknowledge stock alert: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchAlert
+	}
+
+	return nil
+}
@@ -2,13 +2,116 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/lib/pq"
 	"github.com/wb-go/wbf/dbpg"
 )
 
+const (
+	foreignKeyViolation pq.ErrorCode = "23503"
+	uniqueViolation     pq.ErrorCode = "23505"
+)
+
 var (
 	ErrNoSuchItem = errors.New("no item with such id")
 	ErrNoSuchUser = errors.New("no such user")
+	// ErrItemArchived means item is deleted and has to be restored first
+	ErrItemArchived    = errors.New("item is archived")
+	ErrItemNotArchived = errors.New("item is not archived")
+	// ErrItemInUse keeps item referenced by documents or stock from being purged
+	ErrItemInUse = errors.New("item is still in use")
+	// ErrVersionConflict means item was changed after client has read it
+	ErrVersionConflict = errors.New("item was changed by someone else, reload it and try again")
+	// ErrItemNameTaken comes wrapped in DuplicateNameError
+	ErrItemNameTaken = errors.New("item with such name already exists")
+	ErrItemMerged    = errors.New("item was merged into another item")
+	ErrMergeConflict = errors.New("items cannot be merged")
+
+	ErrNoSuchCategory      = errors.New("no category with such id")
+	ErrCategoryCycle       = errors.New("category cannot be moved under itself or its descendant")
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+
+	ErrNoSuchAttribute = errors.New("no attribute definition with such id")
+	ErrAttributeExists = errors.New("attribute with such name is already defined for category")
+
+	ErrNoSuchUnit = errors.New("no such unit of measure")
+	ErrUnitExists = errors.New("unit with such code already exists")
+
+	ErrNoSuchWarehouse   = errors.New("no warehouse with such id")
+	ErrWarehouseExists   = errors.New("warehouse with such name already exists")
+	ErrWarehouseHasStock = errors.New("warehouse still has stock")
+
+	ErrNoSuchLocation    = errors.New("no location with such id")
+	ErrLocationExists    = errors.New("location with such code already exists at this level")
+	ErrLocationNotEmpty  = errors.New("location still has stock or nested locations")
+	ErrLocationWarehouse = errors.New("location belongs to another warehouse")
+	ErrBelowLocatedStock = errors.New("warehouse quantity cannot be less than quantity put away to locations")
+
+	ErrInsufficientStock = errors.New("insufficient stock")
+
+	ErrNoSuchLot        = errors.New("no lot with such id")
+	ErrLotExists        = errors.New("lot with such number already exists for item")
+	ErrLotItem          = errors.New("lot belongs to another item")
+	ErrBelowLottedStock = errors.New("warehouse quantity cannot be less than quantity tracked in lots")
+
+	ErrNoSuchSerial    = errors.New("no such serial number for item")
+	ErrNotSerialized   = errors.New("item is not tracked by serial numbers")
+	ErrSerialsMismatch = errors.New("serial number must be given for every unit of serialized item")
+	ErrSerialState     = errors.New("serial number is not in state that allows this movement")
+	ErrSerializedStock = errors.New("serial tracking can be switched only for item without stock")
+
+	ErrNoSuchReorderRule = errors.New("no reorder rule for item in such warehouse")
+	ErrNoSuchAlert       = errors.New("no active stock alert with such id")
+
+	ErrNoSuchSupplier     = errors.New("no supplier with such id")
+	ErrSupplierExists     = errors.New("supplier with such name already exists")
+	ErrSupplierHasOrders  = errors.New("supplier has purchase orders")
+	ErrNoSuchSupplierItem = errors.New("item is not in price list of supplier")
+
+	ErrNoSuchPurchaseOrder     = errors.New("no purchase order with such id")
+	ErrNoSuchPurchaseOrderLine = errors.New("no such line in purchase order")
+	ErrPurchaseOrderLineExists = errors.New("item is already ordered in purchase order")
+	ErrPurchaseOrderStatus     = errors.New("purchase order is not in status that allows this change")
+	ErrPurchaseOrderEmpty      = errors.New("purchase order has no lines")
+	ErrOverDelivery            = errors.New("received quantity exceeds ordered quantity over allowed tolerance")
+
+	ErrNoSuchReceipt = errors.New("no receipt with such id")
+
+	ErrNoSuchOutboundOrder = errors.New("no outbound order with such id")
+	ErrNoSuchPick          = errors.New("no such pick in pick list of outbound order")
+	ErrOutboundOrderStatus = errors.New("outbound order is not in status that allows this change")
+
+	ErrNoSuchTransferOrder     = errors.New("no transfer order with such id")
+	ErrNoSuchTransferOrderLine = errors.New("no such line in transfer order")
+	ErrTransferOrderStatus     = errors.New("transfer order is not in status that allows this change")
+	ErrOverTransferReceipt     = errors.New("received quantity exceeds dispatched quantity")
+
+	ErrNoSuchStocktake     = errors.New("no stocktake with such id")
+	ErrStocktakeStatus     = errors.New("stocktake is not open")
+	ErrStocktakeScope      = errors.New("item or location is outside of stocktake scope")
+	ErrStocktakeIncomplete = errors.New("stocktake has lines that are not counted yet")
+	// ErrStocktakeTracked means item is counted by serial numbers or lots,
+	// which stocktakes do not support
+	ErrStocktakeTracked = errors.New("serialized and lot-tracked items cannot be counted in stocktake")
+
+	ErrNoSuchKit  = errors.New("item is not a kit")
+	ErrKitNesting = errors.New("kit cannot be a component of another kit")
+
+	ErrNoSuchAttachment = errors.New("no attachment with such id")
+
+	ErrNoSuchSavedView = errors.New("no saved view with such id")
+	ErrSavedViewExists = errors.New("saved view with such name already exists")
+
+	ErrNoSuchChangeRequest = errors.New("no change request with such id")
+	ErrChangeRequestStatus = errors.New("change request is already reviewed")
+
+	ErrNoSuchReservation     = errors.New("no reservation with such id")
+	ErrReservationNotActive  = errors.New("reservation is not active anymore")
+	// ErrInsufficientAvailable means stock is there but held by
+	// reservations or outbound orders
+	ErrInsufficientAvailable = errors.New("not enough available stock, the rest is reserved")
 )
 
 type Repository struct {
@@ -20,3 +123,40 @@ func New(db *dbpg.DB) *Repository {
 		db: db,
 	}
 }
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// mapItemForeignKeyError turns violated item references into
+// sentinel errors and wraps any other error with format
+func mapItemForeignKeyError(err error, format string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		switch {
+		case strings.HasSuffix(pqErr.Constraint, "category_id_fkey"):
+			return ErrNoSuchCategory
+		case strings.HasSuffix(pqErr.Constraint, "unit_fkey"):
+			return ErrNoSuchUnit
+		case strings.HasSuffix(pqErr.Constraint, "item_id_fkey"):
+			return ErrNoSuchItem
+		case strings.HasSuffix(pqErr.Constraint, "lot_id_fkey"):
+			return ErrNoSuchLot
+		case strings.HasSuffix(pqErr.Constraint, "warehouse_id_fkey"):
+			return ErrNoSuchWarehouse
+		case strings.HasSuffix(pqErr.Constraint, "supplier_id_fkey"):
+			return ErrNoSuchSupplier
+		case strings.HasSuffix(pqErr.Constraint, "user_id_fkey"),
+			strings.HasSuffix(pqErr.Constraint, "changed_by_id_fkey"),
+			strings.HasSuffix(pqErr.Constraint, "reviewed_by_fkey"):
+			return ErrNoSuchUser
+		}
+	}
+	return fmt.Errorf(format, err)
+}
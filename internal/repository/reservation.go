@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+// reservedQuantity is lateral subquery of quantity held by active
+// reservations and open outbound orders for stock row aliased as s,
+// expired reservations not swept yet are already ignored
+const reservedQuantity = `SELECT COALESCE(SUM(quantity), 0) AS reserved FROM (
+		SELECT quantity FROM reservations
+		WHERE item_id = s.item_id AND warehouse_id = s.warehouse_id
+		AND status = 'active' AND expires_at > NOW()
+		UNION ALL
+		SELECT ol.quantity FROM outbound_order_lines ol
+		JOIN outbound_orders o ON o.id = ol.order_id
+		WHERE ol.item_id = s.item_id AND o.warehouse_id = s.warehouse_id
+		AND o.status IN ('new', 'picking', 'packed')
+	) held`
+
+const reservationColumns = `id, item_id, warehouse_id, quantity, reference, status, user_id, expires_at, created_at, updated_at`
+
+func scanReservation(row rowScanner) (model.Reservation, error) {
+	var reservation model.Reservation
+	err := row.Scan(
+		&reservation.ID,
+		&reservation.ItemID,
+		&reservation.WarehouseID,
+		&reservation.Quantity,
+		&reservation.Reference,
+		&reservation.Status,
+		&reservation.UserID,
+		&reservation.ExpiresAt,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	)
+	return reservation, err
+}
+
+func (r *Repository) CreateReservation(ctx context.Context, createReservation dto.CreateReservation) (*model.Reservation, error) {
+	tx, err := r.db.Master.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockActiveItem(ctx, tx, createReservation.ItemID, "FOR SHARE"); err != nil {
+		return nil, err
+	}
+
+	available, err := lockAvailableStock(ctx, tx, createReservation.ItemID, createReservation.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	if available < createReservation.Quantity {
+		return nil, ErrInsufficientAvailable
+	}
+
+	query := `INSERT INTO reservations(item_id, warehouse_id, quantity, reference, user_id, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING ` + reservationColumns
+
+	reservation, err := scanReservation(tx.QueryRowContext(
+		ctx,
+		query,
+		createReservation.ItemID,
+		createReservation.WarehouseID,
+		createReservation.Quantity,
+		createReservation.Reference,
+		createReservation.UserID,
+		*createReservation.ExpiresAt,
+	))
+	if err != nil {
+		return nil, mapItemForeignKeyError(err, "could not create reservation in db: %w")
+	}
+
+	if len(createReservation.Serials) > 0 {
+		if err := holdSerials(ctx, tx, &reservation, createReservation.Serials); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return &reservation, nil
+}
+
+// lockAvailableStock returns quantity of item in the warehouse that is
+// not held yet, stock row lock serializes holds of the same item in
+// the warehouse so available quantity cannot be promised twice
+func lockAvailableStock(ctx context.Context, tx *sql.Tx, itemID, warehouseID int) (float64, error) {
+	query := `SELECT s.quantity - r.reserved FROM stock s
+	CROSS JOIN LATERAL (` + reservedQuantity + `) r
+	WHERE s.item_id = $1 AND s.warehouse_id = $2
+	FOR UPDATE OF s`
+
+	var available float64
+	err := tx.QueryRowContext(ctx, query, itemID, warehouseID).Scan(&available)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("could not get available stock from db: %w", err)
+	}
+
+	return available, nil
+}
+
+func (r *Repository) GetReservations(ctx context.Context, filter dto.ReservationFilter) ([]model.Reservation, error) {
+	query := "SELECT " + reservationColumns + " FROM reservations"
+
+	var args []any
+	var conditions []string
+	if filter.ItemID != nil {
+		args = append(args, *filter.ItemID)
+		conditions = append(conditions, fmt.Sprintf("item_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Master.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not get reservations from db: %w", err)
+	}
+	defer rows.Close()
+
+	var reservations []model.Reservation
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, nil
+}
+
+// lockActiveReservation locks reservation for status change,
+// reservation past its expiry is not active even if not swept yet
+func lockActiveReservation(ctx context.Context, tx *sql.Tx, id int) (*model.Reservation, error) {
+	query := `SELECT item_id, warehouse_id, quantity, reference, status = 'active' AND expires_at > NOW()
+	FROM reservations WHERE id = $1 FOR UPDATE`
+
+	reservation := model.Reservation{ID: id}
+	var active bool
+	err := tx.QueryRowContext(ctx, query, id).Scan(
+		&reservation.ItemID,
+		&reservation.WarehouseID,
+		&reservation.Quantity,
+		&reservation.Reference,
+		&active,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchReservation
+		}
+		return nil, fmt.Errorf("could not get reservation from db: %w", err)
+	}
+
+	if !active {
+		return nil, ErrReservationNotActive
+	}
+
+	return &reservation, nil
+}
+
+func setReservationStatus(ctx context.Context, tx *sql.Tx, id int, status string) error {
+	query := "UPDATE reservations SET status = $1, updated_at = NOW() WHERE id = $2"
+	if _, err := tx.ExecContext(ctx, query, status, id); err != nil {
+		return fmt.Errorf("could not update reservation status in db: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmReservation ships reserved stock: on-hand quantity is
+// reduced by reservation movement and reservation is closed
+func (r *Repository) ConfirmReservation(ctx context.Context, confirmReservation dto.ConfirmReservation) error {
+	tx, err := r.db.Master.Begin()
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	reservation, err := lockActiveReservation(ctx, tx, confirmReservation.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := setCurrentUser(ctx, tx, confirmReservation.UserID); err != nil {
+		return err
+	}
+
+	// units held by reservation are the ones shipped
+	serials, err := freeSerials(ctx, tx, reservation.ID)
+	if err != nil {
+		return err
+	}
+	if len(serials) == 0 {
+		serials = confirmReservation.Serials
+	}
+
+	err = applyMovement(ctx, tx, &model.Movement{
+		ItemID:      reservation.ItemID,
+		WarehouseID: reservation.WarehouseID,
+		LotID:       confirmReservation.LotID,
+		Quantity:    -reservation.Quantity,
+		Reason:      model.MovementReasonReservation,
+		Reference:   reservation.Reference,
+		UserID:      &confirmReservation.UserID,
+		Serials:     serials,
+	}, false)
+	if err != nil {
+		return err
+	}
+
+	if err := setReservationStatus(ctx, tx, reservation.ID, model.ReservationStatusConfirmed); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ReleaseReservation(ctx context.Context, id int) error {
+	tx, err := r.db.Master.Begin()
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockActiveReservation(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if _, err := freeSerials(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err := setReservationStatus(ctx, tx, id, model.ReservationStatusReleased); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// ExpireReservations releases every active reservation past its
+// expiry together with serials it has held
+func (r *Repository) ExpireReservations(ctx context.Context) (int64, error) {
+	query := `WITH expired AS (
+		UPDATE reservations SET status = 'expired', updated_at = NOW()
+		WHERE status = 'active' AND expires_at <= NOW()
+		RETURNING id
+	), freed AS (
+		UPDATE serials SET status = 'in_stock', reservation_id = NULL, updated_at = NOW()
+		WHERE reservation_id IN (SELECT id FROM expired)
+	)
+	SELECT COUNT(*) FROM expired`
+
+	var expired int64
+	if err := r.db.Master.QueryRowContext(ctx, query).Scan(&expired); err != nil {
+		return 0, fmt.Errorf("could not expire reservations: %w", err)
+	}
+
+	return expired, nil
+}
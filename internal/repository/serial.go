@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+
+	"github.com/lib/pq"
+)
+
+func isSerialized(ctx context.Context, tx *sql.Tx, itemID int) (bool, error) {
+	var serialized bool
+	query := "SELECT serialized FROM items WHERE id = $1"
+	if err := tx.QueryRowContext(ctx, query, itemID).Scan(&serialized); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrNoSuchItem
+		}
+		return false, fmt.Errorf("could not get item from db: %w", err)
+	}
+
+	return serialized, nil
+}
+
+// checkSerialCount makes sure every unit of quantity has its own serial
+func checkSerialCount(serials []string, quantity float64) error {
+	if float64(len(serials)) != math.Abs(quantity) {
+		return ErrSerialsMismatch
+	}
+
+	seen := make(map[string]bool, len(serials))
+	for _, serial := range serials {
+		if seen[serial] {
+			return ErrSerialsMismatch
+		}
+		seen[serial] = true
+	}
+
+	return nil
+}
+
+// moveSerials brings serials named by movement of serialized item into
+// stock or takes them out, so they always match quantity on hand, ids
+// of moved serials are returned to link them with the movement
+func moveSerials(ctx context.Context, tx *sql.Tx, movement *model.Movement) ([]int, error) {
+	serialized, err := isSerialized(ctx, tx, movement.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if !serialized {
+		if len(movement.Serials) > 0 {
+			return nil, ErrNotSerialized
+		}
+		return nil, nil
+	}
+
+	if err := checkSerialCount(movement.Serials, movement.Quantity); err != nil {
+		return nil, err
+	}
+
+	var query string
+	switch {
+	case movement.Reason == model.MovementReasonTransferIn:
+		// unit arriving by transfer keeps its history and is only rehomed
+		query = `UPDATE serials SET status = 'in_stock', warehouse_id = $3, updated_at = NOW()
+		WHERE item_id = $1 AND serial_number = $2 AND status = 'in_transit'
+		RETURNING id`
+	case movement.Quantity > 0:
+		// unit seen before may only come back after it was shipped
+		query = `INSERT INTO serials(item_id, serial_number, status, warehouse_id) VALUES ($1, $2, 'in_stock', $3)
+		ON CONFLICT (item_id, serial_number) DO UPDATE SET
+			status = 'returned',
+			warehouse_id = EXCLUDED.warehouse_id,
+			updated_at = NOW()
+		WHERE serials.status = 'shipped'
+		RETURNING id`
+	case movement.Reason == model.MovementReasonTransferOut:
+		query = `UPDATE serials SET status = 'in_transit', updated_at = NOW()
+		WHERE item_id = $1 AND serial_number = $2 AND warehouse_id = $3
+		AND status IN ('in_stock', 'returned')
+		RETURNING id`
+	default:
+		query = `UPDATE serials SET status = 'shipped', updated_at = NOW()
+		WHERE item_id = $1 AND serial_number = $2 AND warehouse_id = $3
+		AND status IN ('in_stock', 'returned')
+		RETURNING id`
+	}
+
+	serialIDs := make([]int, 0, len(movement.Serials))
+	for _, serial := range movement.Serials {
+		var id int
+		err := tx.QueryRowContext(ctx, query, movement.ItemID, serial, movement.WarehouseID).Scan(&id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: %s", ErrSerialState, serial)
+			}
+			return nil, fmt.Errorf("could not move serial in db: %w", err)
+		}
+		serialIDs = append(serialIDs, id)
+	}
+
+	return serialIDs, nil
+}
+
+func linkSerials(ctx context.Context, tx *sql.Tx, movementID int64, serialIDs []int) error {
+	if len(serialIDs) == 0 {
+		return nil
+	}
+
+	query := "INSERT INTO movement_serials(movement_id, serial_id) SELECT $1, UNNEST($2::INT[])"
+	if _, err := tx.ExecContext(ctx, query, movementID, pq.Array(serialIDs)); err != nil {
+		return fmt.Errorf("could not link serials to movement: %w", err)
+	}
+
+	return nil
+}
+
+// holdSerials marks exact units of serialized item as reserved
+func holdSerials(ctx context.Context, tx *sql.Tx, reservation *model.Reservation, serials []string) error {
+	serialized, err := isSerialized(ctx, tx, reservation.ItemID)
+	if err != nil {
+		return err
+	}
+	if !serialized {
+		return ErrNotSerialized
+	}
+
+	if err := checkSerialCount(serials, reservation.Quantity); err != nil {
+		return err
+	}
+
+	query := `UPDATE serials SET status = 'reserved', reservation_id = $1, updated_at = NOW()
+	WHERE item_id = $2 AND warehouse_id = $3 AND serial_number = ANY($4)
+	AND status IN ('in_stock', 'returned')`
+
+	result, err := tx.ExecContext(ctx, query, reservation.ID, reservation.ItemID, reservation.WarehouseID, pq.Array(serials))
+	if err != nil {
+		return fmt.Errorf("could not reserve serials in db: %w", err)
+	}
+
+	held, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+	if held != int64(len(serials)) {
+		return ErrSerialState
+	}
+
+	return nil
+}
+
+// freeSerials returns units held by reservation back to stock
+// and reports their serial numbers
+func freeSerials(ctx context.Context, tx *sql.Tx, reservationID int) ([]string, error) {
+	query := `UPDATE serials SET status = 'in_stock', reservation_id = NULL, updated_at = NOW()
+	WHERE reservation_id = $1
+	RETURNING serial_number`
+
+	rows, err := tx.QueryContext(ctx, query, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("could not free reserved serials in db: %w", err)
+	}
+	defer rows.Close()
+
+	var serials []string
+	for rows.Next() {
+		var serial string
+		if err := rows.Scan(&serial); err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+		serials = append(serials, serial)
+	}
+
+	return serials, rows.Err()
+}
+
+const serialColumns = `id, item_id, serial_number, status, warehouse_id, reservation_id, created_at, updated_at`
+
+func scanSerial(row rowScanner) (model.Serial, error) {
+	var serial model.Serial
+	err := row.Scan(
+		&serial.ID,
+		&serial.ItemID,
+		&serial.SerialNumber,
+		&serial.Status,
+		&serial.WarehouseID,
+		&serial.ReservationID,
+		&serial.CreatedAt,
+		&serial.UpdatedAt,
+	)
+	return serial, err
+}
+
+func (r *Repository) GetSerials(ctx context.Context, filter dto.SerialFilter) ([]model.Serial, error) {
+	query := "SELECT " + serialColumns + ` FROM serials
+	WHERE item_id = $1 AND ($2 = '' OR status = $2)
+	ORDER BY serial_number`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, filter.ItemID, filter.Status)
+	if err != nil {
+		return nil, fmt.Errorf("could not get serials from db: %w", err)
+	}
+	defer rows.Close()
+
+	var serials []model.Serial
+	for rows.Next() {
+		serial, err := scanSerial(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		serials = append(serials, serial)
+	}
+
+	return serials, nil
+}
+
+func (r *Repository) GetSerialHistory(ctx context.Context, itemID int, serialNumber string) (*model.SerialHistory, error) {
+	query := "SELECT " + serialColumns + " FROM serials WHERE item_id = $1 AND serial_number = $2"
+
+	serial, err := scanSerial(r.db.Master.QueryRowContext(ctx, query, itemID, serialNumber))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchSerial
+		}
+		return nil, fmt.Errorf("could not get serial from db: %w", err)
+	}
+
+	query = "SELECT " + movementColumns + ` FROM stock_movements m
+	JOIN movement_serials ms ON ms.movement_id = m.id
+	WHERE ms.serial_id = $1
+	ORDER BY m.created_at, m.id`
+
+	rows, err := r.db.Master.QueryContext(ctx, query, serial.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get serial movements from db: %w", err)
+	}
+	defer rows.Close()
+
+	history := &model.SerialHistory{Serial: serial, Movements: []model.Movement{}}
+	for rows.Next() {
+		movement, err := scanMovement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		history.Movements = append(history.Movements, movement)
+	}
+
+	return history, nil
+}
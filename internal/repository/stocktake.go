@@ -0,0 +1,446 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+const stocktakeColumns = `id, warehouse_id, location_id, category_id, note, status, user_id, approved_by,
+	created_at, updated_at, approved_at`
+
+func scanStocktake(row rowScanner) (model.Stocktake, error) {
+	var stocktake model.Stocktake
+	err := row.Scan(
+		&stocktake.ID,
+		&stocktake.WarehouseID,
+		&stocktake.LocationID,
+		&stocktake.CategoryID,
+		&stocktake.Note,
+		&stocktake.Status,
+		&stocktake.UserID,
+		&stocktake.ApprovedBy,
+		&stocktake.CreatedAt,
+		&stocktake.UpdatedAt,
+		&stocktake.ApprovedAt,
+	)
+	return stocktake, err
+}
+
+// locationSubtree and categorySubtree select ids of node given
+// as $N and all nodes below it, used to test stocktake scope
+const (
+	locationSubtree = `WITH RECURSIVE subtree AS (
+		SELECT id FROM locations WHERE id = $%[1]d
+		UNION ALL
+		SELECT l.id FROM locations l JOIN subtree s ON l.parent_id = s.id
+	)
+	SELECT id FROM subtree`
+	categorySubtree = `WITH RECURSIVE subtree AS (
+		SELECT id FROM categories WHERE id = $%[1]d
+		UNION ALL
+		SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+	)
+	SELECT id FROM subtree`
+)
+
+// CreateStocktake freezes expected quantities of every item in scope.
+// Stocktake lines are not keyed by serial number or lot, so serialized
+// and lot-tracked items are not supported and their counts are refused
+// with ErrStocktakeTracked, archived items are not counted at all
+func (r *Repository) CreateStocktake(ctx context.Context, createStocktake dto.CreateStocktake) (*model.Stocktake, error) {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO stocktakes(warehouse_id, location_id, category_id, note, user_id)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING ` + stocktakeColumns
+
+	stocktake, err := scanStocktake(tx.QueryRowContext(
+		ctx,
+		query,
+		createStocktake.WarehouseID,
+		createStocktake.LocationID,
+		createStocktake.CategoryID,
+		createStocktake.Note,
+		createStocktake.UserID,
+	))
+	if err != nil {
+		return nil, mapItemForeignKeyError(err, "could not create stocktake in db: %w")
+	}
+
+	query = `INSERT INTO stocktake_lines(stocktake_id, item_id, location_id, expected_quantity)
+	SELECT $1, ls.item_id, ls.location_id, ls.quantity
+	FROM location_stock ls
+	JOIN locations l ON l.id = ls.location_id
+	JOIN items i ON i.id = ls.item_id
+	WHERE l.warehouse_id = $2 AND ls.quantity <> 0 AND NOT i.serialized AND i.deleted_at IS NULL
+	AND NOT EXISTS(SELECT 1 FROM lots WHERE item_id = i.id)
+	AND ($3::INT IS NULL OR ls.location_id IN (` + fmt.Sprintf(locationSubtree, 3) + `))
+	AND ($4::INT IS NULL OR i.category_id IN (` + fmt.Sprintf(categorySubtree, 4) + `))`
+
+	_, err = tx.ExecContext(ctx, query, stocktake.ID, stocktake.WarehouseID, stocktake.LocationID, stocktake.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("could not snapshot located stock: %w", err)
+	}
+
+	if stocktake.LocationID == nil {
+		query = `INSERT INTO stocktake_lines(stocktake_id, item_id, location_id, expected_quantity)
+		SELECT $1, item_id, NULL, unlocated FROM (
+			SELECT s.item_id, s.quantity - COALESCE((
+				SELECT SUM(ls.quantity) FROM location_stock ls
+				JOIN locations l ON l.id = ls.location_id
+				WHERE ls.item_id = s.item_id AND l.warehouse_id = s.warehouse_id
+			), 0) AS unlocated
+			FROM stock s JOIN items i ON i.id = s.item_id
+			WHERE s.warehouse_id = $2 AND NOT i.serialized AND i.deleted_at IS NULL
+			AND NOT EXISTS(SELECT 1 FROM lots WHERE item_id = i.id)
+			AND ($3::INT IS NULL OR i.category_id IN (` + fmt.Sprintf(categorySubtree, 3) + `))
+		) unlocated_stock
+		WHERE unlocated <> 0`
+
+		_, err = tx.ExecContext(ctx, query, stocktake.ID, stocktake.WarehouseID, stocktake.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("could not snapshot unlocated stock: %w", err)
+		}
+	}
+
+	lines, err := getStocktakeLines(ctx, tx, stocktake.ID)
+	if err != nil {
+		return nil, err
+	}
+	stocktake.Lines = lines
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return &stocktake, nil
+}
+
+func (r *Repository) GetStocktakes(ctx context.Context, filter dto.StocktakeFilter) ([]model.Stocktake, error) {
+	query := "SELECT " + stocktakeColumns + " FROM stocktakes"
+
+	var args []any
+	var conditions []string
+	if filter.WarehouseID != nil {
+		args = append(args, *filter.WarehouseID)
+		conditions = append(conditions, fmt.Sprintf("warehouse_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Master.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not get stocktakes from db: %w", err)
+	}
+	defer rows.Close()
+
+	var stocktakes []model.Stocktake
+	for rows.Next() {
+		stocktake, err := scanStocktake(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		stocktakes = append(stocktakes, stocktake)
+	}
+
+	return stocktakes, nil
+}
+
+func (r *Repository) GetStocktakeByID(ctx context.Context, id int) (*model.Stocktake, error) {
+	query := "SELECT " + stocktakeColumns + " FROM stocktakes WHERE id = $1"
+
+	stocktake, err := scanStocktake(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchStocktake
+		}
+		return nil, fmt.Errorf("could not get stocktake from db: %w", err)
+	}
+
+	lines, err := getStocktakeLines(ctx, r.db.Master, id)
+	if err != nil {
+		return nil, err
+	}
+	stocktake.Lines = lines
+
+	return &stocktake, nil
+}
+
+type rowsQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// getStocktakeLines returns lines in walking order of the warehouse
+func getStocktakeLines(ctx context.Context, q rowsQuerier, stocktakeID int) ([]model.StocktakeLine, error) {
+	query := locationPaths + `
+	SELECT sl.id, sl.stocktake_id, sl.item_id, i.name, sl.location_id, COALESCE(p.path, ''),
+		sl.expected_quantity, sl.counted_quantity, sl.counted_quantity - sl.expected_quantity,
+		sl.counted_by, sl.counted_at, sl.movement_id
+	FROM stocktake_lines sl
+	JOIN items i ON i.id = sl.item_id
+	LEFT JOIN paths p ON p.id = sl.location_id
+	WHERE sl.stocktake_id = $1
+	ORDER BY sl.location_id IS NULL, p.path, i.name, sl.id`
+
+	rows, err := q.QueryContext(ctx, query, stocktakeID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get stocktake lines from db: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []model.StocktakeLine
+	for rows.Next() {
+		var line model.StocktakeLine
+		err := rows.Scan(
+			&line.ID,
+			&line.StocktakeID,
+			&line.ItemID,
+			&line.ItemName,
+			&line.LocationID,
+			&line.Path,
+			&line.ExpectedQuantity,
+			&line.CountedQuantity,
+			&line.Variance,
+			&line.CountedBy,
+			&line.CountedAt,
+			&line.MovementID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func lockOpenStocktake(ctx context.Context, tx *sql.Tx, id int) (*model.Stocktake, error) {
+	query := "SELECT " + stocktakeColumns + " FROM stocktakes WHERE id = $1 FOR UPDATE"
+
+	stocktake, err := scanStocktake(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchStocktake
+		}
+		return nil, fmt.Errorf("could not lock stocktake: %w", err)
+	}
+	if stocktake.Status != model.StocktakeStatusOpen {
+		return nil, ErrStocktakeStatus
+	}
+
+	return &stocktake, nil
+}
+
+// RecordStocktakeCounts stores counts of any number of counters, count of
+// item at location not in snapshot adds a line expected to be empty
+func (r *Repository) RecordStocktakeCounts(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stocktake, err := lockOpenStocktake(ctx, tx, recordCounts.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, count := range recordCounts.Counts {
+		query := `SELECT id FROM stocktake_lines
+		WHERE stocktake_id = $1 AND item_id = $2 AND COALESCE(location_id, 0) = COALESCE($3, 0)`
+
+		var lineID int
+		err := tx.QueryRowContext(ctx, query, stocktake.ID, count.ItemID, count.LocationID).Scan(&lineID)
+		if errors.Is(err, sql.ErrNoRows) {
+			if err := checkStocktakeScope(ctx, tx, stocktake, count.ItemID, count.LocationID); err != nil {
+				return err
+			}
+
+			query = `INSERT INTO stocktake_lines(stocktake_id, item_id, location_id, expected_quantity)
+			VALUES ($1, $2, $3, 0)
+			RETURNING id`
+			err = tx.QueryRowContext(ctx, query, stocktake.ID, count.ItemID, count.LocationID).Scan(&lineID)
+			if err != nil {
+				return mapItemForeignKeyError(err, "could not create stocktake line in db: %w")
+			}
+		} else if err != nil {
+			return fmt.Errorf("could not get stocktake line from db: %w", err)
+		}
+
+		query = `UPDATE stocktake_lines SET counted_quantity = $1, counted_by = $2, counted_at = NOW()
+		WHERE id = $3`
+		if _, err := tx.ExecContext(ctx, query, count.Quantity, recordCounts.UserID, lineID); err != nil {
+			return fmt.Errorf("could not update counted quantity in db: %w", err)
+		}
+
+		query = "INSERT INTO stocktake_counts(line_id, user_id, quantity) VALUES ($1, $2, $3)"
+		if _, err := tx.ExecContext(ctx, query, lineID, recordCounts.UserID, count.Quantity); err != nil {
+			return mapItemForeignKeyError(err, "could not create stocktake count in db: %w")
+		}
+	}
+
+	query := "UPDATE stocktakes SET updated_at = NOW() WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, stocktake.ID); err != nil {
+		return fmt.Errorf("could not update stocktake: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// checkStocktakeScope tells whether item found at location may be counted
+func checkStocktakeScope(ctx context.Context, tx *sql.Tx, stocktake *model.Stocktake, itemID int, locationID *int) error {
+	query := `SELECT serialized OR EXISTS(SELECT 1 FROM lots WHERE item_id = items.id),
+		deleted_at IS NULL AND ($2::INT IS NULL OR category_id IN (` + fmt.Sprintf(categorySubtree, 2) + `))
+	FROM items WHERE id = $1`
+
+	var tracked, inScope bool
+	if err := tx.QueryRowContext(ctx, query, itemID, stocktake.CategoryID).Scan(&tracked, &inScope); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoSuchItem
+		}
+		return fmt.Errorf("could not check item scope: %w", err)
+	}
+	if tracked {
+		return fmt.Errorf("%w: item %d", ErrStocktakeTracked, itemID)
+	}
+	if !inScope {
+		return ErrStocktakeScope
+	}
+
+	if locationID == nil {
+		if stocktake.LocationID != nil {
+			return ErrStocktakeScope
+		}
+		return nil
+	}
+
+	query = `SELECT warehouse_id = $2 AND ($3::INT IS NULL OR id IN (` + fmt.Sprintf(locationSubtree, 3) + `))
+	FROM locations WHERE id = $1`
+
+	if err := tx.QueryRowContext(ctx, query, *locationID, stocktake.WarehouseID, stocktake.LocationID).Scan(&inScope); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoSuchLocation
+		}
+		return fmt.Errorf("could not check location scope: %w", err)
+	}
+	if !inScope {
+		return ErrStocktakeScope
+	}
+
+	return nil
+}
+
+// ApproveStocktake posts variance of every counted line as stocktake
+// movement, stock moved after snapshot is kept as is
+func (r *Repository) ApproveStocktake(ctx context.Context, approveStocktake dto.ApproveStocktake) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setCurrentUser(ctx, tx, approveStocktake.UserID); err != nil {
+		return err
+	}
+
+	stocktake, err := lockOpenStocktake(ctx, tx, approveStocktake.ID)
+	if err != nil {
+		return err
+	}
+
+	lines, err := getStocktakeLines(ctx, tx, stocktake.ID)
+	if err != nil {
+		return err
+	}
+
+	uncounted := 0
+	for _, line := range lines {
+		if line.CountedQuantity == nil {
+			uncounted++
+		}
+	}
+	if uncounted > 0 {
+		return fmt.Errorf("%w: %d lines left", ErrStocktakeIncomplete, uncounted)
+	}
+
+	reference := fmt.Sprintf("stocktake %d", stocktake.ID)
+	query := "UPDATE stocktake_lines SET movement_id = $1 WHERE id = $2"
+	for _, line := range lines {
+		if *line.Variance == 0 {
+			continue
+		}
+
+		movement := &model.Movement{
+			ItemID:      line.ItemID,
+			WarehouseID: stocktake.WarehouseID,
+			LocationID:  line.LocationID,
+			Quantity:    *line.Variance,
+			Reason:      model.MovementReasonStocktake,
+			Reference:   reference,
+			UserID:      &approveStocktake.UserID,
+		}
+		if err := applyMovement(ctx, tx, movement, false); err != nil {
+			return fmt.Errorf("%w: item %d", err, line.ItemID)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, movement.ID, line.ID); err != nil {
+			return fmt.Errorf("could not link stocktake line to movement: %w", err)
+		}
+	}
+
+	query = `UPDATE stocktakes SET status = 'approved', approved_by = $1, approved_at = NOW(), updated_at = NOW()
+	WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, approveStocktake.UserID, stocktake.ID); err != nil {
+		return fmt.Errorf("could not approve stocktake: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) CancelStocktake(ctx context.Context, id int) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOpenStocktake(ctx, tx, id); err != nil {
+		return err
+	}
+
+	query := "UPDATE stocktakes SET status = 'cancelled', updated_at = NOW() WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("could not cancel stocktake: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+const supplierColumns = `id, name, contact_name, email, phone, lead_time_days, created_at`
+
+func scanSupplier(row rowScanner) (model.Supplier, error) {
+	var supplier model.Supplier
+	err := row.Scan(
+		&supplier.ID,
+		&supplier.Name,
+		&supplier.ContactName,
+		&supplier.Email,
+		&supplier.Phone,
+		&supplier.LeadTimeDays,
+		&supplier.CreatedAt,
+	)
+	return supplier, err
+}
+
+func (r *Repository) CreateSupplier(ctx context.Context, createSupplier dto.CreateSupplier) (*model.Supplier, error) {
+	query := `INSERT INTO suppliers(name, contact_name, email, phone, lead_time_days) VALUES ($1, $2, $3, $4, $5)
+	RETURNING ` + supplierColumns
+
+	supplier, err := scanSupplier(r.db.Master.QueryRowContext(
+		ctx,
+		query,
+		createSupplier.Name,
+		createSupplier.ContactName,
+		createSupplier.Email,
+		createSupplier.Phone,
+		createSupplier.LeadTimeDays,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrSupplierExists
+		}
+		return nil, fmt.Errorf("could not create supplier: %w", err)
+	}
+
+	return &supplier, nil
+}
+
+func (r *Repository) GetSuppliers(ctx context.Context) ([]model.Supplier, error) {
+	query := "SELECT " + supplierColumns + " FROM suppliers ORDER BY name"
+
+	rows, err := r.db.Master.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not get suppliers from db: %w", err)
+	}
+	defer rows.Close()
+
+	var suppliers []model.Supplier
+	for rows.Next() {
+		supplier, err := scanSupplier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		suppliers = append(suppliers, supplier)
+	}
+
+	return suppliers, nil
+}
+
+func (r *Repository) GetSupplierByID(ctx context.Context, id int) (*model.Supplier, error) {
+	query := "SELECT " + supplierColumns + " FROM suppliers WHERE id = $1"
+
+	supplier, err := scanSupplier(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchSupplier
+		}
+		return nil, fmt.Errorf("could not get supplier from db: %w", err)
+	}
+
+	return &supplier, nil
+}
+
+func (r *Repository) UpdateSupplier(ctx context.Context, updateSupplier *dto.UpdateSupplier) error {
+	query := `UPDATE suppliers SET
+		name = COALESCE($1, name),
+		contact_name = COALESCE($2, contact_name),
+		email = COALESCE($3, email),
+		phone = COALESCE($4, phone),
+		lead_time_days = COALESCE($5, lead_time_days)
+		WHERE id = $6`
+
+	result, err := r.db.Master.ExecContext(
+		ctx,
+		query,
+		updateSupplier.Name,
+		updateSupplier.ContactName,
+		updateSupplier.Email,
+		updateSupplier.Phone,
+		updateSupplier.LeadTimeDays,
+		updateSupplier.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrSupplierExists
+		}
+		return fmt.Errorf("could not update supplier in db: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchSupplier
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteSupplier(ctx context.Context, id int) error {
+	query := "DELETE FROM suppliers WHERE id = $1"
+
+	result, err := r.db.Master.ExecContext(ctx, query, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrSupplierHasOrders
+		}
+		return fmt.Errorf("could not delete supplier by id: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchSupplier
+	}
+
+	return nil
+}
+
+func (r *Repository) SetSupplierItem(ctx context.Context, setSupplierItem dto.SetSupplierItem) (*model.SupplierItem, error) {
+	query := `INSERT INTO supplier_items(supplier_id, item_id, supplier_sku, price, min_order_quantity)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (supplier_id, item_id) DO UPDATE SET
+		supplier_sku = EXCLUDED.supplier_sku,
+		price = EXCLUDED.price,
+		min_order_quantity = EXCLUDED.min_order_quantity`
+
+	_, err := r.db.Master.ExecContext(
+		ctx,
+		query,
+		setSupplierItem.SupplierID,
+		setSupplierItem.ItemID,
+		setSupplierItem.SupplierSKU,
+		setSupplierItem.Price,
+		setSupplierItem.MinOrderQuantity,
+	)
+	if err != nil {
+		return nil, mapItemForeignKeyError(err, "could not set supplier item in db: %w")
+	}
+
+	return &model.SupplierItem{
+		SupplierID:       setSupplierItem.SupplierID,
+		ItemID:           setSupplierItem.ItemID,
+		SupplierSKU:      setSupplierItem.SupplierSKU,
+		Price:            setSupplierItem.Price,
+		MinOrderQuantity: setSupplierItem.MinOrderQuantity,
+	}, nil
+}
+
+func (r *Repository) GetSupplierItems(ctx context.Context, supplierID int) ([]model.SupplierItem, error) {
+	query := `SELECT supplier_id, item_id, supplier_sku, price, min_order_quantity
+	FROM supplier_items WHERE supplier_id = $1
+	ORDER BY item_id`
+
+	return r.querySupplierItems(ctx, query, supplierID)
+}
+
+// GetItemSuppliers returns price list entries of item, cheapest
+// first and faster delivery first among equal prices
+func (r *Repository) GetItemSuppliers(ctx context.Context, itemID int) ([]model.SupplierItem, error) {
+	query := `SELECT si.supplier_id, si.item_id, si.supplier_sku, si.price, si.min_order_quantity
+	FROM supplier_items si
+	JOIN suppliers s ON s.id = si.supplier_id
+	WHERE si.item_id = $1
+	ORDER BY si.price, s.lead_time_days, si.supplier_id`
+
+	return r.querySupplierItems(ctx, query, itemID)
+}
+
+func (r *Repository) querySupplierItems(ctx context.Context, query string, args ...any) ([]model.SupplierItem, error) {
+	rows, err := r.db.Master.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not get supplier items from db: %w", err)
+	}
+	defer rows.Close()
+
+	var items []model.SupplierItem
+	for rows.Next() {
+		var item model.SupplierItem
+		err := rows.Scan(
+			&item.SupplierID,
+			&item.ItemID,
+			&item.SupplierSKU,
+			&item.Price,
+			&item.MinOrderQuantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (r *Repository) GetSupplierItem(ctx context.Context, supplierID, itemID int) (*model.SupplierItem, error) {
+	query := `SELECT supplier_id, item_id, supplier_sku, price, min_order_quantity
+	FROM supplier_items WHERE supplier_id = $1 AND item_id = $2`
+
+	var item model.SupplierItem
+	err := r.db.Master.QueryRowContext(ctx, query, supplierID, itemID).Scan(
+		&item.SupplierID,
+		&item.ItemID,
+		&item.SupplierSKU,
+		&item.Price,
+		&item.MinOrderQuantity,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchSupplierItem
+		}
+		return nil, fmt.Errorf("could not get supplier item from db: %w", err)
+	}
+
+	return &item, nil
+}
+
+func (r *Repository) DeleteSupplierItem(ctx context.Context, supplierID, itemID int) error {
+	query := "DELETE FROM supplier_items WHERE supplier_id = $1 AND item_id = $2"
+
+	result, err := r.db.Master.ExecContext(ctx, query, supplierID, itemID)
+	if err != nil {
+		return fmt.Errorf("could not delete supplier item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchSupplierItem
+	}
+
+	return nil
+}
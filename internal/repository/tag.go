@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+
+	"github.com/lib/pq"
+)
+
+func (r *Repository) SetItemTags(ctx context.Context, setTags *dto.SetItemTags) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM item_tags WHERE item_id = $1"
+	if _, err := tx.ExecContext(ctx, query, setTags.ItemID); err != nil {
+		return fmt.Errorf("could not delete item tags: %w", err)
+	}
+
+	if len(setTags.Tags) > 0 {
+		query = `INSERT INTO item_tags(item_id, tag)
+		SELECT $1, tag FROM UNNEST($2::VARCHAR[]) AS tag
+		ON CONFLICT DO NOTHING`
+
+		if _, err := tx.ExecContext(ctx, query, setTags.ItemID, pq.Array(setTags.Tags)); err != nil {
+			return mapItemForeignKeyError(err, "could not add item tags: %w")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// GetTags returns tags in use with number of active items labeled with each
+func (r *Repository) GetTags(ctx context.Context) ([]model.TagCount, error) {
+	query := `SELECT t.tag, COUNT(*) FROM item_tags t
+	JOIN items i ON i.id = t.item_id
+	WHERE i.deleted_at IS NULL
+	GROUP BY t.tag
+	ORDER BY t.tag`
+
+	rows, err := r.db.Master.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not get tags from db: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []model.TagCount
+	for rows.Next() {
+		var tag model.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Items); err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
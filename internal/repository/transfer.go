@@ -0,0 +1,441 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+
+	"github.com/lib/pq"
+)
+
+// inTransitQuantity is lateral subquery of quantity dispatched to
+// warehouse of stock row aliased as s and not received yet
+const inTransitQuantity = `SELECT COALESCE(SUM(tl.quantity), 0) AS in_transit
+		FROM transfer_order_lines tl JOIN transfer_orders t ON t.id = tl.order_id
+		WHERE tl.item_id = s.item_id AND t.destination_warehouse_id = s.warehouse_id
+		AND t.status = 'in_transit'`
+
+const transferOrderColumns = `id, source_warehouse_id, destination_warehouse_id, reference, status, user_id,
+	created_at, updated_at, dispatched_at, received_at`
+
+func scanTransferOrder(row rowScanner) (model.TransferOrder, error) {
+	var order model.TransferOrder
+	err := row.Scan(
+		&order.ID,
+		&order.SourceWarehouseID,
+		&order.DestinationWarehouseID,
+		&order.Reference,
+		&order.Status,
+		&order.UserID,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+		&order.DispatchedAt,
+		&order.ReceivedAt,
+	)
+	return order, err
+}
+
+const transferOrderLineColumns = `id, order_id, item_id, lot_id, quantity, received_quantity,
+	COALESCE(quantity - received_quantity, 0), serials, note, dispatch_movement_id, receipt_movement_id`
+
+func scanTransferOrderLine(row rowScanner) (model.TransferOrderLine, error) {
+	var line model.TransferOrderLine
+	err := row.Scan(
+		&line.ID,
+		&line.OrderID,
+		&line.ItemID,
+		&line.LotID,
+		&line.Quantity,
+		&line.ReceivedQuantity,
+		&line.Discrepancy,
+		pq.Array(&line.Serials),
+		&line.Note,
+		&line.DispatchMovementID,
+		&line.ReceiptMovementID,
+	)
+	return line, err
+}
+
+func (r *Repository) CreateTransferOrder(ctx context.Context, createOrder dto.CreateTransferOrder) (*model.TransferOrder, error) {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO transfer_orders(source_warehouse_id, destination_warehouse_id, reference, user_id)
+	VALUES ($1, $2, $3, $4)
+	RETURNING ` + transferOrderColumns
+
+	order, err := scanTransferOrder(tx.QueryRowContext(
+		ctx,
+		query,
+		createOrder.SourceWarehouseID,
+		createOrder.DestinationWarehouseID,
+		createOrder.Reference,
+		createOrder.UserID,
+	))
+	if err != nil {
+		return nil, mapItemForeignKeyError(err, "could not create transfer order in db: %w")
+	}
+
+	query = `INSERT INTO transfer_order_lines(order_id, item_id, lot_id, quantity, serials)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING ` + transferOrderLineColumns
+	for _, line := range createOrder.Lines {
+		if err := lockActiveItem(ctx, tx, line.ItemID, "FOR SHARE"); err != nil {
+			return nil, err
+		}
+
+		serials := line.Serials
+		if serials == nil {
+			serials = []string{}
+		}
+
+		orderLine, err := scanTransferOrderLine(tx.QueryRowContext(ctx, query, order.ID, line.ItemID, line.LotID, line.Quantity, pq.Array(serials)))
+		if err != nil {
+			return nil, mapItemForeignKeyError(err, "could not create transfer order line in db: %w")
+		}
+
+		order.Lines = append(order.Lines, orderLine)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return &order, nil
+}
+
+func (r *Repository) GetTransferOrders(ctx context.Context, filter dto.TransferOrderFilter) ([]model.TransferOrder, error) {
+	query := "SELECT " + transferOrderColumns + " FROM transfer_orders"
+
+	var args []any
+	var conditions []string
+	if filter.WarehouseID != nil {
+		args = append(args, *filter.WarehouseID)
+		conditions = append(conditions, fmt.Sprintf("(source_warehouse_id = $%d OR destination_warehouse_id = $%d)", len(args), len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Master.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not get transfer orders from db: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []model.TransferOrder
+	for rows.Next() {
+		order, err := scanTransferOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+func (r *Repository) GetTransferOrderByID(ctx context.Context, id int) (*model.TransferOrder, error) {
+	query := "SELECT " + transferOrderColumns + " FROM transfer_orders WHERE id = $1"
+
+	order, err := scanTransferOrder(r.db.Master.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchTransferOrder
+		}
+		return nil, fmt.Errorf("could not get transfer order from db: %w", err)
+	}
+
+	query = "SELECT " + transferOrderLineColumns + " FROM transfer_order_lines WHERE order_id = $1 ORDER BY id"
+	rows, err := r.db.Master.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not get transfer order lines from db: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		line, err := scanTransferOrderLine(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		order.Lines = append(order.Lines, line)
+	}
+
+	return &order, nil
+}
+
+// lockTransferOrder locks order for change, lines are returned
+// as they are needed by every change of order status
+func lockTransferOrder(ctx context.Context, tx *sql.Tx, id int) (*model.TransferOrder, error) {
+	query := "SELECT " + transferOrderColumns + " FROM transfer_orders WHERE id = $1 FOR UPDATE"
+
+	order, err := scanTransferOrder(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchTransferOrder
+		}
+		return nil, fmt.Errorf("could not lock transfer order: %w", err)
+	}
+
+	query = "SELECT " + transferOrderLineColumns + " FROM transfer_order_lines WHERE order_id = $1 ORDER BY id"
+	rows, err := tx.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not get transfer order lines from db: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		line, err := scanTransferOrderLine(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		order.Lines = append(order.Lines, line)
+	}
+
+	return &order, nil
+}
+
+// DispatchTransferOrder takes lines out of source warehouse, only stock
+// that is not reserved may leave, destination sees it as in transit
+func (r *Repository) DispatchTransferOrder(ctx context.Context, dispatchOrder dto.DispatchTransferOrder) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setCurrentUser(ctx, tx, dispatchOrder.UserID); err != nil {
+		return err
+	}
+
+	order, err := lockTransferOrder(ctx, tx, dispatchOrder.ID)
+	if err != nil {
+		return err
+	}
+	if order.Status != model.TransferOrderStatusDraft {
+		return ErrTransferOrderStatus
+	}
+
+	reference := fmt.Sprintf("transfer order %d", order.ID)
+	for _, line := range order.Lines {
+		available, err := lockAvailableStock(ctx, tx, line.ItemID, order.SourceWarehouseID)
+		if err != nil {
+			return err
+		}
+		if available < line.Quantity {
+			return fmt.Errorf("%w: item %d", ErrInsufficientAvailable, line.ItemID)
+		}
+
+		movement := &model.Movement{
+			ItemID:      line.ItemID,
+			WarehouseID: order.SourceWarehouseID,
+			LotID:       line.LotID,
+			Quantity:    -line.Quantity,
+			Serials:     line.Serials,
+			Reason:      model.MovementReasonTransferOut,
+			Reference:   reference,
+			UserID:      &dispatchOrder.UserID,
+		}
+		if len(movement.Serials) == 0 {
+			movement.Serials = nil
+		}
+		if err := applyMovement(ctx, tx, movement, false); err != nil {
+			return err
+		}
+
+		// stock row of destination shows incoming quantity before arrival
+		query := `INSERT INTO stock(item_id, warehouse_id, quantity) VALUES ($1, $2, 0)
+		ON CONFLICT (item_id, warehouse_id) DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, line.ItemID, order.DestinationWarehouseID); err != nil {
+			return mapItemForeignKeyError(err, "could not create destination stock in db: %w")
+		}
+
+		query = "UPDATE transfer_order_lines SET dispatch_movement_id = $1 WHERE id = $2"
+		if _, err := tx.ExecContext(ctx, query, movement.ID, line.ID); err != nil {
+			return fmt.Errorf("could not link transfer order line to movement: %w", err)
+		}
+	}
+
+	query := `UPDATE transfer_orders SET status = 'in_transit', dispatched_at = NOW(), updated_at = NOW()
+	WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, order.ID); err != nil {
+		return fmt.Errorf("could not update transfer order status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// ReceiveTransferOrder puts arrived quantities into destination warehouse,
+// lines not listed arrive in full, anything less is kept as discrepancy
+func (r *Repository) ReceiveTransferOrder(ctx context.Context, receiveOrder *dto.ReceiveTransferOrder) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setCurrentUser(ctx, tx, receiveOrder.UserID); err != nil {
+		return err
+	}
+
+	order, err := lockTransferOrder(ctx, tx, receiveOrder.ID)
+	if err != nil {
+		return err
+	}
+	if order.Status != model.TransferOrderStatusInTransit {
+		return ErrTransferOrderStatus
+	}
+
+	orderLines := make(map[int]bool, len(order.Lines))
+	for _, line := range order.Lines {
+		orderLines[line.ID] = true
+	}
+
+	received := make(map[int]dto.ReceiveTransferLine, len(receiveOrder.Lines))
+	for _, receiveLine := range receiveOrder.Lines {
+		if !orderLines[receiveLine.LineID] {
+			return ErrNoSuchTransferOrderLine
+		}
+		received[receiveLine.LineID] = receiveLine
+	}
+
+	reference := fmt.Sprintf("transfer order %d", order.ID)
+	for _, line := range order.Lines {
+		receiveLine, ok := received[line.ID]
+		if !ok {
+			receiveLine = dto.ReceiveTransferLine{LineID: line.ID, Quantity: line.Quantity, Serials: line.Serials}
+		}
+		if receiveLine.Quantity > line.Quantity {
+			return fmt.Errorf("%w: line %d", ErrOverTransferReceipt, line.ID)
+		}
+		if ok && receiveLine.Serials == nil && receiveLine.Quantity == line.Quantity {
+			receiveLine.Serials = line.Serials
+		}
+		for _, serial := range receiveLine.Serials {
+			if !slices.Contains(line.Serials, serial) {
+				return fmt.Errorf("%w: %s was not dispatched", ErrSerialState, serial)
+			}
+		}
+
+		var movementID *int64
+		if receiveLine.Quantity > 0 {
+			movement := &model.Movement{
+				ItemID:      line.ItemID,
+				WarehouseID: order.DestinationWarehouseID,
+				LocationID:  receiveOrder.LocationID,
+				LotID:       line.LotID,
+				Quantity:    receiveLine.Quantity,
+				Serials:     receiveLine.Serials,
+				Reason:      model.MovementReasonTransferIn,
+				Reference:   reference,
+				UserID:      &receiveOrder.UserID,
+			}
+			if len(movement.Serials) == 0 {
+				movement.Serials = nil
+			}
+			if err := transferredCost(ctx, tx, line.DispatchMovementID, movement); err != nil {
+				return err
+			}
+			if err := applyMovement(ctx, tx, movement, false); err != nil {
+				return err
+			}
+			movementID = &movement.ID
+		}
+
+		query := `UPDATE transfer_order_lines SET received_quantity = $1, note = $2, receipt_movement_id = $3
+		WHERE id = $4`
+		if _, err := tx.ExecContext(ctx, query, receiveLine.Quantity, receiveLine.Note, movementID, line.ID); err != nil {
+			return fmt.Errorf("could not update received quantity in db: %w", err)
+		}
+	}
+
+	query := `UPDATE transfer_orders SET status = 'received', received_at = NOW(), updated_at = NOW()
+	WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, order.ID); err != nil {
+		return fmt.Errorf("could not update transfer order status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// transferredCost values received stock at cost it left source
+// warehouse with, stock lost on the way takes its share of cost with it
+func transferredCost(ctx context.Context, tx *sql.Tx, dispatchMovementID *int64, movement *model.Movement) error {
+	if dispatchMovementID == nil {
+		return nil
+	}
+
+	var dispatched float64
+	var fifoCost, averageCost *float64
+	query := "SELECT quantity, fifo_cost, average_cost FROM stock_movements WHERE id = $1"
+	err := tx.QueryRowContext(ctx, query, *dispatchMovementID).Scan(&dispatched, &fifoCost, &averageCost)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("could not get dispatch movement from db: %w", err)
+	}
+	if fifoCost == nil || averageCost == nil || dispatched == 0 {
+		return nil
+	}
+
+	share := movement.Quantity / -dispatched
+	fifo := roundCost(-*fifoCost * share)
+	average := roundCost(-*averageCost * share)
+	movement.FIFOCost = &fifo
+	movement.AverageCost = &average
+
+	return nil
+}
+
+// CancelTransferOrder drops draft order, dispatched goods have to be received
+func (r *Repository) CancelTransferOrder(ctx context.Context, id int) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	order, err := lockTransferOrder(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if order.Status != model.TransferOrderStatusDraft {
+		return ErrTransferOrderStatus
+	}
+
+	query := "UPDATE transfer_orders SET status = 'cancelled', updated_at = NOW() WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("could not cancel transfer order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (r *Repository) CreateUnit(ctx context.Context, createUnit dto.CreateUnit) (*model.Unit, error) {
+	query := "INSERT INTO units(code, name, allow_decimal) VALUES ($1, $2, $3)"
+
+	_, err := r.db.Master.ExecContext(ctx, query, createUnit.Code, createUnit.Name, createUnit.AllowDecimal)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrUnitExists
+		}
+		return nil, fmt.Errorf("could not create unit: %w", err)
+	}
+
+	return &model.Unit{
+		Code:         createUnit.Code,
+		Name:         createUnit.Name,
+		AllowDecimal: createUnit.AllowDecimal,
+	}, nil
+}
+
+func (r *Repository) GetUnits(ctx context.Context) ([]model.Unit, error) {
+	query := "SELECT code, name, allow_decimal FROM units ORDER BY code"
+
+	rows, err := r.db.Master.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not get units from db: %w", err)
+	}
+	defer rows.Close()
+
+	var units []model.Unit
+	for rows.Next() {
+		var unit model.Unit
+		if err := rows.Scan(&unit.Code, &unit.Name, &unit.AllowDecimal); err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		units = append(units, unit)
+	}
+
+	return units, nil
+}
+
+func (r *Repository) GetUnit(ctx context.Context, code string) (*model.Unit, error) {
+	query := "SELECT code, name, allow_decimal FROM units WHERE code = $1"
+
+	var unit model.Unit
+	err := r.db.Master.QueryRowContext(ctx, query, code).Scan(&unit.Code, &unit.Name, &unit.AllowDecimal)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchUnit
+		}
+		return nil, fmt.Errorf("could not get unit from db: %w", err)
+	}
+
+	return &unit, nil
+}
+
+func (r *Repository) SetItemUnit(ctx context.Context, itemUnit model.ItemUnit) error {
+	query := `INSERT INTO item_units(item_id, unit, factor) VALUES ($1, $2, $3)
+	ON CONFLICT (item_id, unit) DO UPDATE SET factor = EXCLUDED.factor`
+
+	_, err := r.db.Master.ExecContext(ctx, query, itemUnit.ItemID, itemUnit.Unit, itemUnit.Factor)
+	if err != nil {
+		return mapItemForeignKeyError(err, "could not set item unit: %w")
+	}
+
+	return nil
+}
+
+func (r *Repository) GetItemUnits(ctx context.Context, itemID int) ([]model.ItemUnit, error) {
+	query := "SELECT item_id, unit, factor FROM item_units WHERE item_id = $1 ORDER BY unit"
+
+	rows, err := r.db.Master.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get item units from db: %w", err)
+	}
+	defer rows.Close()
+
+	var units []model.ItemUnit
+	for rows.Next() {
+		var unit model.ItemUnit
+		if err := rows.Scan(&unit.ItemID, &unit.Unit, &unit.Factor); err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		units = append(units, unit)
+	}
+
+	return units, nil
+}
+
+func (r *Repository) DeleteItemUnit(ctx context.Context, itemID int, unit string) error {
+	query := "DELETE FROM item_units WHERE item_id = $1 AND unit = $2"
+
+	result, err := r.db.Master.ExecContext(ctx, query, itemID, unit)
+	if err != nil {
+		return fmt.Errorf("could not delete item unit: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchUnit
+	}
+
+	return nil
+}
@@ -2,39 +2,71 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
 )
 
 func (r *Repository) UpdateItem(ctx context.Context, updateItem *dto.UpdateItem) error {
-	// I know about risk of sql injection here but it is not possible
-	// to use placehlder because of dirver and userID is int also
-	// it guarantees than sql injection string will not be placed
 	tx, err := r.db.Master.Begin()
 	if err != nil {
 		return fmt.Errorf("could not start transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	query := fmt.Sprintf("SET LOCAL app.current_user_id = %d;", updateItem.UserID)
-	_, err = tx.ExecContext(ctx, query)
-	if err != nil {
-		return fmt.Errorf("could not update item in db: %w", err)
+	if err := applyItemUpdate(ctx, tx, updateItem); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
+
+// applyItemUpdate changes item within tx and sets its new version to updateItem
+func applyItemUpdate(ctx context.Context, tx *sql.Tx, updateItem *dto.UpdateItem) error {
+	if err := setCurrentUser(ctx, tx, updateItem.UserID); err != nil {
+		return err
+	}
+
+	if updateItem.Serialized != nil {
+		if err := checkSerializedSwitch(ctx, tx, updateItem.ID, *updateItem.Serialized); err != nil {
+			return err
+		}
 	}
 
-	query = `UPDATE items SET 
+	var attributes []byte
+	var err error
+	if updateItem.Attributes != nil {
+		attributes, err = json.Marshal(updateItem.Attributes)
+		if err != nil {
+			return fmt.Errorf("could not marshal item attributes: %w", err)
+		}
+	}
+
+	query := `UPDATE items SET 
 		name = COALESCE($1, name),
-		count = COALESCE($2, count)
-		WHERE id = $3;`
+		category_id = COALESCE($2, category_id),
+		attributes = COALESCE($3::JSONB, attributes),
+		serialized = COALESCE($6, serialized)
+		WHERE id = $4 AND ($5::INT IS NULL OR version = $5) AND deleted_at IS NULL;`
 	result, err := tx.ExecContext(
 		ctx,
 		query,
 		updateItem.Name,
-		updateItem.Count,
+		updateItem.CategoryID,
+		attributes,
 		updateItem.ID,
+		updateItem.Version,
+		updateItem.Serialized,
 	)
 	if err != nil {
-		return fmt.Errorf("could not update item in db: %w", err)
+		return mapItemForeignKeyError(err, "could not update item in db: %w")
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -43,12 +75,119 @@ func (r *Repository) UpdateItem(ctx context.Context, updateItem *dto.UpdateItem)
 	}
 
 	if rowsAffected == 0 {
-		return ErrNoSuchItem
+		return itemVersionError(ctx, tx, updateItem.ID)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("could not commit transcation: %w", err)
+	if updateItem.Name != nil || updateItem.CategoryID != nil {
+		if err := checkItemName(ctx, tx, updateItem.ID, updateItem.NameScope); err != nil {
+			return err
+		}
+	}
+
+	if updateItem.Count != nil {
+		if err := setItemStock(ctx, tx, updateItem); err != nil {
+			return err
+		}
+	}
+
+	var version int
+	query = "SELECT version FROM items WHERE id = $1"
+	if err := tx.QueryRowContext(ctx, query, updateItem.ID).Scan(&version); err != nil {
+		return fmt.Errorf("could not get item version from db: %w", err)
+	}
+	updateItem.Version = &version
+
+	return nil
+}
+
+// setItemStock turns absolute quantity of item in the warehouse or,
+// when location is given, in that location into a correction movement
+func setItemStock(ctx context.Context, tx *sql.Tx, updateItem *dto.UpdateItem) error {
+	current, err := currentItemStock(ctx, tx, updateItem)
+	if err != nil {
+		return err
+	}
+
+	delta := quantityDelta(*updateItem.Count, current)
+	if delta == 0 {
+		return nil
+	}
+
+	return applyMovement(ctx, tx, &model.Movement{
+		ItemID:      updateItem.ID,
+		WarehouseID: updateItem.WarehouseID,
+		LocationID:  updateItem.LocationID,
+		Quantity:    delta,
+		Reason:      model.MovementReasonCorrection,
+		Reference:   "item update",
+		UserID:      &updateItem.UserID,
+		Serials:     updateItem.Serials,
+	}, false)
+}
+
+// currentItemStock locks and returns quantity Count of update is set for
+func currentItemStock(ctx context.Context, tx *sql.Tx, updateItem *dto.UpdateItem) (float64, error) {
+	var query string
+	var args []any
+	if updateItem.LocationID == nil {
+		query = "SELECT quantity FROM stock WHERE item_id = $1 AND warehouse_id = $2 FOR UPDATE"
+		args = []any{updateItem.ID, updateItem.WarehouseID}
+	} else {
+		if err := checkLocationWarehouse(ctx, tx, *updateItem.LocationID, updateItem.WarehouseID); err != nil {
+			return 0, err
+		}
+
+		query = "SELECT quantity FROM location_stock WHERE item_id = $1 AND location_id = $2 FOR UPDATE"
+		args = []any{updateItem.ID, *updateItem.LocationID}
+	}
+
+	var current float64
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&current)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("could not get item stock from db: %w", err)
+	}
+
+	return current, nil
+}
+
+// checkSerializedSwitch allows to turn serial tracking on or off only
+// while item has no stock, otherwise units on hand would lack serials
+func checkSerializedSwitch(ctx context.Context, tx *sql.Tx, itemID int, serialized bool) error {
+	var current bool
+	var count float64
+	query := "SELECT serialized, count FROM items WHERE id = $1 FOR UPDATE"
+	if err := tx.QueryRowContext(ctx, query, itemID).Scan(&current, &count); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoSuchItem
+		}
+		return fmt.Errorf("could not get item from db: %w", err)
+	}
+
+	if current != serialized && count != 0 {
+		return ErrSerializedStock
 	}
 
 	return nil
 }
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// itemVersionError tells why conditional write of item affected no
+// rows: item is gone, archived or its version has already moved on
+func itemVersionError(ctx context.Context, q queryRower, id int) error {
+	var archived bool
+	query := "SELECT deleted_at IS NOT NULL FROM items WHERE id = $1"
+	if err := q.QueryRowContext(ctx, query, id).Scan(&archived); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoSuchItem
+		}
+		return fmt.Errorf("could not check item existence in db: %w", err)
+	}
+
+	if archived {
+		return ErrItemArchived
+	}
+	return ErrVersionConflict
+}
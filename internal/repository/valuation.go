@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+// costMovement values recorded movement by both valuation methods,
+// inbound stock opens cost layer and adds to average value, outbound
+// stock consumes oldest layers and takes average cost of stock it
+// leaves, previousQuantity is warehouse stock before movement
+func costMovement(ctx context.Context, tx *sql.Tx, movement *model.Movement, previousQuantity float64) error {
+	if movement.Quantity == 0 {
+		return nil
+	}
+
+	var value float64
+	query := "SELECT value FROM stock WHERE item_id = $1 AND warehouse_id = $2"
+	if err := tx.QueryRowContext(ctx, query, movement.ItemID, movement.WarehouseID).Scan(&value); err != nil {
+		return fmt.Errorf("could not get stock value from db: %w", err)
+	}
+
+	averageUnit, err := averageUnitCost(ctx, tx, movement.ItemID, movement.WarehouseID, previousQuantity, value)
+	if err != nil {
+		return err
+	}
+
+	var fifoCost, averageCost float64
+	if movement.Quantity > 0 {
+		switch {
+		case movement.FIFOCost != nil && movement.AverageCost != nil:
+			fifoCost, averageCost = *movement.FIFOCost, *movement.AverageCost
+		case movement.UnitCost != nil:
+			fifoCost = *movement.UnitCost * movement.Quantity
+			averageCost = fifoCost
+		default:
+			fifoCost = averageUnit * movement.Quantity
+			averageCost = fifoCost
+		}
+
+		query = `INSERT INTO cost_layers(item_id, warehouse_id, movement_id, remaining_quantity, unit_cost)
+		VALUES ($1, $2, $3, $4, $5)`
+		unitCost := math.Max(fifoCost/movement.Quantity, 0)
+		_, err := tx.ExecContext(ctx, query, movement.ItemID, movement.WarehouseID, movement.ID, movement.Quantity, unitCost)
+		if err != nil {
+			return fmt.Errorf("could not create cost layer in db: %w", err)
+		}
+	} else {
+		fifoCost, err = consumeCostLayers(ctx, tx, movement, averageUnit)
+		if err != nil {
+			return err
+		}
+
+		// the last units take whatever value is left so that
+		// rounding never leaves value on empty stock
+		if previousQuantity > 0 && quantityDelta(previousQuantity, -movement.Quantity) == 0 {
+			averageCost = -value
+		} else {
+			averageCost = averageUnit * movement.Quantity
+		}
+	}
+	fifoCost = roundCost(fifoCost)
+	averageCost = roundCost(averageCost)
+
+	query = "UPDATE stock SET value = value + $1 WHERE item_id = $2 AND warehouse_id = $3"
+	if _, err := tx.ExecContext(ctx, query, averageCost, movement.ItemID, movement.WarehouseID); err != nil {
+		return fmt.Errorf("could not update stock value in db: %w", err)
+	}
+
+	query = "UPDATE stock_movements SET unit_cost = $1, fifo_cost = $2, average_cost = $3 WHERE id = $4"
+	if _, err := tx.ExecContext(ctx, query, movement.UnitCost, fifoCost, averageCost, movement.ID); err != nil {
+		return fmt.Errorf("could not record movement cost in db: %w", err)
+	}
+
+	movement.FIFOCost = &fifoCost
+	movement.AverageCost = &averageCost
+
+	return nil
+}
+
+// averageUnitCost is weighted average cost of base unit of stock,
+// empty stock is valued at cost of the last layer it had
+func averageUnitCost(ctx context.Context, tx *sql.Tx, itemID, warehouseID int, quantity, value float64) (float64, error) {
+	if quantity > 0 {
+		return value / quantity, nil
+	}
+
+	var unitCost float64
+	query := `SELECT unit_cost FROM cost_layers WHERE item_id = $1 AND warehouse_id = $2
+	ORDER BY id DESC LIMIT 1`
+	if err := tx.QueryRowContext(ctx, query, itemID, warehouseID).Scan(&unitCost); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("could not get last cost layer from db: %w", err)
+	}
+
+	return unitCost, nil
+}
+
+// stockUnitCost is average cost of base unit of item stock in warehouse
+func stockUnitCost(ctx context.Context, tx *sql.Tx, itemID, warehouseID int) (float64, error) {
+	var quantity, value float64
+	query := "SELECT quantity, value FROM stock WHERE item_id = $1 AND warehouse_id = $2"
+	err := tx.QueryRowContext(ctx, query, itemID, warehouseID).Scan(&quantity, &value)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("could not get stock value from db: %w", err)
+	}
+
+	return averageUnitCost(ctx, tx, itemID, warehouseID, quantity, value)
+}
+
+type costLayer struct {
+	id        int64
+	remaining float64
+	unitCost  float64
+}
+
+// consumeCostLayers takes outbound quantity from the oldest layers and
+// returns its negative cost, quantity beyond layers is valued at average
+func consumeCostLayers(ctx context.Context, tx *sql.Tx, movement *model.Movement, averageUnit float64) (float64, error) {
+	query := `SELECT id, remaining_quantity, unit_cost FROM cost_layers
+	WHERE item_id = $1 AND warehouse_id = $2 AND remaining_quantity > 0
+	ORDER BY id
+	FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, movement.ItemID, movement.WarehouseID)
+	if err != nil {
+		return 0, fmt.Errorf("could not lock cost layers: %w", err)
+	}
+
+	var layers []costLayer
+	for rows.Next() {
+		var layer costLayer
+		if err := rows.Scan(&layer.id, &layer.remaining, &layer.unitCost); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+		layers = append(layers, layer)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("could not lock cost layers: %w", err)
+	}
+
+	need := -movement.Quantity
+	var cost float64
+	query = "UPDATE cost_layers SET remaining_quantity = $1 WHERE id = $2"
+	for _, layer := range layers {
+		if need <= 0 {
+			break
+		}
+
+		taken := math.Min(layer.remaining, need)
+		cost += taken * layer.unitCost
+		need = quantityDelta(need, taken)
+
+		if _, err := tx.ExecContext(ctx, query, quantityDelta(layer.remaining, taken), layer.id); err != nil {
+			return 0, fmt.Errorf("could not update cost layer in db: %w", err)
+		}
+	}
+	if need > 0 {
+		cost += need * averageUnit
+	}
+
+	return -cost, nil
+}
+
+// roundCost rounds value to storage precision of costs
+func roundCost(cost float64) float64 {
+	return math.Round(cost*10000) / 10000
+}
+
+// GetValuation sums quantities and values of movements up to the end
+// of AsOf day per item and warehouse, values are taken by Method
+func (r *Repository) GetValuation(ctx context.Context, filter dto.ValuationFilter) ([]model.ItemValuation, error) {
+	query := `SELECT m.item_id, i.name, i.category_id, COALESCE(c.name, ''), m.warehouse_id, w.name,
+		SUM(m.quantity),
+		SUM(COALESCE(CASE WHEN $4 = 'average' THEN m.average_cost ELSE m.fifo_cost END, 0)) AS value
+	FROM stock_movements m
+	JOIN items i ON i.id = m.item_id
+	JOIN warehouses w ON w.id = m.warehouse_id
+	LEFT JOIN categories c ON c.id = i.category_id
+	WHERE m.created_at < $1
+	AND ($2::INT IS NULL OR m.warehouse_id = $2)
+	AND ($3::INT IS NULL OR i.category_id IN (
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = $3
+			UNION ALL
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree))
+	GROUP BY m.item_id, i.name, i.category_id, c.name, m.warehouse_id, w.name
+	HAVING SUM(m.quantity) <> 0 OR SUM(COALESCE(CASE WHEN $4 = 'average' THEN m.average_cost ELSE m.fifo_cost END, 0)) <> 0
+	ORDER BY m.item_id, m.warehouse_id`
+
+	asOf := filter.AsOf.Add(24 * time.Hour)
+	rows, err := r.db.Master.QueryContext(ctx, query, asOf, filter.WarehouseID, filter.CategoryID, filter.Method)
+	if err != nil {
+		return nil, fmt.Errorf("could not get valuation from db: %w", err)
+	}
+	defer rows.Close()
+
+	var valuations []model.ItemValuation
+	for rows.Next() {
+		var valuation model.ItemValuation
+		err := rows.Scan(
+			&valuation.ItemID,
+			&valuation.Name,
+			&valuation.CategoryID,
+			&valuation.CategoryName,
+			&valuation.WarehouseID,
+			&valuation.WarehouseName,
+			&valuation.Quantity,
+			&valuation.Value,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		valuations = append(valuations, valuation)
+	}
+
+	return valuations, nil
+}
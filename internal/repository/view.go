@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+const savedViewColumns = `id, user_id, name, filter, created_at, updated_at`
+
+func scanSavedView(row rowScanner) (model.SavedView, error) {
+	var view model.SavedView
+	err := row.Scan(
+		&view.ID,
+		&view.UserID,
+		&view.Name,
+		&view.Filter,
+		&view.CreatedAt,
+		&view.UpdatedAt,
+	)
+	return view, err
+}
+
+func (r *Repository) CreateSavedView(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error) {
+	filter, err := json.Marshal(saveView.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal view filter: %w", err)
+	}
+
+	query := `INSERT INTO saved_views(user_id, name, filter) VALUES ($1, $2, $3)
+	RETURNING ` + savedViewColumns
+
+	view, err := scanSavedView(r.db.Master.QueryRowContext(ctx, query, saveView.UserID, saveView.Name, filter))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrSavedViewExists
+		}
+		return nil, mapItemForeignKeyError(err, "could not create saved view: %w")
+	}
+
+	return &view, nil
+}
+
+func (r *Repository) GetSavedViews(ctx context.Context, userID int) ([]model.SavedView, error) {
+	query := "SELECT " + savedViewColumns + " FROM saved_views WHERE user_id = $1 ORDER BY name"
+
+	rows, err := r.db.Master.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get saved views from db: %w", err)
+	}
+	defer rows.Close()
+
+	var views []model.SavedView
+	for rows.Next() {
+		view, err := scanSavedView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		views = append(views, view)
+	}
+
+	return views, nil
+}
+
+// GetSavedViewByID returns view only to its owner, views
+// of other users are reported as missing
+func (r *Repository) GetSavedViewByID(ctx context.Context, id, userID int) (*model.SavedView, error) {
+	query := "SELECT " + savedViewColumns + " FROM saved_views WHERE id = $1 AND user_id = $2"
+
+	view, err := scanSavedView(r.db.Master.QueryRowContext(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchSavedView
+		}
+		return nil, fmt.Errorf("could not get saved view from db: %w", err)
+	}
+
+	return &view, nil
+}
+
+func (r *Repository) UpdateSavedView(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error) {
+	filter, err := json.Marshal(saveView.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal view filter: %w", err)
+	}
+
+	query := `UPDATE saved_views SET name = $1, filter = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $3 AND user_id = $4
+	RETURNING ` + savedViewColumns
+
+	view, err := scanSavedView(r.db.Master.QueryRowContext(
+		ctx,
+		query,
+		saveView.Name,
+		filter,
+		saveView.ID,
+		saveView.UserID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchSavedView
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrSavedViewExists
+		}
+		return nil, fmt.Errorf("could not update saved view in db: %w", err)
+	}
+
+	return &view, nil
+}
+
+func (r *Repository) DeleteSavedView(ctx context.Context, id, userID int) error {
+	query := "DELETE FROM saved_views WHERE id = $1 AND user_id = $2"
+
+	result, err := r.db.Master.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("could not delete saved view by id: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchSavedView
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (r *Repository) CreateWarehouse(ctx context.Context, createWarehouse dto.CreateWarehouse) (*model.Warehouse, error) {
+	query := `INSERT INTO warehouses(name, address) VALUES ($1, $2)
+	RETURNING id, created_at`
+
+	var warehouse model.Warehouse
+	err := r.db.Master.QueryRowContext(
+		ctx,
+		query,
+		createWarehouse.Name,
+		createWarehouse.Address,
+	).Scan(&warehouse.ID, &warehouse.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrWarehouseExists
+		}
+		return nil, fmt.Errorf("could not create warehouse: %w", err)
+	}
+
+	warehouse.Name = createWarehouse.Name
+	warehouse.Address = createWarehouse.Address
+
+	return &warehouse, nil
+}
+
+func (r *Repository) GetWarehouses(ctx context.Context) ([]model.Warehouse, error) {
+	query := "SELECT id, name, address, created_at FROM warehouses ORDER BY id"
+
+	rows, err := r.db.Master.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not get warehouses from db: %w", err)
+	}
+	defer rows.Close()
+
+	var warehouses []model.Warehouse
+	for rows.Next() {
+		var warehouse model.Warehouse
+		err := rows.Scan(
+			&warehouse.ID,
+			&warehouse.Name,
+			&warehouse.Address,
+			&warehouse.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row result to model: %w", err)
+		}
+
+		warehouses = append(warehouses, warehouse)
+	}
+
+	return warehouses, nil
+}
+
+func (r *Repository) GetWarehouseByID(ctx context.Context, id int) (*model.Warehouse, error) {
+	query := "SELECT id, name, address, created_at FROM warehouses WHERE id = $1"
+
+	var warehouse model.Warehouse
+	err := r.db.Master.QueryRowContext(ctx, query, id).Scan(
+		&warehouse.ID,
+		&warehouse.Name,
+		&warehouse.Address,
+		&warehouse.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSuchWarehouse
+		}
+		return nil, fmt.Errorf("could not get warehouse from db: %w", err)
+	}
+
+	return &warehouse, nil
+}
+
+func (r *Repository) UpdateWarehouse(ctx context.Context, updateWarehouse *dto.UpdateWarehouse) error {
+	query := `UPDATE warehouses SET
+		name = COALESCE($1, name),
+		address = COALESCE($2, address)
+		WHERE id = $3`
+
+	result, err := r.db.Master.ExecContext(
+		ctx,
+		query,
+		updateWarehouse.Name,
+		updateWarehouse.Address,
+		updateWarehouse.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrWarehouseExists
+		}
+		return fmt.Errorf("could not update warehouse in db: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchWarehouse
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteWarehouse(ctx context.Context, id int) error {
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// empty stock rows do not prevent deletion
+	query := "DELETE FROM stock WHERE warehouse_id = $1 AND quantity = 0"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("could not delete empty warehouse stock: %w", err)
+	}
+
+	query = "DELETE FROM warehouses WHERE id = $1"
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrWarehouseHasStock
+		}
+		return fmt.Errorf("could not delete warehouse by id: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows in db: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoSuchWarehouse
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transcation: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"wharehouse-control/internal/blob"
+	"wharehouse-control/internal/config"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+)
+
+const maxFileNameLen = 255
+
+// UploadAttachment checks size and content type detected from file
+// contents, puts file and thumbnail of image to blob storage and
+// records attachment, files are removed again if record fails
+func (s *Service) UploadAttachment(ctx context.Context, upload *dto.UploadAttachment) (*model.Attachment, error) {
+	item, err := s.storage.GetItemByID(ctx, upload.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.DeletedAt != nil {
+		return nil, repository.ErrItemArchived
+	}
+
+	rules := config.Cfg.Attachments
+	content := upload.Content
+	if rules.MaxSize > 0 {
+		content = io.LimitReader(content, rules.MaxSize+1)
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("could not read attachment: %w", err)
+	}
+	if rules.MaxSize > 0 && int64(len(data)) > rules.MaxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrAttachmentTooLarge, rules.MaxSize)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidAttachment)
+	}
+
+	contentType := http.DetectContentType(data)
+	if !slices.Contains(rules.AllowedTypes, contentType) {
+		return nil, fmt.Errorf("%w: content type %s is not allowed", ErrInvalidAttachment, contentType)
+	}
+
+	createAttachment := dto.CreateAttachment{
+		ItemID:      upload.ItemID,
+		Kind:        model.AttachmentKindDocument,
+		FileName:    attachmentFileName(upload.FileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		UserID:      upload.UserID,
+	}
+
+	var thumbnail []byte
+	if strings.HasPrefix(contentType, "image/") {
+		createAttachment.Kind = model.AttachmentKindImage
+		if rules.ThumbnailSize > 0 {
+			thumbnail, err = makeThumbnail(data, rules.ThumbnailSize)
+			if err != nil {
+				return nil, fmt.Errorf("%w: image cannot be decoded", ErrInvalidAttachment)
+			}
+		}
+	}
+
+	createAttachment.StorageKey, err = newAttachmentKey(upload.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.blobs.Put(ctx, createAttachment.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	if thumbnail != nil {
+		thumbnailKey := createAttachment.StorageKey + "-thumbnail.png"
+		if err := s.blobs.Put(ctx, thumbnailKey, bytes.NewReader(thumbnail)); err != nil {
+			s.blobs.Delete(ctx, createAttachment.StorageKey)
+			return nil, err
+		}
+		createAttachment.ThumbnailKey = &thumbnailKey
+	}
+
+	attachment, err := s.storage.CreateAttachment(ctx, createAttachment)
+	if err != nil {
+		s.deleteAttachmentFiles(ctx, createAttachment.StorageKey, createAttachment.ThumbnailKey)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (s *Service) GetAttachments(ctx context.Context, itemID int) ([]model.Attachment, error) {
+	if _, err := s.storage.GetItemByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetAttachments(ctx, itemID)
+}
+
+// OpenAttachment returns attachment with reader of its file,
+// thumbnail of image is opened instead when thumbnail is set
+func (s *Service) OpenAttachment(ctx context.Context, id int, thumbnail bool) (*model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.storage.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := attachment.StorageKey
+	if thumbnail {
+		if attachment.ThumbnailKey == nil {
+			return nil, nil, fmt.Errorf("%w: attachment has no thumbnail", repository.ErrNoSuchAttachment)
+		}
+		key = *attachment.ThumbnailKey
+	}
+
+	file, err := s.blobs.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			return nil, nil, fmt.Errorf("%w: file is missing in storage", repository.ErrNoSuchAttachment)
+		}
+		return nil, nil, err
+	}
+
+	return attachment, file, nil
+}
+
+func (s *Service) DeleteAttachment(ctx context.Context, id int) error {
+	attachment, err := s.storage.DeleteAttachment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteAttachmentFiles(ctx, attachment.StorageKey, attachment.ThumbnailKey)
+}
+
+func (s *Service) deleteAttachmentFiles(ctx context.Context, key string, thumbnailKey *string) error {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		return err
+	}
+	if thumbnailKey != nil {
+		return s.blobs.Delete(ctx, *thumbnailKey)
+	}
+
+	return nil
+}
+
+// newAttachmentKey makes random blob key so that names given
+// by users never reach the storage
+func newAttachmentKey(itemID int) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("could not generate attachment key: %w", err)
+	}
+
+	return fmt.Sprintf("items/%d/%s", itemID, hex.EncodeToString(random)), nil
+}
+
+// attachmentFileName keeps only base name of uploaded file,
+// long names are cut from the start to keep extension
+func attachmentFileName(fileName string) string {
+	fileName = path.Base(strings.ReplaceAll(strings.TrimSpace(fileName), `\`, "/"))
+	if fileName == "." || fileName == "/" {
+		fileName = "file"
+	}
+	if runes := []rune(fileName); len(runes) > maxFileNameLen {
+		fileName = string(runes[len(runes)-maxFileNameLen:])
+	}
+
+	return fileName
+}
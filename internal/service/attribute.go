@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (s *Service) CreateAttributeDefinition(ctx context.Context, createDefinition dto.CreateAttributeDefinition) (*model.AttributeDefinition, error) {
+	for _, value := range createDefinition.AllowedValues {
+		if err := checkAttributeType(createDefinition.Type, value); err != nil {
+			return nil, fmt.Errorf("%w: allowed value %q is not a %s", ErrInvalidAttribute, value, createDefinition.Type)
+		}
+	}
+
+	return s.storage.CreateAttributeDefinition(ctx, createDefinition)
+}
+
+func (s *Service) GetAttributeDefinitions(ctx context.Context, categoryID int) ([]model.AttributeDefinition, error) {
+	if _, err := s.storage.GetCategoryByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetAttributeDefinitions(ctx, categoryID)
+}
+
+func (s *Service) DeleteAttributeDefinition(ctx context.Context, categoryID, id int) error {
+	return s.storage.DeleteAttributeDefinition(ctx, categoryID, id)
+}
+
+// validateAttributes checks item attributes against the schema of its category
+func (s *Service) validateAttributes(ctx context.Context, categoryID *int, attributes map[string]any) error {
+	if categoryID == nil {
+		if len(attributes) > 0 {
+			return fmt.Errorf("%w: item without category cannot have attributes", ErrInvalidAttribute)
+		}
+		return nil
+	}
+
+	definitions, err := s.storage.GetAttributeDefinitions(ctx, *categoryID)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(definitions))
+	for _, definition := range definitions {
+		known[definition.Name] = true
+
+		value, ok := attributes[definition.Name]
+		if !ok || value == nil {
+			if definition.Required {
+				return fmt.Errorf("%w: %s is required", ErrInvalidAttribute, definition.Name)
+			}
+			continue
+		}
+
+		if err := checkAttributeValue(definition, value); err != nil {
+			return err
+		}
+	}
+
+	for name := range attributes {
+		if !known[name] {
+			return fmt.Errorf("%w: %s is not defined for category", ErrInvalidAttribute, name)
+		}
+	}
+
+	return nil
+}
+
+func checkAttributeValue(definition model.AttributeDefinition, value any) error {
+	var text string
+	switch v := value.(type) {
+	case string:
+		if definition.Type != model.AttributeTypeString {
+			return fmt.Errorf("%w: %s must be a %s", ErrInvalidAttribute, definition.Name, definition.Type)
+		}
+		text = v
+	case float64:
+		if definition.Type != model.AttributeTypeNumber {
+			return fmt.Errorf("%w: %s must be a %s", ErrInvalidAttribute, definition.Name, definition.Type)
+		}
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if definition.Type != model.AttributeTypeBoolean {
+			return fmt.Errorf("%w: %s must be a %s", ErrInvalidAttribute, definition.Name, definition.Type)
+		}
+		text = strconv.FormatBool(v)
+	default:
+		return fmt.Errorf("%w: %s must be a %s", ErrInvalidAttribute, definition.Name, definition.Type)
+	}
+
+	if len(definition.AllowedValues) > 0 && !slices.Contains(definition.AllowedValues, text) {
+		return fmt.Errorf("%w: %s must be one of %v", ErrInvalidAttribute, definition.Name, definition.AllowedValues)
+	}
+
+	return nil
+}
+
+func checkAttributeType(attributeType, value string) error {
+	switch attributeType {
+	case model.AttributeTypeNumber:
+		_, err := strconv.ParseFloat(value, 64)
+		return err
+	case model.AttributeTypeBoolean:
+		_, err := strconv.ParseBool(value)
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (s *Service) CreateCategory(ctx context.Context, createCategory dto.CreateCategory) (*model.Category, error) {
+	return s.storage.CreateCategory(ctx, createCategory)
+}
+
+// GetCategoryTree returns root categories with their subcategories nested
+func (s *Service) GetCategoryTree(ctx context.Context) ([]model.Category, error) {
+	categories, err := s.storage.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCategoryTree(categories), nil
+}
+
+func (s *Service) GetCategoryByID(ctx context.Context, id int) (*model.Category, error) {
+	return s.storage.GetCategoryByID(ctx, id)
+}
+
+func (s *Service) UpdateCategory(ctx context.Context, updateCategory *dto.UpdateCategory) error {
+	return s.storage.UpdateCategory(ctx, updateCategory)
+}
+
+func (s *Service) DeleteCategory(ctx context.Context, id int) error {
+	return s.storage.DeleteCategory(ctx, id)
+}
+
+func buildCategoryTree(categories []model.Category) []model.Category {
+	children := make(map[int][]model.Category)
+	var roots []model.Category
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	var attach func(nodes []model.Category) []model.Category
+	attach = func(nodes []model.Category) []model.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	return attach(roots)
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"wharehouse-control/internal/config"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+)
+
+// RequestItemUpdate checks update made by manager against approval rules,
+// update that breaks any of them is saved as pending change request and
+// returned, nil request means update may be applied right away
+func (s *Service) RequestItemUpdate(ctx context.Context, updateItem *dto.UpdateItem) (*model.ChangeRequest, error) {
+	item, err := s.storage.GetItemByID(ctx, updateItem.ID)
+	if err != nil {
+		return nil, err
+	}
+	if item.DeletedAt != nil {
+		return nil, repository.ErrItemArchived
+	}
+	if updateItem.Version != nil && *updateItem.Version != item.Version {
+		return nil, repository.ErrVersionConflict
+	}
+
+	var reasons []string
+	if config.Cfg.Approvals.RenameRequiresApproval && updateItem.Name != nil && *updateItem.Name != item.Name {
+		reasons = append(reasons, fmt.Sprintf("item is renamed from %q to %q", item.Name, *updateItem.Name))
+	}
+
+	original := itemOriginal(item, updateItem)
+	if updateItem.Count != nil {
+		if err := checkCountWarehouse(item, updateItem); err != nil {
+			return nil, err
+		}
+
+		current, err := s.currentQuantity(ctx, item, warehouseOrDefault(updateItem.WarehouseID), updateItem.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		original.Quantity = &current
+
+		reason, err := s.quantityApprovalReason(ctx, item, updateItem, current)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+
+	changes, err := json.Marshal(updateItem)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal item changes: %w", err)
+	}
+	originalValues, err := json.Marshal(original)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal original item values: %w", err)
+	}
+
+	return s.storage.CreateChangeRequest(ctx, dto.CreateChangeRequest{
+		ItemID:   item.ID,
+		Kind:     model.ChangeRequestKindUpdate,
+		Changes:  changes,
+		Original: originalValues,
+		Version:  item.Version,
+		Reasons:  reasons,
+		UserID:   &updateItem.UserID,
+	})
+}
+
+// itemOriginal keeps current values of fields update changes,
+// quantity is added by caller as it depends on warehouse
+func itemOriginal(item *model.Item, updateItem *dto.UpdateItem) dto.ItemOriginal {
+	var original dto.ItemOriginal
+	if updateItem.Name != nil {
+		original.Name = &item.Name
+	}
+	if updateItem.CategoryID != nil {
+		original.CategoryID = item.CategoryID
+	}
+	if updateItem.Attributes != nil {
+		original.Attributes = item.Attributes
+	}
+	if updateItem.Serialized != nil {
+		original.Serialized = &item.Serialized
+	}
+
+	return original
+}
+
+// quantityApprovalReason compares count being set with current quantity
+// at the same warehouse or location, empty reason means change is small
+func (s *Service) quantityApprovalReason(ctx context.Context, item *model.Item, updateItem *dto.UpdateItem, current float64) (string, error) {
+	rules := config.Cfg.Approvals
+	if rules.QuantityChangePercent <= 0 && rules.QuantityChangeUnits <= 0 {
+		return "", nil
+	}
+
+	count, err := s.normalizeItemQuantity(ctx, item, updateItem.CountUnit, *updateItem.Count)
+	if err != nil {
+		return "", err
+	}
+
+	return quantityChangeReason(rules, item.Unit, current, count), nil
+}
+
+// quantityChangeReason tells which rule change of stock from current
+// to count breaks, empty reason means change is within both limits
+func quantityChangeReason(rules config.ApprovalsConfig, unit string, current, count float64) string {
+	change := math.Abs(count - current)
+	switch {
+	case rules.QuantityChangeUnits > 0 && change > rules.QuantityChangeUnits:
+		return fmt.Sprintf("quantity changes by %g %s, more than %g allowed", change, unit, rules.QuantityChangeUnits)
+	case rules.QuantityChangePercent > 0 && change > 0 && (current == 0 || change/math.Abs(current)*100 > rules.QuantityChangePercent):
+		return fmt.Sprintf("quantity changes from %g to %g %s, more than %g%% allowed", current, count, unit, rules.QuantityChangePercent)
+	}
+
+	return ""
+}
+
+// RequestItemAdjust checks stock adjustment made by manager against
+// quantity rules the same way as count set by update, adjustment that
+// breaks them is saved as pending change request and returned
+func (s *Service) RequestItemAdjust(ctx context.Context, adjustItem *dto.AdjustItem) (*model.ChangeRequest, error) {
+	rules := config.Cfg.Approvals
+	if rules.QuantityChangePercent <= 0 && rules.QuantityChangeUnits <= 0 {
+		return nil, nil
+	}
+
+	item, err := s.storage.GetItemByID(ctx, adjustItem.ID)
+	if err != nil {
+		return nil, err
+	}
+	if item.DeletedAt != nil {
+		return nil, repository.ErrItemArchived
+	}
+
+	delta, err := s.normalizeItemQuantity(ctx, item, adjustItem.Unit, adjustItem.Delta)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.currentQuantity(ctx, item, warehouseOrDefault(adjustItem.WarehouseID), adjustItem.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := quantityChangeReason(rules, item.Unit, current, current+delta)
+	if reason == "" {
+		return nil, nil
+	}
+
+	changes, err := json.Marshal(adjustItem)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal stock adjustment: %w", err)
+	}
+
+	return s.storage.CreateChangeRequest(ctx, dto.CreateChangeRequest{
+		ItemID:  item.ID,
+		Kind:    model.ChangeRequestKindAdjust,
+		Changes: changes,
+		Version: item.Version,
+		Reasons: []string{reason},
+		UserID:  &adjustItem.UserID,
+	})
+}
+
+func (s *Service) currentQuantity(ctx context.Context, item *model.Item, warehouseID int, locationID *int) (float64, error) {
+	if locationID == nil {
+		for _, level := range item.Stock {
+			if level.WarehouseID == warehouseID {
+				return level.Quantity, nil
+			}
+		}
+		return 0, nil
+	}
+
+	itemLocations, err := s.storage.GetItemLocations(ctx, item.ID)
+	if err != nil {
+		return 0, err
+	}
+	for _, itemLocation := range itemLocations {
+		if itemLocation.LocationID != nil && *itemLocation.LocationID == *locationID {
+			return itemLocation.Quantity, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// RequestItemDelete saves deletion made by manager as pending change
+// request when deletions need approval, nil request means item may
+// be deleted right away
+func (s *Service) RequestItemDelete(ctx context.Context, id, version int, userID *int) (*model.ChangeRequest, error) {
+	if !config.Cfg.Approvals.DeleteRequiresApproval {
+		return nil, nil
+	}
+
+	item, err := s.storage.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.DeletedAt != nil {
+		return nil, repository.ErrItemArchived
+	}
+	if version != item.Version {
+		return nil, repository.ErrVersionConflict
+	}
+
+	return s.storage.CreateChangeRequest(ctx, dto.CreateChangeRequest{
+		ItemID:  item.ID,
+		Kind:    model.ChangeRequestKindDelete,
+		Changes: []byte("{}"),
+		Version: item.Version,
+		Reasons: []string{"item is deleted"},
+		UserID:  userID,
+	})
+}
+
+func (s *Service) GetChangeRequests(ctx context.Context, filter dto.ChangeRequestFilter) ([]model.ChangeRequest, error) {
+	return s.storage.GetChangeRequests(ctx, filter)
+}
+
+func (s *Service) GetChangeRequestByID(ctx context.Context, id int) (*model.ChangeRequest, error) {
+	return s.storage.GetChangeRequestByID(ctx, id)
+}
+
+// ApproveChangeRequest applies requested change. Update is checked
+// against original values of fields it changes, so request outdated by
+// later changes of those fields fails with conflict and can only be
+// rejected, while unrelated changes like stock movements do not block
+// it. Stock adjustment is relative and deletion archives item as it
+// is, both are applied to current item. Change is applied and request
+// is closed at once, so request rejected in the meantime is never applied
+func (s *Service) ApproveChangeRequest(ctx context.Context, review dto.ReviewChangeRequest) error {
+	changeRequest, err := s.storage.GetChangeRequestByID(ctx, review.ID)
+	if err != nil {
+		return err
+	}
+	if changeRequest.Status != model.ChangeRequestStatusPending {
+		return repository.ErrChangeRequestStatus
+	}
+
+	approve := dto.ApproveChangeRequest{Review: review}
+	switch changeRequest.Kind {
+	case model.ChangeRequestKindUpdate:
+		var updateItem dto.UpdateItem
+		if err := json.Unmarshal(changeRequest.Changes, &updateItem); err != nil {
+			return fmt.Errorf("could not unmarshal item changes: %w", err)
+		}
+		updateItem.ID = changeRequest.ItemID
+		updateItem.Version = nil
+
+		var original dto.ItemOriginal
+		if err := json.Unmarshal(changeRequest.Original, &original); err != nil {
+			return fmt.Errorf("could not unmarshal original item values: %w", err)
+		}
+
+		if err := s.prepareItemUpdate(ctx, &updateItem); err != nil {
+			return err
+		}
+		approve.UpdateItem = &updateItem
+		approve.Original = &original
+	case model.ChangeRequestKindAdjust:
+		var adjustItem dto.AdjustItem
+		if err := json.Unmarshal(changeRequest.Changes, &adjustItem); err != nil {
+			return fmt.Errorf("could not unmarshal stock adjustment: %w", err)
+		}
+		adjustItem.ID = changeRequest.ItemID
+
+		if err := s.prepareItemAdjustment(ctx, &adjustItem); err != nil {
+			return err
+		}
+		approve.AdjustItem = &adjustItem
+	case model.ChangeRequestKindDelete:
+		approve.DeleteItem = &dto.DeleteItem{
+			ID:     changeRequest.ItemID,
+			UserID: changeRequest.UserID,
+		}
+	}
+
+	return s.storage.ApproveChangeRequest(ctx, approve)
+}
+
+func (s *Service) RejectChangeRequest(ctx context.Context, review dto.ReviewChangeRequest) error {
+	return s.storage.ReviewChangeRequest(ctx, review, model.ChangeRequestStatusRejected)
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"wharehouse-control/internal/config"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+// fakeStorage embeds Storage so that tests only need to stub
+// the methods they exercise, calling anything else panics
+type fakeStorage struct {
+	Storage
+
+	item    *model.Item
+	units   []model.ItemUnit
+	created *dto.CreateChangeRequest
+}
+
+func (f *fakeStorage) GetItemByID(ctx context.Context, id int) (*model.Item, error) {
+	return f.item, nil
+}
+
+func (f *fakeStorage) GetItemUnits(ctx context.Context, itemID int) ([]model.ItemUnit, error) {
+	return f.units, nil
+}
+
+func (f *fakeStorage) GetUnit(ctx context.Context, code string) (*model.Unit, error) {
+	return &model.Unit{Code: code, AllowDecimal: true}, nil
+}
+
+func (f *fakeStorage) CreateChangeRequest(ctx context.Context, createRequest dto.CreateChangeRequest) (*model.ChangeRequest, error) {
+	f.created = &createRequest
+	return &model.ChangeRequest{
+		ItemID:  createRequest.ItemID,
+		Kind:    createRequest.Kind,
+		Version: createRequest.Version,
+		Reasons: createRequest.Reasons,
+		Status:  model.ChangeRequestStatusPending,
+	}, nil
+}
+
+// withApprovals sets approval rules for the test and puts old ones back
+func withApprovals(t *testing.T, rules config.ApprovalsConfig) {
+	previous := config.Cfg.Approvals
+	config.Cfg.Approvals = rules
+	t.Cleanup(func() { config.Cfg.Approvals = previous })
+}
+
+func TestQuantityChangeReason(t *testing.T) {
+	tests := []struct {
+		name           string
+		rules          config.ApprovalsConfig
+		current        float64
+		count          float64
+		expectedReason string
+	}{
+		{
+			name:           "within percent",
+			rules:          config.ApprovalsConfig{QuantityChangePercent: 10},
+			current:        100,
+			count:          110,
+			expectedReason: "",
+		},
+		{
+			name:           "over percent",
+			rules:          config.ApprovalsConfig{QuantityChangePercent: 10},
+			current:        100,
+			count:          89,
+			expectedReason: "quantity changes from 100 to 89 pcs, more than 10% allowed",
+		},
+		{
+			name:           "any change of empty stock",
+			rules:          config.ApprovalsConfig{QuantityChangePercent: 10},
+			current:        0,
+			count:          1,
+			expectedReason: "quantity changes from 0 to 1 pcs, more than 10% allowed",
+		},
+		{
+			name:           "within units",
+			rules:          config.ApprovalsConfig{QuantityChangeUnits: 100},
+			current:        1000,
+			count:          900,
+			expectedReason: "",
+		},
+		{
+			name:           "over units",
+			rules:          config.ApprovalsConfig{QuantityChangeUnits: 100},
+			current:        1000,
+			count:          1101,
+			expectedReason: "quantity changes by 101 pcs, more than 100 allowed",
+		},
+		{
+			name:           "units checked before percent",
+			rules:          config.ApprovalsConfig{QuantityChangePercent: 10, QuantityChangeUnits: 100},
+			current:        100,
+			count:          300,
+			expectedReason: "quantity changes by 200 pcs, more than 100 allowed",
+		},
+		{
+			name:           "rules off",
+			rules:          config.ApprovalsConfig{},
+			current:        100,
+			count:          1000,
+			expectedReason: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := quantityChangeReason(tt.rules, "pcs", tt.current, tt.count)
+			if reason != tt.expectedReason {
+				t.Errorf("expected reason %q, got %q", tt.expectedReason, reason)
+			}
+		})
+	}
+}
+
+func TestService_RequestItemUpdate(t *testing.T) {
+	name := func(s string) *string { return &s }
+	count := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name            string
+		rules           config.ApprovalsConfig
+		updateItem      dto.UpdateItem
+		expectedRequest bool
+		expectedReasons int
+	}{
+		{
+			name:            "rename needs approval",
+			rules:           config.ApprovalsConfig{RenameRequiresApproval: true},
+			updateItem:      dto.UpdateItem{Name: name("hex bolt")},
+			expectedRequest: true,
+			expectedReasons: 1,
+		},
+		{
+			name:            "rename allowed",
+			rules:           config.ApprovalsConfig{RenameRequiresApproval: false},
+			updateItem:      dto.UpdateItem{Name: name("hex bolt")},
+			expectedRequest: false,
+		},
+		{
+			name:            "same name is not rename",
+			rules:           config.ApprovalsConfig{RenameRequiresApproval: true},
+			updateItem:      dto.UpdateItem{Name: name("bolt")},
+			expectedRequest: false,
+		},
+		{
+			name:            "small count change",
+			rules:           config.ApprovalsConfig{QuantityChangePercent: 10, QuantityChangeUnits: 100},
+			updateItem:      dto.UpdateItem{Count: count(105)},
+			expectedRequest: false,
+		},
+		{
+			name:            "count over percent",
+			rules:           config.ApprovalsConfig{QuantityChangePercent: 10},
+			updateItem:      dto.UpdateItem{Count: count(150)},
+			expectedRequest: true,
+			expectedReasons: 1,
+		},
+		{
+			name:            "count over units",
+			rules:           config.ApprovalsConfig{QuantityChangeUnits: 20},
+			updateItem:      dto.UpdateItem{Count: count(130)},
+			expectedRequest: true,
+			expectedReasons: 1,
+		},
+		{
+			name:            "rename and count together",
+			rules:           config.ApprovalsConfig{QuantityChangePercent: 10, RenameRequiresApproval: true},
+			updateItem:      dto.UpdateItem{Name: name("hex bolt"), Count: count(150)},
+			expectedRequest: true,
+			expectedReasons: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withApprovals(t, tt.rules)
+
+			storage := &fakeStorage{item: &model.Item{
+				ID:      1,
+				Name:    "bolt",
+				Unit:    "pcs",
+				Version: 3,
+				Stock:   []model.StockLevel{{WarehouseID: config.Cfg.Warehouse.DefaultID, Quantity: 100}},
+			}}
+			s := New(storage, nil)
+
+			updateItem := tt.updateItem
+			updateItem.ID = 1
+			updateItem.UserID = 2
+			changeRequest, err := s.RequestItemUpdate(context.Background(), &updateItem)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if (changeRequest != nil) != tt.expectedRequest {
+				t.Fatalf("expected change request %v, got %v", tt.expectedRequest, changeRequest != nil)
+			}
+			if changeRequest == nil {
+				return
+			}
+			if storage.created.Kind != model.ChangeRequestKindUpdate || storage.created.Version != 3 {
+				t.Errorf("unexpected change request %+v", storage.created)
+			}
+			if len(changeRequest.Reasons) != tt.expectedReasons {
+				t.Errorf("expected %d reasons, got %v", tt.expectedReasons, changeRequest.Reasons)
+			}
+
+			var original dto.ItemOriginal
+			if err := json.Unmarshal(storage.created.Original, &original); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (original.Name != nil) != (tt.updateItem.Name != nil) || original.Name != nil && *original.Name != "bolt" {
+				t.Errorf("expected original name of renamed item only, got %v", original.Name)
+			}
+			if (original.Quantity != nil) != (tt.updateItem.Count != nil) || original.Quantity != nil && *original.Quantity != 100 {
+				t.Errorf("expected original quantity of counted item only, got %v", original.Quantity)
+			}
+		})
+	}
+}
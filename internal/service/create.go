@@ -2,11 +2,43 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"wharehouse-control/internal/dto"
 	"wharehouse-control/internal/model"
 )
 
 func (s *Service) CreateItem(ctx context.Context, createItem dto.CreateItem) (*model.Item, error) {
+	if err := s.validateAttributes(ctx, createItem.CategoryID, createItem.Attributes); err != nil {
+		return nil, err
+	}
+
+	if createItem.Unit == "" {
+		createItem.Unit = model.DefaultUnit
+	}
+
+	itemUnits := make([]model.ItemUnit, 0, len(createItem.Units))
+	for _, unit := range createItem.Units {
+		if unit.Unit == createItem.Unit {
+			return nil, fmt.Errorf("%w: %s is the base unit of item", ErrInvalidQuantity, unit.Unit)
+		}
+		itemUnits = append(itemUnits, model.ItemUnit{Unit: unit.Unit, Factor: unit.Factor})
+	}
+
+	count, err := s.toBaseQuantity(ctx, createItem.Unit, itemUnits, createItem.CountUnit, createItem.Count)
+	if err != nil {
+		return nil, err
+	}
+	if count < 0 {
+		return nil, fmt.Errorf("%w: initial count cannot be negative", ErrInvalidQuantity)
+	}
+	if createItem.UnitCost != nil && count > 0 {
+		unitCost := *createItem.UnitCost * createItem.Count / count
+		createItem.UnitCost = &unitCost
+	}
+	createItem.Count = count
+	createItem.WarehouseID = warehouseOrDefault(createItem.WarehouseID)
+	createItem.NameScope = nameScope()
+
 	return s.storage.CreateItem(ctx, createItem)
 }
 
@@ -1,7 +1,32 @@
 package service
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
-func (s *Service) DeleteItem(ctx context.Context, id int) error {
-	return s.storage.DeleteItem(ctx, id)
+func (s *Service) DeleteItem(ctx context.Context, id, version int, userID *int) error {
+	return s.storage.DeleteItem(ctx, id, version, userID)
+}
+
+func (s *Service) RestoreItem(ctx context.Context, id int) (int, error) {
+	return s.storage.RestoreItem(ctx, id, nameScope())
+}
+
+// PurgeItem removes files of item attachments once item is gone
+// from db, every file is tried even if some of them fail
+func (s *Service) PurgeItem(ctx context.Context, id int) error {
+	attachments, err := s.storage.PurgeItem(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, attachment := range attachments {
+		if err := s.deleteAttachmentFiles(ctx, attachment.StorageKey, attachment.ThumbnailKey); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
 }
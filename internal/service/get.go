@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"wharehouse-control/internal/dto"
 	"wharehouse-control/internal/model"
 )
 
@@ -9,8 +10,26 @@ func (s *Service) GetUsersWithChanges(ctx context.Context) ([]model.UserHistory,
 	return s.storage.GetUsersWithChanges(ctx)
 }
 
-func (s *Service) GetAllItems(ctx context.Context) ([]model.Item, error) {
-	return s.storage.GetAllItems(ctx)
+func (s *Service) GetAllItems(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error) {
+	if filter.ViewID != nil && filter.UserID != nil {
+		var err error
+		filter, err = s.applySavedView(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := normalizeTags(filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+	filter.Tags = tags
+
+	return s.storage.GetAllItems(ctx, filter)
+}
+
+func (s *Service) GetItemByID(ctx context.Context, id int) (*model.Item, error) {
+	return s.storage.GetItemByID(ctx, id)
 }
 
 func (s *Service) GetUserRole(ctx context.Context, id int) (string, error) {
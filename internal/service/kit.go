@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+)
+
+// SetKitComponents replaces bill of materials of kit, component
+// quantities are stored in base units of components
+func (s *Service) SetKitComponents(ctx context.Context, setComponents *dto.SetKitComponents) (*model.Kit, error) {
+	kit, err := s.storage.GetItemByID(ctx, setComponents.KitID)
+	if err != nil {
+		return nil, err
+	}
+	if kit.DeletedAt != nil {
+		return nil, repository.ErrItemArchived
+	}
+	if kit.Serialized && len(setComponents.Components) > 0 {
+		return nil, fmt.Errorf("%w: serialized item cannot be a kit", ErrInvalidKit)
+	}
+
+	seen := make(map[int]bool, len(setComponents.Components))
+	for i := range setComponents.Components {
+		component := &setComponents.Components[i]
+		if component.ItemID == kit.ID {
+			return nil, fmt.Errorf("%w: kit cannot be its own component", ErrInvalidKit)
+		}
+		if seen[component.ItemID] {
+			return nil, fmt.Errorf("%w: component %d is listed twice", ErrInvalidKit, component.ItemID)
+		}
+		seen[component.ItemID] = true
+
+		item, err := s.storage.GetItemByID(ctx, component.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if item.DeletedAt != nil {
+			return nil, fmt.Errorf("%w: component %d", repository.ErrItemArchived, item.ID)
+		}
+		if item.Serialized {
+			return nil, fmt.Errorf("%w: serialized item %d cannot be a component", ErrInvalidKit, item.ID)
+		}
+
+		quantity, err := s.normalizeItemQuantity(ctx, item, component.Unit, component.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		component.Quantity = quantity
+	}
+
+	if err := s.storage.SetKitComponents(ctx, setComponents); err != nil {
+		return nil, err
+	}
+
+	return s.GetKit(ctx, kit.ID, 0)
+}
+
+// GetKit returns bill of materials of kit with number of kits that
+// can be assembled from available component stock of the warehouse
+func (s *Service) GetKit(ctx context.Context, kitID, warehouseID int) (*model.Kit, error) {
+	kit, err := s.storage.GetItemByID(ctx, kitID)
+	if err != nil {
+		return nil, err
+	}
+
+	kitUnit, err := s.storage.GetUnit(ctx, kit.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	warehouseID = warehouseOrDefault(warehouseID)
+	components, err := s.storage.GetKitComponents(ctx, kitID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.Kit{
+		ItemID:      kitID,
+		WarehouseID: warehouseID,
+		Components:  []model.KitComponent{},
+	}
+	for i, component := range components {
+		kits := math.Max(component.Available, 0) / component.Quantity
+		if kitUnit.AllowDecimal {
+			kits = roundQuantity(kits)
+		} else {
+			kits = math.Floor(kits)
+		}
+		component.Kits = kits
+
+		if i == 0 || kits < result.Available {
+			result.Available = kits
+		}
+		result.Components = append(result.Components, component)
+	}
+
+	return result, nil
+}
+
+func (s *Service) AssembleKit(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error) {
+	assembleKit.Disassemble = false
+	return s.moveKitStock(ctx, assembleKit)
+}
+
+func (s *Service) DisassembleKit(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error) {
+	assembleKit.Disassemble = true
+	return s.moveKitStock(ctx, assembleKit)
+}
+
+func (s *Service) moveKitStock(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error) {
+	kit, err := s.storage.GetItemByID(ctx, assembleKit.KitID)
+	if err != nil {
+		return nil, err
+	}
+	if kit.DeletedAt != nil {
+		return nil, repository.ErrItemArchived
+	}
+
+	quantity, err := s.normalizeItemQuantity(ctx, kit, "", assembleKit.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	assembleKit.Quantity = quantity
+	assembleKit.WarehouseID = warehouseOrDefault(assembleKit.WarehouseID)
+
+	return s.storage.AssembleKit(ctx, assembleKit)
+}
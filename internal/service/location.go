@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (s *Service) CreateLocation(ctx context.Context, createLocation dto.CreateLocation) (*model.Location, error) {
+	if createLocation.ParentID != nil {
+		parent, err := s.storage.GetLocationByID(ctx, *createLocation.ParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if parent.WarehouseID != createLocation.WarehouseID {
+			return nil, fmt.Errorf("%w: parent location is in another warehouse", ErrInvalidLocation)
+		}
+
+		// zone holds aisles, aisle holds racks and so on,
+		// levels may be skipped but never go back up
+		parentLevel := slices.Index(model.LocationLevels, parent.Type)
+		level := slices.Index(model.LocationLevels, createLocation.Type)
+		if level <= parentLevel {
+			return nil, fmt.Errorf("%w: %s cannot be placed inside %s", ErrInvalidLocation, createLocation.Type, parent.Type)
+		}
+	}
+
+	return s.storage.CreateLocation(ctx, createLocation)
+}
+
+func (s *Service) GetLocations(ctx context.Context, warehouseID int) ([]model.Location, error) {
+	if _, err := s.storage.GetWarehouseByID(ctx, warehouseID); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetLocations(ctx, warehouseID)
+}
+
+func (s *Service) DeleteLocation(ctx context.Context, warehouseID, id int) error {
+	return s.storage.DeleteLocation(ctx, warehouseID, id)
+}
+
+func (s *Service) GetItemLocations(ctx context.Context, itemID int) ([]model.ItemLocation, error) {
+	if _, err := s.storage.GetItemByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetItemLocations(ctx, itemID)
+}
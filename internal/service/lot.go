@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+const maxExpiringDays = 3650
+
+func (s *Service) CreateLot(ctx context.Context, createLot dto.CreateLot) (*model.Lot, error) {
+	if createLot.ManufacturedAt != nil && createLot.ExpiresAt != nil && *createLot.ManufacturedAt > *createLot.ExpiresAt {
+		return nil, fmt.Errorf("%w: lot cannot expire before it is manufactured", ErrInvalidLot)
+	}
+
+	return s.storage.CreateLot(ctx, createLot)
+}
+
+func (s *Service) GetLots(ctx context.Context, itemID int) ([]model.Lot, error) {
+	if _, err := s.storage.GetItemByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetLots(ctx, itemID)
+}
+
+// SuggestPick spreads requested quantity over lots first-expired-first-out
+func (s *Service) SuggestPick(ctx context.Context, pickRequest dto.PickRequest) (*model.PickSuggestion, error) {
+	item, err := s.storage.GetItemByID(ctx, pickRequest.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	requested, err := s.normalizeItemQuantity(ctx, item, pickRequest.Unit, pickRequest.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if requested <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidQuantity)
+	}
+
+	warehouseID := warehouseOrDefault(pickRequest.WarehouseID)
+	lots, err := s.storage.GetPickableLots(ctx, item.ID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion := &model.PickSuggestion{
+		ItemID:      item.ID,
+		WarehouseID: warehouseID,
+		Requested:   requested,
+		Picks:       []model.LotPick{},
+	}
+
+	remaining := requested
+	for _, lot := range lots {
+		if remaining <= 0 {
+			break
+		}
+
+		lot.Quantity = math.Min(lot.Available, remaining)
+		remaining = roundQuantity(remaining - lot.Quantity)
+		suggestion.Picks = append(suggestion.Picks, lot)
+	}
+	suggestion.Shortage = remaining
+
+	return suggestion, nil
+}
+
+func (s *Service) GetExpiringLots(ctx context.Context, days int, warehouseID *int) ([]model.ExpiringLot, error) {
+	if days < 0 || days > maxExpiringDays {
+		return nil, fmt.Errorf("%w: days must be between 0 and %d", ErrInvalidLot, maxExpiringDays)
+	}
+
+	return s.storage.GetExpiringLots(ctx, days, warehouseID)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"wharehouse-control/internal/config"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+const (
+	defaultDuplicateThreshold = 0.5
+	defaultDuplicateLimit     = 50
+)
+
+// GetDuplicateItems suggests pairs of items that are likely the same
+// thing under different names, threshold comes from configuration
+// when filter has none
+func (s *Service) GetDuplicateItems(ctx context.Context, filter dto.DuplicateFilter) ([]model.DuplicatePair, error) {
+	if filter.Threshold == 0 {
+		filter.Threshold = config.Cfg.Naming.SimilarityThreshold
+	}
+	if filter.Threshold <= 0 || filter.Threshold > 1 {
+		filter.Threshold = defaultDuplicateThreshold
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultDuplicateLimit
+	}
+
+	return s.storage.GetDuplicateItems(ctx, filter)
+}
+
+// MergeItems merges source item into target and returns target
+// with stock of both
+func (s *Service) MergeItems(ctx context.Context, mergeItems dto.MergeItems) (*model.Item, error) {
+	if mergeItems.SourceID == mergeItems.TargetID {
+		return nil, fmt.Errorf("%w: item cannot be merged into itself", ErrInvalidMerge)
+	}
+
+	if err := s.storage.MergeItems(ctx, mergeItems); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetItemByID(ctx, mergeItems.TargetID)
+}
+
+// nameScope is where item names must be unique, everywhere
+// unless configuration says otherwise
+func nameScope() string {
+	if scope := config.Cfg.Naming.UniqueScope; scope != "" {
+		return scope
+	}
+	return model.NameScopeGlobal
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"wharehouse-control/internal/config"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+const (
+	defaultMovementsLimit = 100
+	maxMovementsLimit     = 1000
+)
+
+func (s *Service) GetMovements(ctx context.Context, filter dto.MovementFilter) ([]model.Movement, error) {
+	if _, err := s.storage.GetItemByID(ctx, filter.ItemID); err != nil {
+		return nil, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultMovementsLimit
+	}
+	if filter.Limit > maxMovementsLimit {
+		filter.Limit = maxMovementsLimit
+	}
+
+	return s.storage.GetMovements(ctx, filter)
+}
+
+func (s *Service) AdjustItem(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error) {
+	if err := s.prepareItemAdjustment(ctx, adjustItem); err != nil {
+		return nil, err
+	}
+
+	return s.storage.AdjustItem(ctx, adjustItem)
+}
+
+// prepareItemAdjustment brings delta and unit cost to base unit of item
+// and fills in warehouse and stock settings from configuration
+func (s *Service) prepareItemAdjustment(ctx context.Context, adjustItem *dto.AdjustItem) error {
+	item, err := s.storage.GetItemByID(ctx, adjustItem.ID)
+	if err != nil {
+		return err
+	}
+
+	delta, err := s.normalizeItemQuantity(ctx, item, adjustItem.Unit, adjustItem.Delta)
+	if err != nil {
+		return err
+	}
+	if delta == 0 {
+		return fmt.Errorf("%w: delta is too small", ErrInvalidQuantity)
+	}
+
+	if adjustItem.UnitCost != nil {
+		if delta < 0 {
+			return fmt.Errorf("%w: unit cost is only for stock that comes in", ErrInvalidCost)
+		}
+		unitCost := *adjustItem.UnitCost * adjustItem.Delta / delta
+		adjustItem.UnitCost = &unitCost
+	}
+
+	adjustItem.Delta = delta
+	adjustItem.WarehouseID = warehouseOrDefault(adjustItem.WarehouseID)
+	adjustItem.AllowNegative = config.Cfg.Stock.AllowNegative
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+)
+
+func (s *Service) CreateOutboundOrder(ctx context.Context, createOrder dto.CreateOutboundOrder) (*model.OutboundOrder, error) {
+	createOrder.WarehouseID = warehouseOrDefault(createOrder.WarehouseID)
+	if _, err := s.storage.GetWarehouseByID(ctx, createOrder.WarehouseID); err != nil {
+		return nil, err
+	}
+
+	ordered := make(map[int]bool, len(createOrder.Lines))
+	for i := range createOrder.Lines {
+		line := &createOrder.Lines[i]
+		if ordered[line.ItemID] {
+			return nil, fmt.Errorf("%w: item %d is ordered twice", ErrInvalidOutboundOrder, line.ItemID)
+		}
+		ordered[line.ItemID] = true
+
+		item, err := s.storage.GetItemByID(ctx, line.ItemID)
+		if err != nil {
+			return nil, err
+		}
+
+		quantity, err := s.normalizeItemQuantity(ctx, item, line.Unit, line.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		if quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity is too small", ErrInvalidQuantity)
+		}
+		line.Quantity = quantity
+	}
+
+	return s.storage.CreateOutboundOrder(ctx, createOrder)
+}
+
+func (s *Service) GetOutboundOrders(ctx context.Context, filter dto.OutboundOrderFilter) ([]model.OutboundOrder, error) {
+	return s.storage.GetOutboundOrders(ctx, filter)
+}
+
+func (s *Service) GetOutboundOrderByID(ctx context.Context, id int) (*model.OutboundOrder, error) {
+	return s.storage.GetOutboundOrderByID(ctx, id)
+}
+
+// GeneratePickList builds pick list for order lines from current stock:
+// lots are taken first-expired-first-out, locations in route order
+// and the whole list is sorted so picker walks the warehouse once
+func (s *Service) GeneratePickList(ctx context.Context, orderID int) (*model.OutboundOrder, error) {
+	order, err := s.storage.GetOutboundOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.OutboundOrderStatusNew && order.Status != model.OutboundOrderStatusPicking {
+		return nil, repository.ErrOutboundOrderStatus
+	}
+
+	var picks []model.Pick
+	for _, line := range order.Lines {
+		linePicks, err := s.pickLine(ctx, order.WarehouseID, line)
+		if err != nil {
+			return nil, err
+		}
+
+		picks = append(picks, linePicks...)
+	}
+
+	// picks without location, stock that is not put away and lots,
+	// go last after the walk through locations
+	sort.SliceStable(picks, func(i, j int) bool {
+		if (picks[i].LocationID == nil) != (picks[j].LocationID == nil) {
+			return picks[j].LocationID == nil
+		}
+		return picks[i].Path < picks[j].Path
+	})
+	for i := range picks {
+		picks[i].Sequence = i + 1
+	}
+
+	if err := s.storage.SavePickList(ctx, orderID, picks); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetOutboundOrderByID(ctx, orderID)
+}
+
+// pickLine spreads line quantity over locations and lots of warehouse
+func (s *Service) pickLine(ctx context.Context, warehouseID int, line model.OutboundOrderLine) ([]model.Pick, error) {
+	itemLocations, err := s.storage.GetItemLocations(ctx, line.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []model.ItemLocation
+	var total float64
+	for _, location := range itemLocations {
+		if location.WarehouseID != warehouseID || location.Quantity <= 0 {
+			continue
+		}
+
+		locations = append(locations, location)
+		total += location.Quantity
+	}
+
+	lots, err := s.storage.GetPickableLots(ctx, line.ItemID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	lotted := 0.0
+	for _, lot := range lots {
+		lotted += lot.Available
+	}
+
+	picks := allocatePicks(line, locations, lots, roundQuantity(total-lotted))
+
+	picked := 0.0
+	for _, pick := range picks {
+		picked += pick.Quantity
+	}
+	if shortage := roundQuantity(line.Quantity - picked); shortage > 0 {
+		return nil, fmt.Errorf("%w: %v of item %d cannot be picked", repository.ErrInsufficientStock, shortage, line.ItemID)
+	}
+
+	return picks, nil
+}
+
+// allocatePicks takes lots first-expired-first-out and then stock not
+// tracked in lots from locations. Lot stock is kept per warehouse, not
+// per location, so lot picks leave location unset and packer tells
+// where the lot was taken from
+func allocatePicks(line model.OutboundOrderLine, locations []model.ItemLocation, lots []model.LotPick, unlotted float64) []model.Pick {
+	var picks []model.Pick
+
+	remaining := line.Quantity
+	for _, lot := range lots {
+		if remaining <= 0 {
+			break
+		}
+
+		quantity := math.Min(remaining, lot.Available)
+		lotID, lotNumber := lot.LotID, lot.LotNumber
+		picks = append(picks, model.Pick{
+			OrderID:   line.OrderID,
+			LineID:    line.ID,
+			ItemID:    line.ItemID,
+			LotID:     &lotID,
+			LotNumber: &lotNumber,
+			Quantity:  quantity,
+		})
+		remaining = roundQuantity(remaining - quantity)
+	}
+
+	for _, location := range locations {
+		if remaining <= 0 || unlotted <= 0 {
+			break
+		}
+
+		quantity := math.Min(remaining, math.Min(location.Quantity, unlotted))
+		picks = append(picks, model.Pick{
+			OrderID:    line.OrderID,
+			LineID:     line.ID,
+			ItemID:     line.ItemID,
+			LocationID: location.LocationID,
+			Path:       location.Path,
+			Quantity:   quantity,
+		})
+		remaining = roundQuantity(remaining - quantity)
+		unlotted = roundQuantity(unlotted - quantity)
+	}
+
+	return picks
+}
+
+func (s *Service) PackOutboundOrder(ctx context.Context, packOrder *dto.PackOutboundOrder) error {
+	return s.storage.PackOutboundOrder(ctx, packOrder)
+}
+
+func (s *Service) ShipOutboundOrder(ctx context.Context, shipOrder *dto.ShipOutboundOrder) error {
+	return s.storage.ShipOutboundOrder(ctx, shipOrder)
+}
+
+func (s *Service) CancelOutboundOrder(ctx context.Context, id int) error {
+	return s.storage.CancelOutboundOrder(ctx, id)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"wharehouse-control/internal/model"
+)
+
+func TestAllocatePicks(t *testing.T) {
+	bin := func(id int) *int { return &id }
+
+	locations := []model.ItemLocation{
+		{WarehouseID: 1, LocationID: bin(10), Path: "A/1", Quantity: 6},
+		{WarehouseID: 1, LocationID: bin(11), Path: "A/2", Quantity: 4},
+	}
+
+	tests := []struct {
+		name     string
+		quantity float64
+		lots     []model.LotPick
+		unlotted float64
+		expected []model.Pick
+	}{
+		{
+			name:     "stock without lots is taken from locations",
+			quantity: 8,
+			unlotted: 10,
+			expected: []model.Pick{
+				{LocationID: bin(10), Path: "A/1", Quantity: 6},
+				{LocationID: bin(11), Path: "A/2", Quantity: 2},
+			},
+		},
+		{
+			name:     "lots are not tied to locations",
+			quantity: 5,
+			lots:     []model.LotPick{{LotID: 1, LotNumber: "L1", Available: 3}, {LotID: 2, LotNumber: "L2", Available: 7}},
+			expected: []model.Pick{
+				{Quantity: 3},
+				{Quantity: 2},
+			},
+		},
+		{
+			name:     "stock without lots after lots",
+			quantity: 9,
+			lots:     []model.LotPick{{LotID: 1, LotNumber: "L1", Available: 7}},
+			unlotted: 3,
+			expected: []model.Pick{
+				{Quantity: 7},
+				{LocationID: bin(10), Path: "A/1", Quantity: 2},
+			},
+		},
+		{
+			name:     "shortage",
+			quantity: 12,
+			lots:     []model.LotPick{{LotID: 1, LotNumber: "L1", Available: 7}},
+			unlotted: 3,
+			expected: []model.Pick{
+				{Quantity: 7},
+				{LocationID: bin(10), Path: "A/1", Quantity: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := model.OutboundOrderLine{ID: 1, OrderID: 1, ItemID: 1, Quantity: tt.quantity}
+			picks := allocatePicks(line, locations, tt.lots, tt.unlotted)
+
+			if len(picks) != len(tt.expected) {
+				t.Fatalf("expected %d picks, got %+v", len(tt.expected), picks)
+			}
+			for i, pick := range picks {
+				expected := tt.expected[i]
+				if pick.Quantity != expected.Quantity || pick.Path != expected.Path {
+					t.Errorf("pick %d: expected %+v, got %+v", i, expected, pick)
+				}
+				if (pick.LocationID == nil) != (expected.LocationID == nil) ||
+					pick.LocationID != nil && *pick.LocationID != *expected.LocationID {
+					t.Errorf("pick %d: expected location %v, got %v", i, expected.LocationID, pick.LocationID)
+				}
+				if (pick.LotID != nil) != (pick.LocationID == nil) {
+					t.Errorf("pick %d: expected either lot or location, got %+v", i, pick)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+)
+
+// lowStockOrderReference marks orders created from low stock list
+const lowStockOrderReference = "low stock"
+
+func (s *Service) CreatePurchaseOrder(ctx context.Context, createOrder dto.CreatePurchaseOrder) (*model.PurchaseOrder, error) {
+	if _, err := s.storage.GetSupplierByID(ctx, createOrder.SupplierID); err != nil {
+		return nil, err
+	}
+
+	createOrder.WarehouseID = warehouseOrDefault(createOrder.WarehouseID)
+
+	ordered := make(map[int]bool, len(createOrder.Lines))
+	for i := range createOrder.Lines {
+		line := &createOrder.Lines[i]
+		if ordered[line.ItemID] {
+			return nil, fmt.Errorf("%w: item %d is ordered twice", ErrInvalidPurchaseOrder, line.ItemID)
+		}
+		ordered[line.ItemID] = true
+
+		if err := s.resolvePurchaseOrderLine(ctx, createOrder.SupplierID, line); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.storage.CreatePurchaseOrder(ctx, createOrder)
+}
+
+// resolvePurchaseOrderLine converts line quantity and price into
+// item base unit and takes missing price from supplier price list
+func (s *Service) resolvePurchaseOrderLine(ctx context.Context, supplierID int, line *dto.PurchaseOrderLine) error {
+	item, err := s.storage.GetItemByID(ctx, line.ItemID)
+	if err != nil {
+		return err
+	}
+
+	quantity, err := s.normalizeItemQuantity(ctx, item, line.Unit, line.Quantity)
+	if err != nil {
+		return err
+	}
+	if quantity <= 0 {
+		return fmt.Errorf("%w: quantity is too small", ErrInvalidQuantity)
+	}
+	if line.Price != nil {
+		price := *line.Price * line.Quantity / quantity
+		line.Price = &price
+	}
+	line.Quantity = quantity
+
+	if line.Price == nil {
+		supplierItem, err := s.storage.GetSupplierItem(ctx, supplierID, line.ItemID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNoSuchSupplierItem) {
+				return fmt.Errorf("%w: no price for item %d", ErrInvalidPurchaseOrder, line.ItemID)
+			}
+			return err
+		}
+		line.Price = &supplierItem.Price
+	}
+
+	return nil
+}
+
+func (s *Service) GetPurchaseOrders(ctx context.Context, filter dto.PurchaseOrderFilter) ([]model.PurchaseOrder, error) {
+	return s.storage.GetPurchaseOrders(ctx, filter)
+}
+
+func (s *Service) GetPurchaseOrderByID(ctx context.Context, id int) (*model.PurchaseOrder, error) {
+	return s.storage.GetPurchaseOrderByID(ctx, id)
+}
+
+func (s *Service) AddPurchaseOrderLine(ctx context.Context, addLine dto.AddPurchaseOrderLine) (*model.PurchaseOrderLine, error) {
+	order, err := s.storage.GetPurchaseOrderByID(ctx, addLine.PurchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.resolvePurchaseOrderLine(ctx, order.SupplierID, &addLine.PurchaseOrderLine); err != nil {
+		return nil, err
+	}
+
+	return s.storage.AddPurchaseOrderLine(ctx, addLine)
+}
+
+func (s *Service) DeletePurchaseOrderLine(ctx context.Context, orderID, lineID int) error {
+	return s.storage.DeletePurchaseOrderLine(ctx, orderID, lineID)
+}
+
+func (s *Service) SendPurchaseOrder(ctx context.Context, id int) error {
+	return s.storage.SendPurchaseOrder(ctx, id)
+}
+
+func (s *Service) ClosePurchaseOrder(ctx context.Context, id int) error {
+	return s.storage.ClosePurchaseOrder(ctx, id)
+}
+
+// CreateLowStockOrders drafts one order per supplier and warehouse for
+// stock below reorder point. Every item goes to its cheapest supplier,
+// quantity already ordered and not received yet is not ordered again
+func (s *Service) CreateLowStockOrders(ctx context.Context, createOrders dto.CreateLowStockOrders) (*model.LowStockOrders, error) {
+	lowStock, err := s.GetLowStock(ctx, createOrders.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	type orderKey struct{ supplierID, warehouseID int }
+	var keys []orderKey
+	orders := make(map[orderKey]*dto.CreatePurchaseOrder)
+	result := &model.LowStockOrders{Orders: []model.PurchaseOrder{}, Unsourced: []model.LowStockItem{}}
+
+	for _, low := range lowStock {
+		suppliers, err := s.storage.GetItemSuppliers(ctx, low.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if len(suppliers) == 0 {
+			result.Unsourced = append(result.Unsourced, low)
+			continue
+		}
+		supplier := suppliers[0]
+
+		ordered, err := s.storage.GetOpenOrderedQuantity(ctx, low.ItemID, low.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+
+		quantity, err := s.lowStockOrderQuantity(ctx, low, supplier, ordered)
+		if err != nil {
+			return nil, err
+		}
+		if quantity <= 0 {
+			continue
+		}
+
+		// rule for total stock is delivered to the default warehouse
+		key := orderKey{supplier.SupplierID, warehouseOrDefault(0)}
+		if low.WarehouseID != nil {
+			key.warehouseID = *low.WarehouseID
+		}
+
+		order, ok := orders[key]
+		if !ok {
+			order = &dto.CreatePurchaseOrder{
+				SupplierID:  key.supplierID,
+				UserID:      createOrders.UserID,
+				WarehouseID: key.warehouseID,
+				Reference:   lowStockOrderReference,
+			}
+			orders[key] = order
+			keys = append(keys, key)
+		}
+
+		// item can be low both in the warehouse and in total
+		merged := false
+		for i := range order.Lines {
+			if order.Lines[i].ItemID == low.ItemID {
+				order.Lines[i].Quantity = max(order.Lines[i].Quantity, quantity)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			price := supplier.Price
+			order.Lines = append(order.Lines, dto.PurchaseOrderLine{ItemID: low.ItemID, Quantity: quantity, Price: &price})
+		}
+	}
+
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	createPurchaseOrders := make([]dto.CreatePurchaseOrder, 0, len(keys))
+	for _, key := range keys {
+		createPurchaseOrders = append(createPurchaseOrders, *orders[key])
+	}
+
+	result.Orders, err = s.storage.CreatePurchaseOrders(ctx, createPurchaseOrders)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// lowStockOrderQuantity is suggested quantity less what is already
+// on the way, raised to supplier minimum and to whole base units
+func (s *Service) lowStockOrderQuantity(ctx context.Context, low model.LowStockItem, supplier model.SupplierItem, ordered float64) (float64, error) {
+	quantity := roundQuantity(low.SuggestedQuantity - ordered)
+	if quantity <= 0 {
+		return 0, nil
+	}
+	quantity = max(quantity, supplier.MinOrderQuantity)
+
+	item, err := s.storage.GetItemByID(ctx, low.ItemID)
+	if err != nil {
+		return 0, err
+	}
+
+	unit, err := s.storage.GetUnit(ctx, item.Unit)
+	if err != nil {
+		return 0, err
+	}
+	if !unit.AllowDecimal {
+		quantity = math.Ceil(quantity)
+	}
+
+	return quantity, nil
+}
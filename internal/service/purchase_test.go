@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (f *fakeStorage) GetSupplierItem(ctx context.Context, supplierID, itemID int) (*model.SupplierItem, error) {
+	return &model.SupplierItem{SupplierID: supplierID, ItemID: itemID, Price: 2}, nil
+}
+
+func TestService_ResolvePurchaseOrderLine(t *testing.T) {
+	price := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name             string
+		line             dto.PurchaseOrderLine
+		expectedQuantity float64
+		expectedPrice    float64
+	}{
+		{
+			name:             "base unit",
+			line:             dto.PurchaseOrderLine{Quantity: 10, Price: price(3)},
+			expectedQuantity: 10,
+			expectedPrice:    3,
+		},
+		{
+			name:             "price of box is converted to price of piece",
+			line:             dto.PurchaseOrderLine{Quantity: 2, Unit: "box", Price: price(120)},
+			expectedQuantity: 48,
+			expectedPrice:    5,
+		},
+		{
+			name:             "price list is already per base unit",
+			line:             dto.PurchaseOrderLine{Quantity: 2, Unit: "box"},
+			expectedQuantity: 48,
+			expectedPrice:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &fakeStorage{
+				item:  &model.Item{ID: 1, Unit: "pcs"},
+				units: []model.ItemUnit{{ItemID: 1, Unit: "box", Factor: 24}},
+			}
+			s := New(storage, nil)
+
+			line := tt.line
+			line.ItemID = 1
+			if err := s.resolvePurchaseOrderLine(context.Background(), 1, &line); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if line.Quantity != tt.expectedQuantity {
+				t.Errorf("expected quantity %v, got %v", tt.expectedQuantity, line.Quantity)
+			}
+			if *line.Price != tt.expectedPrice {
+				t.Errorf("expected price %v, got %v", tt.expectedPrice, *line.Price)
+			}
+		})
+	}
+}
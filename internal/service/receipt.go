@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"wharehouse-control/internal/config"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+)
+
+func (s *Service) CreateReceipt(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error) {
+	order, err := s.storage.GetPurchaseOrderByID(ctx, createReceipt.PurchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.PurchaseOrderStatusSent && order.Status != model.PurchaseOrderStatusPartiallyReceived {
+		return nil, repository.ErrPurchaseOrderStatus
+	}
+
+	orderLines := make(map[int]model.PurchaseOrderLine, len(order.Lines))
+	for _, line := range order.Lines {
+		orderLines[line.ID] = line
+	}
+
+	received := make(map[int]bool, len(createReceipt.Lines))
+	for i := range createReceipt.Lines {
+		line := &createReceipt.Lines[i]
+		orderLine, ok := orderLines[line.PurchaseOrderLineID]
+		if !ok {
+			return nil, repository.ErrNoSuchPurchaseOrderLine
+		}
+		if received[line.PurchaseOrderLineID] {
+			return nil, fmt.Errorf("%w: line %d is received twice", ErrInvalidReceipt, line.PurchaseOrderLineID)
+		}
+		received[line.PurchaseOrderLineID] = true
+
+		item, err := s.storage.GetItemByID(ctx, orderLine.ItemID)
+		if err != nil {
+			return nil, err
+		}
+
+		line.Quantity, err = s.normalizeItemQuantity(ctx, item, line.Unit, line.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		line.DamagedQuantity, err = s.normalizeItemQuantity(ctx, item, line.Unit, line.DamagedQuantity)
+		if err != nil {
+			return nil, err
+		}
+		if line.Quantity+line.DamagedQuantity <= 0 {
+			return nil, fmt.Errorf("%w: nothing is received for line %d", ErrInvalidReceipt, line.PurchaseOrderLineID)
+		}
+
+		line.ItemID = orderLine.ItemID
+	}
+
+	createReceipt.WarehouseID = order.WarehouseID
+	createReceipt.OverDeliveryTolerance = config.Cfg.Receiving.OverDeliveryTolerance
+
+	return s.storage.CreateReceipt(ctx, createReceipt)
+}
+
+func (s *Service) GetReceipts(ctx context.Context, filter dto.ReceiptFilter) ([]model.Receipt, error) {
+	return s.storage.GetReceipts(ctx, filter)
+}
+
+func (s *Service) GetReceiptByID(ctx context.Context, id int) (*model.Receipt, error) {
+	return s.storage.GetReceiptByID(ctx, id)
+}
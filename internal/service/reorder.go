@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (s *Service) SetReorderRule(ctx context.Context, setReorderRule dto.SetReorderRule) (*model.ReorderRule, error) {
+	if setReorderRule.MaxLevel != nil && *setReorderRule.MaxLevel < setReorderRule.MinLevel {
+		return nil, fmt.Errorf("%w: max level cannot be less than min level", ErrInvalidReorderRule)
+	}
+
+	return s.storage.SetReorderRule(ctx, setReorderRule)
+}
+
+func (s *Service) GetReorderRules(ctx context.Context, itemID int) ([]model.ReorderRule, error) {
+	if _, err := s.storage.GetItemByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetReorderRules(ctx, itemID)
+}
+
+func (s *Service) DeleteReorderRule(ctx context.Context, itemID int, warehouseID *int) error {
+	return s.storage.DeleteReorderRule(ctx, itemID, warehouseID)
+}
+
+func (s *Service) GetLowStock(ctx context.Context, warehouseID *int) ([]model.LowStockItem, error) {
+	items, err := s.storage.GetLowStock(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		items[i].SuggestedQuantity = suggestedOrderQuantity(&items[i])
+	}
+
+	return items, nil
+}
+
+// suggestedOrderQuantity fills stock up to max level when it is set,
+// otherwise orders fixed reorder quantity or just enough to reach min level
+func suggestedOrderQuantity(item *model.LowStockItem) float64 {
+	switch {
+	case item.MaxLevel != nil:
+		return roundQuantity(*item.MaxLevel - item.Quantity)
+	case item.ReorderQuantity != nil:
+		return roundQuantity(max(*item.ReorderQuantity, item.MinLevel-item.Quantity))
+	default:
+		return roundQuantity(item.MinLevel - item.Quantity)
+	}
+}
+
+func (s *Service) GetStockAlerts(ctx context.Context, onlyActive bool) ([]model.StockAlert, error) {
+	return s.storage.GetStockAlerts(ctx, onlyActive)
+}
+
+func (s *Service) AcknowledgeStockAlert(ctx context.Context, id int64) error {
+	return s.storage.AcknowledgeStockAlert(ctx, id)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wharehouse-control/internal/config"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (s *Service) CreateReservation(ctx context.Context, createReservation dto.CreateReservation) (*model.Reservation, error) {
+	item, err := s.storage.GetItemByID(ctx, createReservation.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	createReservation.WarehouseID = warehouseOrDefault(createReservation.WarehouseID)
+	if _, err := s.storage.GetWarehouseByID(ctx, createReservation.WarehouseID); err != nil {
+		return nil, err
+	}
+
+	quantity, err := s.normalizeItemQuantity(ctx, item, createReservation.Unit, createReservation.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity is too small", ErrInvalidQuantity)
+	}
+	createReservation.Quantity = quantity
+
+	if createReservation.ExpiresAt == nil {
+		expiresAt := time.Now().Add(config.Cfg.Reservations.DefaultTTL)
+		createReservation.ExpiresAt = &expiresAt
+	} else if !createReservation.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expiry time is in the past", ErrInvalidReservation)
+	}
+
+	return s.storage.CreateReservation(ctx, createReservation)
+}
+
+func (s *Service) GetReservations(ctx context.Context, filter dto.ReservationFilter) ([]model.Reservation, error) {
+	return s.storage.GetReservations(ctx, filter)
+}
+
+func (s *Service) ConfirmReservation(ctx context.Context, confirmReservation dto.ConfirmReservation) error {
+	return s.storage.ConfirmReservation(ctx, confirmReservation)
+}
+
+func (s *Service) ReleaseReservation(ctx context.Context, id int) error {
+	return s.storage.ReleaseReservation(ctx, id)
+}
+
+func (s *Service) ExpireReservations(ctx context.Context) (int64, error) {
+	return s.storage.ExpireReservations(ctx)
+}
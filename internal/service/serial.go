@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (s *Service) GetSerials(ctx context.Context, filter dto.SerialFilter) ([]model.Serial, error) {
+	if _, err := s.storage.GetItemByID(ctx, filter.ItemID); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetSerials(ctx, filter)
+}
+
+func (s *Service) GetSerialHistory(ctx context.Context, itemID int, serialNumber string) (*model.SerialHistory, error) {
+	return s.storage.GetSerialHistory(ctx, itemID, serialNumber)
+}
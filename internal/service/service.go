@@ -2,26 +2,162 @@ package service
 
 import (
 	"context"
+	"errors"
+	"wharehouse-control/internal/blob"
 	"wharehouse-control/internal/dto"
 	"wharehouse-control/internal/model"
 )
 
+var (
+	ErrInvalidAttribute = errors.New("invalid item attributes")
+	ErrInvalidQuantity  = errors.New("invalid quantity")
+	ErrInvalidLocation  = errors.New("invalid location")
+
+	ErrInvalidReservation = errors.New("invalid reservation")
+	ErrInvalidLot         = errors.New("invalid lot")
+	ErrInvalidReorderRule = errors.New("invalid reorder rule")
+
+	ErrInvalidPurchaseOrder = errors.New("invalid purchase order")
+	ErrInvalidReceipt       = errors.New("invalid receipt")
+
+	ErrInvalidOutboundOrder = errors.New("invalid outbound order")
+	ErrInvalidTransferOrder = errors.New("invalid transfer order")
+	ErrInvalidStocktake     = errors.New("invalid stocktake")
+	ErrInvalidKit           = errors.New("invalid kit")
+	ErrInvalidCost          = errors.New("invalid cost")
+	ErrInvalidAttachment    = errors.New("invalid attachment")
+	ErrAttachmentTooLarge   = errors.New("attachment is too large")
+	ErrInvalidTag           = errors.New("invalid tag")
+	ErrInvalidMerge         = errors.New("invalid merge")
+)
+
 type Storage interface {
 	CreateItem(ctx context.Context, createItem dto.CreateItem) (*model.Item, error)
 	CreateUser(ctx context.Context, createUser dto.CreateUser) (*model.User, error)
 	GetUsersWithChanges(ctx context.Context) ([]model.UserHistory, error)
-	GetAllItems(ctx context.Context) ([]model.Item, error)
+	GetAllItems(ctx context.Context, filter dto.ItemFilter) ([]model.Item, error)
+	GetItemByID(ctx context.Context, id int) (*model.Item, error)
 	GetUserRole(ctx context.Context, id int) (string, error)
 	UpdateItem(ctx context.Context, updateItem *dto.UpdateItem) error
-	DeleteItem(ctx context.Context, id int) error
+	DeleteItem(ctx context.Context, id, version int, userID *int) error
+	RestoreItem(ctx context.Context, id int, nameScope string) (int, error)
+	PurgeItem(ctx context.Context, id int) ([]model.Attachment, error)
+	CreateCategory(ctx context.Context, createCategory dto.CreateCategory) (*model.Category, error)
+	GetCategories(ctx context.Context) ([]model.Category, error)
+	GetCategoryByID(ctx context.Context, id int) (*model.Category, error)
+	UpdateCategory(ctx context.Context, updateCategory *dto.UpdateCategory) error
+	DeleteCategory(ctx context.Context, id int) error
+	CreateAttributeDefinition(ctx context.Context, createDefinition dto.CreateAttributeDefinition) (*model.AttributeDefinition, error)
+	GetAttributeDefinitions(ctx context.Context, categoryID int) ([]model.AttributeDefinition, error)
+	DeleteAttributeDefinition(ctx context.Context, categoryID, id int) error
+	CreateUnit(ctx context.Context, createUnit dto.CreateUnit) (*model.Unit, error)
+	GetUnits(ctx context.Context) ([]model.Unit, error)
+	GetUnit(ctx context.Context, code string) (*model.Unit, error)
+	SetItemUnit(ctx context.Context, itemUnit model.ItemUnit) error
+	GetItemUnits(ctx context.Context, itemID int) ([]model.ItemUnit, error)
+	DeleteItemUnit(ctx context.Context, itemID int, unit string) error
+	CreateWarehouse(ctx context.Context, createWarehouse dto.CreateWarehouse) (*model.Warehouse, error)
+	GetWarehouses(ctx context.Context) ([]model.Warehouse, error)
+	GetWarehouseByID(ctx context.Context, id int) (*model.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, updateWarehouse *dto.UpdateWarehouse) error
+	DeleteWarehouse(ctx context.Context, id int) error
+	CreateLocation(ctx context.Context, createLocation dto.CreateLocation) (*model.Location, error)
+	GetLocationByID(ctx context.Context, id int) (*model.Location, error)
+	GetLocations(ctx context.Context, warehouseID int) ([]model.Location, error)
+	DeleteLocation(ctx context.Context, warehouseID, id int) error
+	GetItemLocations(ctx context.Context, itemID int) ([]model.ItemLocation, error)
+	GetMovements(ctx context.Context, filter dto.MovementFilter) ([]model.Movement, error)
+	AdjustItem(ctx context.Context, adjustItem *dto.AdjustItem) (*model.Movement, error)
+	CreateReservation(ctx context.Context, createReservation dto.CreateReservation) (*model.Reservation, error)
+	GetReservations(ctx context.Context, filter dto.ReservationFilter) ([]model.Reservation, error)
+	ConfirmReservation(ctx context.Context, confirmReservation dto.ConfirmReservation) error
+	ReleaseReservation(ctx context.Context, id int) error
+	ExpireReservations(ctx context.Context) (int64, error)
+	CreateLot(ctx context.Context, createLot dto.CreateLot) (*model.Lot, error)
+	GetLots(ctx context.Context, itemID int) ([]model.Lot, error)
+	GetPickableLots(ctx context.Context, itemID, warehouseID int) ([]model.LotPick, error)
+	GetExpiringLots(ctx context.Context, days int, warehouseID *int) ([]model.ExpiringLot, error)
+	GetSerials(ctx context.Context, filter dto.SerialFilter) ([]model.Serial, error)
+	GetSerialHistory(ctx context.Context, itemID int, serialNumber string) (*model.SerialHistory, error)
+	SetReorderRule(ctx context.Context, setReorderRule dto.SetReorderRule) (*model.ReorderRule, error)
+	GetReorderRules(ctx context.Context, itemID int) ([]model.ReorderRule, error)
+	DeleteReorderRule(ctx context.Context, itemID int, warehouseID *int) error
+	GetLowStock(ctx context.Context, warehouseID *int) ([]model.LowStockItem, error)
+	GetStockAlerts(ctx context.Context, onlyActive bool) ([]model.StockAlert, error)
+	AcknowledgeStockAlert(ctx context.Context, id int64) error
+	CreateSupplier(ctx context.Context, createSupplier dto.CreateSupplier) (*model.Supplier, error)
+	GetSuppliers(ctx context.Context) ([]model.Supplier, error)
+	GetSupplierByID(ctx context.Context, id int) (*model.Supplier, error)
+	UpdateSupplier(ctx context.Context, updateSupplier *dto.UpdateSupplier) error
+	DeleteSupplier(ctx context.Context, id int) error
+	SetSupplierItem(ctx context.Context, setSupplierItem dto.SetSupplierItem) (*model.SupplierItem, error)
+	GetSupplierItems(ctx context.Context, supplierID int) ([]model.SupplierItem, error)
+	GetSupplierItem(ctx context.Context, supplierID, itemID int) (*model.SupplierItem, error)
+	GetItemSuppliers(ctx context.Context, itemID int) ([]model.SupplierItem, error)
+	DeleteSupplierItem(ctx context.Context, supplierID, itemID int) error
+	CreatePurchaseOrder(ctx context.Context, createOrder dto.CreatePurchaseOrder) (*model.PurchaseOrder, error)
+	CreatePurchaseOrders(ctx context.Context, createOrders []dto.CreatePurchaseOrder) ([]model.PurchaseOrder, error)
+	GetPurchaseOrders(ctx context.Context, filter dto.PurchaseOrderFilter) ([]model.PurchaseOrder, error)
+	GetPurchaseOrderByID(ctx context.Context, id int) (*model.PurchaseOrder, error)
+	AddPurchaseOrderLine(ctx context.Context, addLine dto.AddPurchaseOrderLine) (*model.PurchaseOrderLine, error)
+	DeletePurchaseOrderLine(ctx context.Context, orderID, lineID int) error
+	SendPurchaseOrder(ctx context.Context, id int) error
+	ClosePurchaseOrder(ctx context.Context, id int) error
+	GetOpenOrderedQuantity(ctx context.Context, itemID int, warehouseID *int) (float64, error)
+	CreateReceipt(ctx context.Context, createReceipt *dto.CreateReceipt) (*model.Receipt, error)
+	GetReceipts(ctx context.Context, filter dto.ReceiptFilter) ([]model.Receipt, error)
+	GetReceiptByID(ctx context.Context, id int) (*model.Receipt, error)
+	CreateOutboundOrder(ctx context.Context, createOrder dto.CreateOutboundOrder) (*model.OutboundOrder, error)
+	GetOutboundOrders(ctx context.Context, filter dto.OutboundOrderFilter) ([]model.OutboundOrder, error)
+	GetOutboundOrderByID(ctx context.Context, id int) (*model.OutboundOrder, error)
+	SavePickList(ctx context.Context, orderID int, picks []model.Pick) error
+	PackOutboundOrder(ctx context.Context, packOrder *dto.PackOutboundOrder) error
+	ShipOutboundOrder(ctx context.Context, shipOrder *dto.ShipOutboundOrder) error
+	CancelOutboundOrder(ctx context.Context, id int) error
+	CreateTransferOrder(ctx context.Context, createOrder dto.CreateTransferOrder) (*model.TransferOrder, error)
+	GetTransferOrders(ctx context.Context, filter dto.TransferOrderFilter) ([]model.TransferOrder, error)
+	GetTransferOrderByID(ctx context.Context, id int) (*model.TransferOrder, error)
+	DispatchTransferOrder(ctx context.Context, dispatchOrder dto.DispatchTransferOrder) error
+	ReceiveTransferOrder(ctx context.Context, receiveOrder *dto.ReceiveTransferOrder) error
+	CancelTransferOrder(ctx context.Context, id int) error
+	CreateStocktake(ctx context.Context, createStocktake dto.CreateStocktake) (*model.Stocktake, error)
+	GetStocktakes(ctx context.Context, filter dto.StocktakeFilter) ([]model.Stocktake, error)
+	GetStocktakeByID(ctx context.Context, id int) (*model.Stocktake, error)
+	RecordStocktakeCounts(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error
+	ApproveStocktake(ctx context.Context, approveStocktake dto.ApproveStocktake) error
+	CancelStocktake(ctx context.Context, id int) error
+	SetKitComponents(ctx context.Context, setComponents *dto.SetKitComponents) error
+	GetKitComponents(ctx context.Context, kitID, warehouseID int) ([]model.KitComponent, error)
+	AssembleKit(ctx context.Context, assembleKit *dto.AssembleKit) ([]model.Movement, error)
+	GetValuation(ctx context.Context, filter dto.ValuationFilter) ([]model.ItemValuation, error)
+	CreateAttachment(ctx context.Context, createAttachment dto.CreateAttachment) (*model.Attachment, error)
+	GetAttachments(ctx context.Context, itemID int) ([]model.Attachment, error)
+	GetAttachmentByID(ctx context.Context, id int) (*model.Attachment, error)
+	DeleteAttachment(ctx context.Context, id int) (*model.Attachment, error)
+	SetItemTags(ctx context.Context, setTags *dto.SetItemTags) error
+	GetDuplicateItems(ctx context.Context, filter dto.DuplicateFilter) ([]model.DuplicatePair, error)
+	MergeItems(ctx context.Context, mergeItems dto.MergeItems) error
+	GetTags(ctx context.Context) ([]model.TagCount, error)
+	CreateSavedView(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error)
+	GetSavedViews(ctx context.Context, userID int) ([]model.SavedView, error)
+	GetSavedViewByID(ctx context.Context, id, userID int) (*model.SavedView, error)
+	UpdateSavedView(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error)
+	DeleteSavedView(ctx context.Context, id, userID int) error
+	CreateChangeRequest(ctx context.Context, createRequest dto.CreateChangeRequest) (*model.ChangeRequest, error)
+	GetChangeRequests(ctx context.Context, filter dto.ChangeRequestFilter) ([]model.ChangeRequest, error)
+	GetChangeRequestByID(ctx context.Context, id int) (*model.ChangeRequest, error)
+	ReviewChangeRequest(ctx context.Context, review dto.ReviewChangeRequest, status string) error
+	ApproveChangeRequest(ctx context.Context, approve dto.ApproveChangeRequest) error
 }
 
 type Service struct {
 	storage Storage
+	blobs   blob.Store
 }
 
-func New(storage Storage) *Service {
+func New(storage Storage, blobs blob.Store) *Service {
 	return &Service{
 		storage: storage,
+		blobs:   blobs,
 	}
 }
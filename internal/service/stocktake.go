@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+)
+
+// stocktakeLineKey is item at location, zero location is stock not put away
+type stocktakeLineKey struct {
+	itemID     int
+	locationID int
+}
+
+func (s *Service) CreateStocktake(ctx context.Context, createStocktake dto.CreateStocktake) (*model.Stocktake, error) {
+	createStocktake.WarehouseID = warehouseOrDefault(createStocktake.WarehouseID)
+	if _, err := s.storage.GetWarehouseByID(ctx, createStocktake.WarehouseID); err != nil {
+		return nil, err
+	}
+
+	if createStocktake.LocationID != nil {
+		location, err := s.storage.GetLocationByID(ctx, *createStocktake.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		if location.WarehouseID != createStocktake.WarehouseID {
+			return nil, repository.ErrLocationWarehouse
+		}
+	}
+
+	if createStocktake.CategoryID != nil {
+		if _, err := s.storage.GetCategoryByID(ctx, *createStocktake.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.storage.CreateStocktake(ctx, createStocktake)
+}
+
+func (s *Service) GetStocktakes(ctx context.Context, filter dto.StocktakeFilter) ([]model.Stocktake, error) {
+	return s.storage.GetStocktakes(ctx, filter)
+}
+
+func (s *Service) GetStocktakeByID(ctx context.Context, id int) (*model.Stocktake, error) {
+	return s.storage.GetStocktakeByID(ctx, id)
+}
+
+// GetStocktakeVariances returns counted lines that differ from snapshot
+func (s *Service) GetStocktakeVariances(ctx context.Context, id int) ([]model.StocktakeLine, error) {
+	stocktake, err := s.storage.GetStocktakeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	variances := []model.StocktakeLine{}
+	for _, line := range stocktake.Lines {
+		if line.Variance != nil && *line.Variance != 0 {
+			variances = append(variances, line)
+		}
+	}
+
+	return variances, nil
+}
+
+func (s *Service) RecordStocktakeCounts(ctx context.Context, recordCounts *dto.RecordStocktakeCounts) error {
+	counted := make(map[stocktakeLineKey]bool, len(recordCounts.Counts))
+	for i := range recordCounts.Counts {
+		count := &recordCounts.Counts[i]
+
+		key := stocktakeLineKey{itemID: count.ItemID}
+		if count.LocationID != nil {
+			key.locationID = *count.LocationID
+		}
+		if counted[key] {
+			return fmt.Errorf("%w: item %d is counted twice at the same location", ErrInvalidStocktake, count.ItemID)
+		}
+		counted[key] = true
+
+		item, err := s.storage.GetItemByID(ctx, count.ItemID)
+		if err != nil {
+			return err
+		}
+
+		count.Quantity, err = s.normalizeItemQuantity(ctx, item, count.Unit, count.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.storage.RecordStocktakeCounts(ctx, recordCounts)
+}
+
+func (s *Service) ApproveStocktake(ctx context.Context, approveStocktake dto.ApproveStocktake) error {
+	return s.storage.ApproveStocktake(ctx, approveStocktake)
+}
+
+func (s *Service) CancelStocktake(ctx context.Context, id int) error {
+	return s.storage.CancelStocktake(ctx, id)
+}
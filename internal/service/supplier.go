@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (s *Service) CreateSupplier(ctx context.Context, createSupplier dto.CreateSupplier) (*model.Supplier, error) {
+	return s.storage.CreateSupplier(ctx, createSupplier)
+}
+
+func (s *Service) GetSuppliers(ctx context.Context) ([]model.Supplier, error) {
+	return s.storage.GetSuppliers(ctx)
+}
+
+// GetSupplierByID returns supplier together with its price list
+func (s *Service) GetSupplierByID(ctx context.Context, id int) (*model.Supplier, error) {
+	supplier, err := s.storage.GetSupplierByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	supplier.Items, err = s.storage.GetSupplierItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return supplier, nil
+}
+
+func (s *Service) UpdateSupplier(ctx context.Context, updateSupplier *dto.UpdateSupplier) error {
+	return s.storage.UpdateSupplier(ctx, updateSupplier)
+}
+
+func (s *Service) DeleteSupplier(ctx context.Context, id int) error {
+	return s.storage.DeleteSupplier(ctx, id)
+}
+
+func (s *Service) SetSupplierItem(ctx context.Context, setSupplierItem dto.SetSupplierItem) (*model.SupplierItem, error) {
+	return s.storage.SetSupplierItem(ctx, setSupplierItem)
+}
+
+func (s *Service) DeleteSupplierItem(ctx context.Context, supplierID, itemID int) error {
+	return s.storage.DeleteSupplierItem(ctx, supplierID, itemID)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+)
+
+// SetItemTags replaces tags of item and returns item with them
+func (s *Service) SetItemTags(ctx context.Context, setTags *dto.SetItemTags) (*model.Item, error) {
+	item, err := s.storage.GetItemByID(ctx, setTags.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.DeletedAt != nil {
+		return nil, repository.ErrItemArchived
+	}
+
+	setTags.Tags, err = normalizeTags(setTags.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.SetItemTags(ctx, setTags); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetItemByID(ctx, setTags.ItemID)
+}
+
+func (s *Service) GetTags(ctx context.Context) ([]model.TagCount, error) {
+	return s.storage.GetTags(ctx)
+}
+
+// normalizeTags lowercases tags and collapses spaces in them so that
+// "Fragile" and " fragile " are the same tag, duplicates are dropped
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+		if tag == "" {
+			return nil, fmt.Errorf("%w: tag cannot be blank", ErrInvalidTag)
+		}
+		normalized = append(normalized, tag)
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
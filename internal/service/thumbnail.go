@@ -0,0 +1,82 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+)
+
+// maxImagePixels keeps small file that declares huge image
+// from taking all memory while it is decoded
+const maxImagePixels = 50_000_000
+
+// makeThumbnail scales image down to fit size x size box keeping
+// its proportions and encodes it as png, every pixel of thumbnail
+// is the average of source pixels it covers
+func makeThumbnail(data []byte, size int) ([]byte, error) {
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %w", err)
+	}
+	if imageConfig.Width*imageConfig.Height > maxImagePixels {
+		return nil, fmt.Errorf("image is too large to decode")
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %w", err)
+	}
+
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("image is empty")
+	}
+
+	thumbWidth, thumbHeight := width, height
+	if width > size || height > size {
+		if width >= height {
+			thumbWidth, thumbHeight = size, max(height*size/width, 1)
+		} else {
+			thumbWidth, thumbHeight = max(width*size/height, 1), size
+		}
+	}
+
+	thumbnail := image.NewNRGBA(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		top := bounds.Min.Y + y*height/thumbHeight
+		bottom := max(bounds.Min.Y+(y+1)*height/thumbHeight, top+1)
+		for x := 0; x < thumbWidth; x++ {
+			left := bounds.Min.X + x*width/thumbWidth
+			right := max(bounds.Min.X+(x+1)*width/thumbWidth, left+1)
+
+			var r, g, b, a, count uint64
+			for sy := top; sy < bottom; sy++ {
+				for sx := left; sx < right; sx++ {
+					pr, pg, pb, pa := source.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					count++
+				}
+			}
+
+			offset := thumbnail.PixOffset(x, y)
+			// averaged channels are alpha premultiplied, nrgba is not
+			if a > 0 {
+				thumbnail.Pix[offset] = uint8(r * 0xff / a)
+				thumbnail.Pix[offset+1] = uint8(g * 0xff / a)
+				thumbnail.Pix[offset+2] = uint8(b * 0xff / a)
+			}
+			thumbnail.Pix[offset+3] = uint8(a / count >> 8)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, thumbnail); err != nil {
+		return nil, fmt.Errorf("could not encode thumbnail: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+	"wharehouse-control/internal/repository"
+)
+
+// transferLineKey tells lines of the same item apart by lot
+type transferLineKey struct {
+	itemID int
+	lotID  int
+}
+
+func (s *Service) CreateTransferOrder(ctx context.Context, createOrder dto.CreateTransferOrder) (*model.TransferOrder, error) {
+	if _, err := s.storage.GetWarehouseByID(ctx, createOrder.SourceWarehouseID); err != nil {
+		return nil, err
+	}
+	if _, err := s.storage.GetWarehouseByID(ctx, createOrder.DestinationWarehouseID); err != nil {
+		return nil, err
+	}
+
+	transferred := make(map[transferLineKey]bool, len(createOrder.Lines))
+	for i := range createOrder.Lines {
+		line := &createOrder.Lines[i]
+
+		key := transferLineKey{itemID: line.ItemID}
+		if line.LotID != nil {
+			key.lotID = *line.LotID
+		}
+		if transferred[key] {
+			return nil, fmt.Errorf("%w: item %d is transferred twice", ErrInvalidTransferOrder, line.ItemID)
+		}
+		transferred[key] = true
+
+		item, err := s.storage.GetItemByID(ctx, line.ItemID)
+		if err != nil {
+			return nil, err
+		}
+
+		quantity, err := s.normalizeItemQuantity(ctx, item, line.Unit, line.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		if quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity is too small", ErrInvalidQuantity)
+		}
+		line.Quantity = quantity
+	}
+
+	return s.storage.CreateTransferOrder(ctx, createOrder)
+}
+
+func (s *Service) GetTransferOrders(ctx context.Context, filter dto.TransferOrderFilter) ([]model.TransferOrder, error) {
+	return s.storage.GetTransferOrders(ctx, filter)
+}
+
+func (s *Service) GetTransferOrderByID(ctx context.Context, id int) (*model.TransferOrder, error) {
+	return s.storage.GetTransferOrderByID(ctx, id)
+}
+
+func (s *Service) DispatchTransferOrder(ctx context.Context, dispatchOrder dto.DispatchTransferOrder) error {
+	return s.storage.DispatchTransferOrder(ctx, dispatchOrder)
+}
+
+func (s *Service) ReceiveTransferOrder(ctx context.Context, receiveOrder *dto.ReceiveTransferOrder) error {
+	order, err := s.storage.GetTransferOrderByID(ctx, receiveOrder.ID)
+	if err != nil {
+		return err
+	}
+	if order.Status != model.TransferOrderStatusInTransit {
+		return repository.ErrTransferOrderStatus
+	}
+
+	orderLines := make(map[int]model.TransferOrderLine, len(order.Lines))
+	for _, line := range order.Lines {
+		orderLines[line.ID] = line
+	}
+
+	received := make(map[int]bool, len(receiveOrder.Lines))
+	for i := range receiveOrder.Lines {
+		line := &receiveOrder.Lines[i]
+		orderLine, ok := orderLines[line.LineID]
+		if !ok {
+			return repository.ErrNoSuchTransferOrderLine
+		}
+		if received[line.LineID] {
+			return fmt.Errorf("%w: line %d is received twice", ErrInvalidTransferOrder, line.LineID)
+		}
+		received[line.LineID] = true
+
+		item, err := s.storage.GetItemByID(ctx, orderLine.ItemID)
+		if err != nil {
+			return err
+		}
+
+		line.Quantity, err = s.normalizeItemQuantity(ctx, item, line.Unit, line.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.storage.ReceiveTransferOrder(ctx, receiveOrder)
+}
+
+func (s *Service) CancelTransferOrder(ctx context.Context, id int) error {
+	return s.storage.CancelTransferOrder(ctx, id)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+// quantities are stored with three decimal places
+const quantityPrecision = 1000
+
+func (s *Service) CreateUnit(ctx context.Context, createUnit dto.CreateUnit) (*model.Unit, error) {
+	return s.storage.CreateUnit(ctx, createUnit)
+}
+
+func (s *Service) GetUnits(ctx context.Context) ([]model.Unit, error) {
+	return s.storage.GetUnits(ctx)
+}
+
+func (s *Service) SetItemUnit(ctx context.Context, itemUnit model.ItemUnit) error {
+	item, err := s.storage.GetItemByID(ctx, itemUnit.ItemID)
+	if err != nil {
+		return err
+	}
+
+	if item.Unit == itemUnit.Unit {
+		return fmt.Errorf("%w: %s is the base unit of item", ErrInvalidQuantity, itemUnit.Unit)
+	}
+
+	return s.storage.SetItemUnit(ctx, itemUnit)
+}
+
+func (s *Service) GetItemUnits(ctx context.Context, itemID int) ([]model.ItemUnit, error) {
+	if _, err := s.storage.GetItemByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetItemUnits(ctx, itemID)
+}
+
+func (s *Service) DeleteItemUnit(ctx context.Context, itemID int, unit string) error {
+	return s.storage.DeleteItemUnit(ctx, itemID, unit)
+}
+
+// normalizeItemQuantity converts quantity given in unitCode
+// into the base unit of already existing item
+func (s *Service) normalizeItemQuantity(ctx context.Context, item *model.Item, unitCode string, quantity float64) (float64, error) {
+	itemUnits, err := s.storage.GetItemUnits(ctx, item.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.toBaseQuantity(ctx, item.Unit, itemUnits, unitCode, quantity)
+}
+
+func (s *Service) toBaseQuantity(ctx context.Context, baseCode string, itemUnits []model.ItemUnit, unitCode string, quantity float64) (float64, error) {
+	baseUnit, err := s.storage.GetUnit(ctx, baseCode)
+	if err != nil {
+		return 0, err
+	}
+
+	factor := 1.0
+	if unitCode != "" && unitCode != baseCode {
+		found := false
+		for _, itemUnit := range itemUnits {
+			if itemUnit.Unit == unitCode {
+				factor, found = itemUnit.Factor, true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("%w: item cannot be measured in %s", ErrInvalidQuantity, unitCode)
+		}
+
+		unit, err := s.storage.GetUnit(ctx, unitCode)
+		if err != nil {
+			return 0, err
+		}
+		if !unit.AllowDecimal && !isWhole(quantity) {
+			return 0, fmt.Errorf("%w: %s must be a whole number", ErrInvalidQuantity, unitCode)
+		}
+	}
+
+	normalized := roundQuantity(quantity * factor)
+	if !baseUnit.AllowDecimal && !isWhole(normalized) {
+		return 0, fmt.Errorf("%w: %s must be a whole number", ErrInvalidQuantity, baseCode)
+	}
+
+	return normalized, nil
+}
+
+func roundQuantity(quantity float64) float64 {
+	return math.Round(quantity*quantityPrecision) / quantityPrecision
+}
+
+func isWhole(quantity float64) bool {
+	return quantity == math.Trunc(quantity)
+}
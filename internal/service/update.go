@@ -2,9 +2,78 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
 )
 
 func (s *Service) UpdateItem(ctx context.Context, updateItem *dto.UpdateItem) error {
+	if err := s.prepareItemUpdate(ctx, updateItem); err != nil {
+		return err
+	}
+
 	return s.storage.UpdateItem(ctx, updateItem)
 }
+
+// prepareItemUpdate validates update and brings its count to base
+// unit and warehouse, so that storage only has to apply it
+func (s *Service) prepareItemUpdate(ctx context.Context, updateItem *dto.UpdateItem) error {
+	updateItem.NameScope = nameScope()
+
+	if updateItem.Attributes == nil && updateItem.CategoryID == nil && updateItem.Count == nil {
+		return nil
+	}
+
+	item, err := s.storage.GetItemByID(ctx, updateItem.ID)
+	if err != nil {
+		return err
+	}
+
+	// category change may invalidate existing attributes
+	// so the resulting combination is always validated
+	if updateItem.Attributes != nil || updateItem.CategoryID != nil {
+		categoryID, attributes := item.CategoryID, item.Attributes
+		if updateItem.CategoryID != nil {
+			categoryID = updateItem.CategoryID
+		}
+		if updateItem.Attributes != nil {
+			attributes = updateItem.Attributes
+		}
+
+		if err := s.validateAttributes(ctx, categoryID, attributes); err != nil {
+			return err
+		}
+	}
+
+	if updateItem.Count != nil {
+		if err := checkCountWarehouse(item, updateItem); err != nil {
+			return err
+		}
+
+		count, err := s.normalizeItemQuantity(ctx, item, updateItem.CountUnit, *updateItem.Count)
+		if err != nil {
+			return err
+		}
+		updateItem.Count = &count
+		updateItem.WarehouseID = warehouseOrDefault(updateItem.WarehouseID)
+	}
+
+	return nil
+}
+
+// checkCountWarehouse refuses count given without warehouse for item
+// stocked outside the default one, as it cannot tell which stock is set
+func checkCountWarehouse(item *model.Item, updateItem *dto.UpdateItem) error {
+	if updateItem.WarehouseID != 0 {
+		return nil
+	}
+
+	defaultID := warehouseOrDefault(0)
+	for _, level := range item.Stock {
+		if level.WarehouseID != defaultID && level.Quantity != 0 {
+			return fmt.Errorf("%w: warehouse_id is required for item stocked in several warehouses", ErrInvalidQuantity)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"wharehouse-control/internal/config"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+// costPrecision matches scale of stored costs
+const costPrecision = 10000
+
+// GetValuation values stock on hand as of date and totals
+// its value per category and per warehouse
+func (s *Service) GetValuation(ctx context.Context, filter dto.ValuationFilter) (*model.Valuation, error) {
+	if filter.Method == "" {
+		filter.Method = config.Cfg.Valuation.Method
+	}
+	if filter.Method == "" {
+		filter.Method = model.ValuationMethodFIFO
+	}
+	if filter.Method != model.ValuationMethodFIFO && filter.Method != model.ValuationMethodAverage {
+		return nil, fmt.Errorf("%w: unknown valuation method %s", ErrInvalidCost, filter.Method)
+	}
+
+	items, err := s.storage.GetValuation(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	valuation := &model.Valuation{
+		AsOf:       filter.AsOf,
+		Method:     filter.Method,
+		Items:      []model.ItemValuation{},
+		Categories: []model.GroupValuation{},
+		Warehouses: []model.GroupValuation{},
+	}
+
+	// items without category are grouped under key 0
+	categories := make(map[int]int)
+	warehouses := make(map[int]int)
+	for _, item := range items {
+		if item.Quantity != 0 {
+			item.UnitCost = roundCost(item.Value / item.Quantity)
+		}
+		valuation.Items = append(valuation.Items, item)
+		valuation.Total += item.Value
+
+		var categoryID int
+		if item.CategoryID != nil {
+			categoryID = *item.CategoryID
+		}
+		categoryIndex, ok := categories[categoryID]
+		if !ok {
+			categoryIndex = len(valuation.Categories)
+			categories[categoryID] = categoryIndex
+			valuation.Categories = append(valuation.Categories, model.GroupValuation{ID: item.CategoryID, Name: item.CategoryName})
+		}
+		valuation.Categories[categoryIndex].Value += item.Value
+
+		warehouseIndex, ok := warehouses[item.WarehouseID]
+		if !ok {
+			warehouseIndex = len(valuation.Warehouses)
+			warehouses[item.WarehouseID] = warehouseIndex
+			warehouseID := item.WarehouseID
+			valuation.Warehouses = append(valuation.Warehouses, model.GroupValuation{ID: &warehouseID, Name: item.WarehouseName})
+		}
+		valuation.Warehouses[warehouseIndex].Value += item.Value
+	}
+
+	valuation.Total = roundCost(valuation.Total)
+	for i := range valuation.Categories {
+		valuation.Categories[i].Value = roundCost(valuation.Categories[i].Value)
+	}
+	for i := range valuation.Warehouses {
+		valuation.Warehouses[i].Value = roundCost(valuation.Warehouses[i].Value)
+	}
+
+	return valuation, nil
+}
+
+func roundCost(cost float64) float64 {
+	return math.Round(cost*costPrecision) / costPrecision
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (s *Service) CreateSavedView(ctx context.Context, saveView dto.SaveView) (*model.SavedView, error) {
+	var err error
+	saveView.Filter.Tags, err = normalizeTags(saveView.Filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storage.CreateSavedView(ctx, saveView)
+}
+
+func (s *Service) GetSavedViews(ctx context.Context, userID int) ([]model.SavedView, error) {
+	return s.storage.GetSavedViews(ctx, userID)
+}
+
+func (s *Service) GetSavedViewByID(ctx context.Context, id, userID int) (*model.SavedView, error) {
+	return s.storage.GetSavedViewByID(ctx, id, userID)
+}
+
+func (s *Service) UpdateSavedView(ctx context.Context, saveView *dto.SaveView) (*model.SavedView, error) {
+	var err error
+	saveView.Filter.Tags, err = normalizeTags(saveView.Filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storage.UpdateSavedView(ctx, *saveView)
+}
+
+func (s *Service) DeleteSavedView(ctx context.Context, id, userID int) error {
+	return s.storage.DeleteSavedView(ctx, id, userID)
+}
+
+// applySavedView fills item filter from saved view of user, category
+// and warehouse given in request win over the view, tags and attributes
+// of request are added to the view ones and narrow the list further
+func (s *Service) applySavedView(ctx context.Context, filter dto.ItemFilter) (dto.ItemFilter, error) {
+	view, err := s.storage.GetSavedViewByID(ctx, *filter.ViewID, *filter.UserID)
+	if err != nil {
+		return filter, err
+	}
+
+	var viewFilter dto.ViewFilter
+	if err := json.Unmarshal(view.Filter, &viewFilter); err != nil {
+		return filter, fmt.Errorf("could not unmarshal view filter: %w", err)
+	}
+
+	if filter.CategoryID == nil {
+		filter.CategoryID = viewFilter.CategoryID
+	}
+	if filter.WarehouseID == nil {
+		filter.WarehouseID = viewFilter.WarehouseID
+	}
+	filter.IncludeArchived = filter.IncludeArchived || viewFilter.IncludeArchived
+	filter.Tags = append(viewFilter.Tags, filter.Tags...)
+
+	if len(viewFilter.Attributes) > 0 {
+		attributes := maps.Clone(viewFilter.Attributes)
+		maps.Copy(attributes, filter.Attributes)
+		filter.Attributes = attributes
+	}
+
+	return filter, nil
+}
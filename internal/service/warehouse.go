@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"wharehouse-control/internal/config"
+	"wharehouse-control/internal/dto"
+	"wharehouse-control/internal/model"
+)
+
+func (s *Service) CreateWarehouse(ctx context.Context, createWarehouse dto.CreateWarehouse) (*model.Warehouse, error) {
+	return s.storage.CreateWarehouse(ctx, createWarehouse)
+}
+
+func (s *Service) GetWarehouses(ctx context.Context) ([]model.Warehouse, error) {
+	return s.storage.GetWarehouses(ctx)
+}
+
+func (s *Service) GetWarehouseByID(ctx context.Context, id int) (*model.Warehouse, error) {
+	return s.storage.GetWarehouseByID(ctx, id)
+}
+
+func (s *Service) UpdateWarehouse(ctx context.Context, updateWarehouse *dto.UpdateWarehouse) error {
+	return s.storage.UpdateWarehouse(ctx, updateWarehouse)
+}
+
+func (s *Service) DeleteWarehouse(ctx context.Context, id int) error {
+	return s.storage.DeleteWarehouse(ctx, id)
+}
+
+// warehouseOrDefault falls back to configured default
+// warehouse for clients that are not warehouse aware
+func warehouseOrDefault(id int) int {
+	if id == 0 {
+		return config.Cfg.Warehouse.DefaultID
+	}
+	return id
+}
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS units(
+    code VARCHAR(16) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    allow_decimal BOOLEAN NOT NULL DEFAULT FALSE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO units(code, name, allow_decimal) VALUES
+    ('pcs', 'piece', FALSE),
+    ('box', 'box', FALSE),
+    ('kg', 'kilogram', TRUE),
+    ('g', 'gram', TRUE),
+    ('m', 'meter', TRUE)
+ON CONFLICT (code) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE items ADD COLUMN IF NOT EXISTS unit VARCHAR(16) NOT NULL DEFAULT 'pcs' REFERENCES units(code);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE items ALTER COLUMN count TYPE NUMERIC(18, 3);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS item_units(
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    unit VARCHAR(16) NOT NULL REFERENCES units(code),
+    factor NUMERIC(18, 6) NOT NULL CHECK (factor > 0),
+    PRIMARY KEY (item_id, unit)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_units;
+ALTER TABLE items ALTER COLUMN count TYPE INT USING ROUND(count)::INT;
+ALTER TABLE items DROP COLUMN IF EXISTS unit;
+DROP TABLE IF EXISTS units;
+-- +goose StatementEnd
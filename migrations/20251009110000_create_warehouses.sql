@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS warehouses(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    address VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO warehouses(name) VALUES ('Main') ON CONFLICT (name) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS stock(
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    warehouse_id INT NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    quantity NUMERIC(18, 3) NOT NULL DEFAULT 0,
+    PRIMARY KEY (item_id, warehouse_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_stock_warehouse_id ON stock(warehouse_id);
+-- +goose StatementEnd
+
+-- items.count stays as the total over all warehouses,
+-- existing quantities are moved to the main warehouse
+-- +goose StatementBegin
+INSERT INTO stock(item_id, warehouse_id, quantity)
+SELECT i.id, w.id, i.count FROM items i CROSS JOIN warehouses w WHERE w.name = 'Main'
+ON CONFLICT (item_id, warehouse_id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stock;
+DROP TABLE IF EXISTS warehouses;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS locations(
+    id SERIAL PRIMARY KEY,
+    warehouse_id INT NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    parent_id INT REFERENCES locations(id) ON DELETE RESTRICT,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('zone', 'aisle', 'rack', 'bin')),
+    code VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX IF NOT EXISTS idx_locations_parent_code ON locations(parent_id, code) WHERE parent_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_locations_root_code ON locations(warehouse_id, code) WHERE parent_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS location_stock(
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    location_id INT NOT NULL REFERENCES locations(id) ON DELETE RESTRICT,
+    quantity NUMERIC(18, 3) NOT NULL DEFAULT 0,
+    PRIMARY KEY (item_id, location_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_location_stock_location_id ON location_stock(location_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS location_stock;
+DROP TABLE IF EXISTS locations;
+-- +goose StatementEnd
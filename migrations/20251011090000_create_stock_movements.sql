@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS stock_movements(
+    id BIGSERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    warehouse_id INT NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    location_id INT REFERENCES locations(id) ON DELETE RESTRICT,
+    quantity NUMERIC(18, 3) NOT NULL CHECK (quantity <> 0),
+    reason VARCHAR(32) NOT NULL,
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_stock_movements_item_id ON stock_movements(item_id, created_at);
+-- +goose StatementEnd
+
+-- existing quantities become opening balances so that
+-- on-hand stock always equals the sum of movements
+-- +goose StatementBegin
+INSERT INTO stock_movements(item_id, warehouse_id, location_id, quantity, reason)
+SELECT ls.item_id, l.warehouse_id, ls.location_id, ls.quantity, 'opening_balance'
+FROM location_stock ls JOIN locations l ON l.id = ls.location_id
+WHERE ls.quantity <> 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO stock_movements(item_id, warehouse_id, quantity, reason)
+SELECT s.item_id, s.warehouse_id, s.quantity - COALESCE((
+    SELECT SUM(ls.quantity) FROM location_stock ls
+    JOIN locations l ON l.id = ls.location_id
+    WHERE ls.item_id = s.item_id AND l.warehouse_id = s.warehouse_id
+), 0), 'opening_balance'
+FROM stock s
+WHERE s.quantity - COALESCE((
+    SELECT SUM(ls.quantity) FROM location_stock ls
+    JOIN locations l ON l.id = ls.location_id
+    WHERE ls.item_id = s.item_id AND l.warehouse_id = s.warehouse_id
+), 0) <> 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stock_movements;
+-- +goose StatementEnd
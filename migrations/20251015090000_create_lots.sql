@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS lots (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    lot_number VARCHAR(100) NOT NULL,
+    manufactured_at DATE,
+    expires_at DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (item_id, lot_number),
+    CHECK (manufactured_at IS NULL OR expires_at IS NULL OR manufactured_at <= expires_at)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS lot_stock (
+    lot_id INT NOT NULL REFERENCES lots(id) ON DELETE CASCADE,
+    warehouse_id INT NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    quantity NUMERIC(18, 3) NOT NULL DEFAULT 0,
+    PRIMARY KEY (lot_id, warehouse_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE stock_movements ADD COLUMN lot_id INT REFERENCES lots(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_lots_expires_at ON lots(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS lot_id;
+DROP TABLE IF EXISTS lot_stock;
+DROP TABLE IF EXISTS lots;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE items ADD COLUMN serialized BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS serials (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    serial_number VARCHAR(100) NOT NULL,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('in_stock', 'reserved', 'shipped', 'returned')),
+    warehouse_id INT REFERENCES warehouses(id) ON DELETE RESTRICT,
+    reservation_id INT REFERENCES reservations(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (item_id, serial_number)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS movement_serials (
+    movement_id BIGINT NOT NULL REFERENCES stock_movements(id) ON DELETE CASCADE,
+    serial_id INT NOT NULL REFERENCES serials(id) ON DELETE CASCADE,
+    PRIMARY KEY (movement_id, serial_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_movement_serials_serial_id ON movement_serials(serial_id);
+CREATE INDEX IF NOT EXISTS idx_serials_reservation_id ON serials(reservation_id) WHERE reservation_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movement_serials;
+DROP TABLE IF EXISTS serials;
+ALTER TABLE items DROP COLUMN IF EXISTS serialized;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reorder_rules (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    warehouse_id INT REFERENCES warehouses(id) ON DELETE CASCADE,
+    min_level NUMERIC(18, 3) NOT NULL CHECK (min_level >= 0),
+    max_level NUMERIC(18, 3) CHECK (max_level >= min_level),
+    reorder_quantity NUMERIC(18, 3) CHECK (reorder_quantity > 0)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- rule without warehouse applies to total item stock
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reorder_rules_item_warehouse ON reorder_rules(item_id, COALESCE(warehouse_id, 0));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS stock_alerts (
+    id BIGSERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    warehouse_id INT REFERENCES warehouses(id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('low_stock', 'restocked')),
+    quantity NUMERIC(18, 3) NOT NULL,
+    min_level NUMERIC(18, 3) NOT NULL,
+    movement_id BIGINT REFERENCES stock_movements(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    acknowledged_at TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_stock_alerts_unacknowledged ON stock_alerts(created_at) WHERE acknowledged_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stock_alerts;
+DROP TABLE IF EXISTS reorder_rules;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS receipts (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE RESTRICT,
+    warehouse_id INT NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    location_id INT REFERENCES locations(id) ON DELETE SET NULL,
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_receipts_purchase_order ON receipts(purchase_order_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- quantities are in item base unit, damaged quantity is not put into stock
+CREATE TABLE IF NOT EXISTS receipt_lines (
+    id SERIAL PRIMARY KEY,
+    receipt_id INT NOT NULL REFERENCES receipts(id) ON DELETE CASCADE,
+    purchase_order_line_id INT NOT NULL REFERENCES purchase_order_lines(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    quantity NUMERIC(18, 3) NOT NULL CHECK (quantity >= 0),
+    damaged_quantity NUMERIC(18, 3) NOT NULL DEFAULT 0 CHECK (damaged_quantity >= 0),
+    note TEXT NOT NULL DEFAULT '',
+    lot_id INT REFERENCES lots(id) ON DELETE SET NULL,
+    movement_id BIGINT REFERENCES stock_movements(id) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS receipt_lines;
+DROP TABLE IF EXISTS receipts;
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS outbound_orders (
+    id SERIAL PRIMARY KEY,
+    warehouse_id INT NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    customer VARCHAR(255) NOT NULL,
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(10) NOT NULL DEFAULT 'new' CHECK (status IN ('new', 'picking', 'packed', 'shipped', 'cancelled')),
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    package_count INT CHECK (package_count >= 0),
+    carrier VARCHAR(100) NOT NULL DEFAULT '',
+    tracking_number VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    shipped_at TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- quantities are in item base unit, lines of open orders hold stock like reservations
+CREATE TABLE IF NOT EXISTS outbound_order_lines (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES outbound_orders(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    quantity NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    UNIQUE (order_id, item_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_outbound_order_lines_item ON outbound_order_lines(item_id);
+CREATE INDEX IF NOT EXISTS idx_outbound_orders_open ON outbound_orders(warehouse_id) WHERE status IN ('new', 'picking', 'packed');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- pick list of order, sequence is the walking route through the warehouse
+CREATE TABLE IF NOT EXISTS outbound_picks (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES outbound_orders(id) ON DELETE CASCADE,
+    line_id INT NOT NULL REFERENCES outbound_order_lines(id) ON DELETE CASCADE,
+    sequence INT NOT NULL,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    location_id INT REFERENCES locations(id) ON DELETE SET NULL,
+    lot_id INT REFERENCES lots(id) ON DELETE SET NULL,
+    quantity NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    serials TEXT[] NOT NULL DEFAULT '{}',
+    movement_id BIGINT REFERENCES stock_movements(id) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_outbound_picks_order ON outbound_picks(order_id, sequence);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbound_picks;
+DROP TABLE IF EXISTS outbound_order_lines;
+DROP TABLE IF EXISTS outbound_orders;
+-- +goose StatementEnd
@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS transfer_orders (
+    id SERIAL PRIMARY KEY,
+    source_warehouse_id INT NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    destination_warehouse_id INT NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(10) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'in_transit', 'received', 'cancelled')),
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP,
+    received_at TIMESTAMP,
+    CHECK (source_warehouse_id <> destination_warehouse_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- quantities are in item base unit, received_quantity is set once
+-- the line arrives and anything below quantity is a discrepancy
+CREATE TABLE IF NOT EXISTS transfer_order_lines (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES transfer_orders(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    lot_id INT REFERENCES lots(id) ON DELETE RESTRICT,
+    quantity NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    received_quantity NUMERIC(18, 3) CHECK (received_quantity >= 0),
+    serials TEXT[] NOT NULL DEFAULT '{}',
+    note TEXT NOT NULL DEFAULT '',
+    dispatch_movement_id BIGINT REFERENCES stock_movements(id) ON DELETE SET NULL,
+    receipt_movement_id BIGINT REFERENCES stock_movements(id) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_transfer_order_lines_order ON transfer_order_lines(order_id);
+CREATE INDEX IF NOT EXISTS idx_transfer_order_lines_item ON transfer_order_lines(item_id);
+CREATE INDEX IF NOT EXISTS idx_transfer_orders_in_transit ON transfer_orders(destination_warehouse_id) WHERE status = 'in_transit';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- units on the way between warehouses
+ALTER TABLE serials DROP CONSTRAINT IF EXISTS serials_status_check;
+ALTER TABLE serials ADD CONSTRAINT serials_status_check
+    CHECK (status IN ('in_stock', 'reserved', 'shipped', 'returned', 'in_transit'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE serials DROP CONSTRAINT IF EXISTS serials_status_check;
+ALTER TABLE serials ADD CONSTRAINT serials_status_check
+    CHECK (status IN ('in_stock', 'reserved', 'shipped', 'returned'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transfer_order_lines;
+DROP TABLE IF EXISTS transfer_orders;
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+-- scope is the whole warehouse or a location subtree, optionally
+-- narrowed to a category subtree
+CREATE TABLE IF NOT EXISTS stocktakes (
+    id SERIAL PRIMARY KEY,
+    warehouse_id INT NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    location_id INT REFERENCES locations(id) ON DELETE RESTRICT,
+    category_id INT REFERENCES categories(id) ON DELETE RESTRICT,
+    note TEXT NOT NULL DEFAULT '',
+    status VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'approved', 'cancelled')),
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    approved_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    approved_at TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- expected_quantity is frozen when stocktake starts, line without
+-- location stands for stock that is not put away
+CREATE TABLE IF NOT EXISTS stocktake_lines (
+    id SERIAL PRIMARY KEY,
+    stocktake_id INT NOT NULL REFERENCES stocktakes(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    location_id INT REFERENCES locations(id) ON DELETE RESTRICT,
+    expected_quantity NUMERIC(18, 3) NOT NULL,
+    counted_quantity NUMERIC(18, 3) CHECK (counted_quantity >= 0),
+    counted_by INT REFERENCES users(id) ON DELETE SET NULL,
+    counted_at TIMESTAMP,
+    movement_id BIGINT REFERENCES stock_movements(id) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stocktake_lines_item_location ON stocktake_lines(stocktake_id, item_id, COALESCE(location_id, 0));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- every count is kept, the latest one of a line is its counted quantity
+CREATE TABLE IF NOT EXISTS stocktake_counts (
+    id SERIAL PRIMARY KEY,
+    line_id INT NOT NULL REFERENCES stocktake_lines(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    quantity NUMERIC(18, 3) NOT NULL CHECK (quantity >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_stocktake_counts_line ON stocktake_counts(line_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stocktake_counts;
+DROP TABLE IF EXISTS stocktake_lines;
+DROP TABLE IF EXISTS stocktakes;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- change request keeps item change made by manager until admin
+-- approves it, changes hold request body, original holds values of
+-- changed fields when request was made and version is the item
+-- version it was made against, item_id is not a foreign key so
+-- that approved deletions are kept
+CREATE TABLE IF NOT EXISTS change_requests (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('update', 'delete', 'adjust')),
+    changes JSONB NOT NULL DEFAULT '{}',
+    original JSONB NOT NULL DEFAULT '{}',
+    version INT NOT NULL,
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_by INT REFERENCES users(id) ON DELETE SET NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_change_requests_status ON change_requests(status, item_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS change_requests;
+-- +goose StatementEnd
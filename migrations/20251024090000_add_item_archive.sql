@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- deleted items are archived instead of removed so that their
+-- history and movements stay, only purge removes them for good
+ALTER TABLE items
+    ADD COLUMN deleted_at TIMESTAMP,
+    ADD COLUMN deleted_by INT REFERENCES users(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_items_active ON items(id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_items_active;
+ALTER TABLE items DROP COLUMN IF EXISTS deleted_by, DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- bill of materials of kit item, quantity of component is given
+-- in its base unit for one unit of kit
+CREATE TABLE IF NOT EXISTS kit_components (
+    kit_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    component_id INT NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    quantity NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (kit_id, component_id),
+    CHECK (kit_id <> component_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_kit_components_component ON kit_components(component_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS kit_components;
+-- +goose StatementEnd
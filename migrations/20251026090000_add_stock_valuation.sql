@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+-- every movement is valued by both methods so that valuation method
+-- can be switched without rewriting the ledger, costs are signed like
+-- quantity, unit_cost is purchase cost of inbound movement if known
+ALTER TABLE stock_movements
+    ADD COLUMN unit_cost NUMERIC(18, 6) CHECK (unit_cost >= 0),
+    ADD COLUMN fifo_cost NUMERIC(18, 4),
+    ADD COLUMN average_cost NUMERIC(18, 4);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- value is weighted average value of warehouse stock
+ALTER TABLE stock ADD COLUMN value NUMERIC(18, 4) NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- every inbound movement opens layer that outbound movements
+-- consume oldest first, remaining_quantity is in item base unit
+CREATE TABLE IF NOT EXISTS cost_layers (
+    id BIGSERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    warehouse_id INT NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    movement_id BIGINT REFERENCES stock_movements(id) ON DELETE SET NULL,
+    remaining_quantity NUMERIC(18, 3) NOT NULL CHECK (remaining_quantity >= 0),
+    unit_cost NUMERIC(18, 6) NOT NULL CHECK (unit_cost >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_cost_layers_open ON cost_layers(item_id, warehouse_id, id) WHERE remaining_quantity > 0;
+-- +goose StatementEnd
+
+-- stock on hand has no known cost, it becomes layer of zero cost
+-- +goose StatementBegin
+INSERT INTO cost_layers(item_id, warehouse_id, remaining_quantity, unit_cost)
+SELECT item_id, warehouse_id, quantity, 0 FROM stock WHERE quantity > 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS cost_layers;
+ALTER TABLE stock DROP COLUMN IF EXISTS value;
+ALTER TABLE stock_movements
+    DROP COLUMN IF EXISTS average_cost,
+    DROP COLUMN IF EXISTS fifo_cost,
+    DROP COLUMN IF EXISTS unit_cost;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- tags are kept apart from items so that tagging does not
+-- bump item version or show up in change history
+CREATE TABLE IF NOT EXISTS item_tags (
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (item_id, tag)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_item_tags_tag ON item_tags(tag);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- filter holds item list filter the same way GET /items takes it
+CREATE TABLE IF NOT EXISTS saved_views (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS saved_views;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_tags;
+-- +goose StatementEnd
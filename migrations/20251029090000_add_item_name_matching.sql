@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- names that differ only in case or spacing are the same name
+CREATE OR REPLACE FUNCTION normalize_item_name(name TEXT)
+RETURNS TEXT AS $$
+  SELECT lower(btrim(regexp_replace(name, '\s+', ' ', 'g')));
+$$ LANGUAGE sql IMMUTABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- existing duplicates are left as they are, uniqueness is checked
+-- by the application in configured scope and duplicates are merged
+ALTER TABLE items
+    ADD COLUMN normalized_name TEXT GENERATED ALWAYS AS (normalize_item_name(name)) STORED,
+    ADD COLUMN merged_into INT REFERENCES items(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_items_normalized_name ON items(normalized_name);
+CREATE INDEX IF NOT EXISTS idx_items_normalized_name_trgm ON items USING GIN (normalized_name gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_items_normalized_name_trgm;
+DROP INDEX IF EXISTS idx_items_normalized_name;
+ALTER TABLE items DROP COLUMN IF EXISTS merged_into, DROP COLUMN IF EXISTS normalized_name;
+DROP FUNCTION IF EXISTS normalize_item_name(TEXT);
+-- +goose StatementEnd